@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/app"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/export"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+)
+
+func runCommand(name string, args []string) {
+	switch name {
+	case "backfill":
+		backfillCommand(args)
+	case "reconcile":
+		reconcileCommand(args)
+	case "discover":
+		discoverCommand()
+	case "export":
+		exportCommand(args)
+	case "import":
+		importCommand(args)
+	default:
+		log.Fatalf("❌ unknown command: %s", name)
+	}
+}
+
+// backfillCommand loads 1h klines from the given date up to now, resuming an interrupted run from its checkpoint
+func backfillCommand(args []string) {
+	flags := flag.NewFlagSet("backfill", flag.ExitOnError)
+	currency := flags.String("currency", "", "currency to backfill, all default currencies when empty")
+	from := flags.String("from", "", "start date, YYYY-MM-DD or RFC3339")
+	flags.Parse(args)
+
+	startTime, err := parseDate(*from)
+	if err != nil {
+		log.Fatalf("❌ invalid -from value %q: %v", *from, err)
+	}
+
+	currencies := instrument.TrackedKeys()
+	if *currency != "" {
+		currencies = []string{*currency}
+	}
+
+	// currencies are backfilled one after another to stay within the binance request weight limit
+	for _, curr := range currencies {
+		checkpoint, err := app.App.BackfillService.Backfill(curr, startTime)
+		if err != nil {
+			log.Fatalf("❌ backfill %s failed: %v", curr, err)
+		}
+		log.Printf("✅ backfill %s complete, last record %s", curr, checkpoint.LastTimestamp)
+	}
+}
+
+// importCommand loads the 1h kline dumps of data.binance.vision from a local directory
+func importCommand(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	currency := flags.String("currency", "btc", "currency to import, only the dumps of its binance symbol are loaded")
+	dir := flags.String("dir", "", "directory with the <SYMBOL>-1h-<date>.zip dumps and their .CHECKSUM files")
+	flags.Parse(args)
+
+	if *dir == "" {
+		log.Fatal("❌ -dir is required")
+	}
+	report, err := app.App.BackfillService.Import(*currency, *dir)
+	if err != nil {
+		log.Fatalf("❌ import %s failed: %v", *currency, err)
+	}
+	for _, skipped := range report.Skipped {
+		log.Printf("❌ skipped %s", skipped)
+	}
+	if report.From != nil {
+		log.Printf("✅ imported %s from %s to %s", *currency, report.From.Format(time.RFC3339), report.To.Format(time.RFC3339))
+	}
+	log.Printf("✅ import %s: %d dumps, %d records, %d already stored (%d differed), %d quarantined",
+		*currency, report.Files, report.Records, report.Overlapping, report.Conflicting, report.Quarantined)
+}
+
+// discoverCommand syncs binance exchangeInfo and prints the symbols discovery proposes, DISCOVERY_MODE=auto enables them
+func discoverCommand() {
+	count, err := app.App.SymbolService.Sync()
+	if err != nil {
+		log.Fatalf("❌ symbol sync failed: %v", err)
+	}
+	symbols, err := app.App.SymbolService.Discover()
+	if err != nil {
+		log.Fatalf("❌ discovery failed: %v", err)
+	}
+	for _, symbol := range symbols {
+		log.Printf("✅ %s %s/%s 24h quote volume %s enabled:%t", symbol.Symbol, symbol.BaseAsset, symbol.QuoteAsset, symbol.QuoteVolume.StringFixed(0), symbol.Enabled)
+	}
+	log.Printf("✅ synced %d symbols, %d discovered", count, len(symbols))
+}
+
+// reconcileCommand prints the comparison of candles built from aggregate trades with binance klines
+func reconcileCommand(args []string) {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	currency := flags.String("currency", "btc", "currency to reconcile")
+	from := flags.String("from", "", "start date, YYYY-MM-DD or RFC3339")
+	to := flags.String("to", "", "end date (exclusive), YYYY-MM-DD or RFC3339, the current hour when empty")
+	flags.Parse(args)
+
+	startTime, err := parseDate(*from)
+	if err != nil {
+		log.Fatalf("❌ invalid -from value %q: %v", *from, err)
+	}
+	endTime := clock.Now().Truncate(time.Hour)
+	if *to != "" {
+		if endTime, err = parseDate(*to); err != nil {
+			log.Fatalf("❌ invalid -to value %q: %v", *to, err)
+		}
+	}
+
+	report, err := app.App.TradeService.Reconcile(*currency, startTime, endTime)
+	if err != nil {
+		log.Fatalf("❌ reconcile %s failed: %v", *currency, err)
+	}
+	for _, row := range report.Rows {
+		if len(row.Mismatches) > 0 {
+			log.Printf("❌ %s %s", row.Timestamp.Format(time.RFC3339), strings.Join(row.Mismatches, ", "))
+		}
+	}
+	log.Printf("✅ reconcile %s: %d hours match, %d hours differ", *currency, report.MatchedHours, report.MismatchedHours)
+}
+
+// exportCommand writes the candles of a currency and timeframe joined with their indicators to a CSV, NDJSON or parquet file
+func exportCommand(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	currency := flags.String("currency", "btc", "currency to export")
+	timeframe := flags.String("timeframe", config.ONE_HOUR, "timeframe to export, e.g. 1h, 4h, 1d or 1h_composite")
+	from := flags.String("from", "", "start date, YYYY-MM-DD or RFC3339, the first record when empty")
+	to := flags.String("to", "", "end date (inclusive), YYYY-MM-DD or RFC3339, the last record when empty")
+	format := flags.String("format", config.EXPORT_FORMAT_CSV, "csv, ndjson or parquet")
+	columns := flags.String("columns", "", "comma separated columns, all when empty: "+strings.Join(export.ColumnNames(), ","))
+	timeFormat := flags.String("time-format", config.EXPORT_TIME_FORMAT_ISO, "iso (RFC3339 in UTC), unix or unix_ms, parquet always stores timestamps")
+	out := flags.String("out", "", "output file, <currency>_<timeframe>.<format> when empty")
+	flags.Parse(args)
+
+	request := dto.ExportRequestDto{Currency: *currency, Timeframe: *timeframe, Format: *format, TimeFormat: *timeFormat}
+	if *columns != "" {
+		request.Columns = strings.Split(*columns, ",")
+	}
+	if *from != "" {
+		startTime, err := parseDate(*from)
+		if err != nil {
+			log.Fatalf("❌ invalid -from value %q: %v", *from, err)
+		}
+		request.StartTime = &startTime
+	}
+	if *to != "" {
+		endTime, err := parseDate(*to)
+		if err != nil {
+			log.Fatalf("❌ invalid -to value %q: %v", *to, err)
+		}
+		request.EndTime = &endTime
+	}
+	if *out == "" {
+		*out = fmt.Sprintf("%s_%s.%s", *currency, *timeframe, *format)
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("❌ cannot create %s: %v", *out, err)
+	}
+	defer file.Close()
+
+	count, err := app.App.ExportService.Export(context.Background(), request, file)
+	if err != nil {
+		log.Fatalf("❌ export %s %s failed: %v", *currency, *timeframe, err)
+	}
+	log.Printf("✅ exported %d rows of %s %s to %s", count, *currency, *timeframe, *out)
+}
+
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.ParseInLocation(time.DateOnly, value, time.UTC); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
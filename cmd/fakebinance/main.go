@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/fakebinance"
+)
+
+// a stand-in for the binance spot API, ex: go run ./cmd/fakebinance -addr=:9090 -faults=klines:429:1:2,*:5xx:3
+func main() {
+	addr := flag.String("addr", ":9090", "address to listen on")
+	seed := flag.Int64("seed", 1, "seed of the generated prices")
+	fixtures := flag.String("fixtures", "", "directory of fixture files served instead of generated data, ex: tests/fixtures/binance")
+	symbols := flag.String("symbols", "BTCUSDT,ETHUSDT", "symbols listed by exchangeInfo and ticker/24hr")
+	faults := flag.String("faults", "", "fault script of endpoint:kind[:times[:parameter]] entries, kinds: 429, 5xx, slow, truncated")
+	missing := flag.String("missing", "", "comma separated RFC3339 hours left out of klines and the stream")
+	offset := flag.Duration("offset", 0, "offset of the server clock, ex: 1500ms")
+	flag.Parse()
+
+	faultScript, err := fakebinance.ParseFaults(*faults)
+	if err != nil {
+		log.Fatalf("❌ invalid -faults value: %v", err)
+	}
+	var missingHours []time.Time
+	for _, value := range strings.Split(*missing, ",") {
+		if value == "" {
+			continue
+		}
+		hour, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			log.Fatalf("❌ invalid -missing value %q: %v", value, err)
+		}
+		missingHours = append(missingHours, hour)
+	}
+
+	server := fakebinance.NewServer(fakebinance.Options{
+		Seed:         *seed,
+		FixturesDir:  *fixtures,
+		Symbols:      strings.Split(*symbols, ","),
+		Faults:       faultScript,
+		MissingHours: missingHours,
+		ClockOffset:  *offset,
+	})
+	log.Printf("✅ fake binance listening on %s, set BINANCE_BASE_API_URL=http://localhost%s/api/v3/ and BINANCE_STREAM_URL=ws://localhost%s/ws", *addr, *addr, *addr)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
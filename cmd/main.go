@@ -3,27 +3,40 @@ package main
 import (
 	"fmt"
 	"log"
-	"sync"
+	"os"
 	"time"
 
-	"github.com/chyngyz-sydykov/marketpulse/config"
 	"github.com/chyngyz-sydykov/marketpulse/internal/app"
 	"github.com/chyngyz-sydykov/marketpulse/internal/app/scheduler"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/grpc"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/metrics"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
 )
 
 func main() {
 
 	fmt.Println("MarketPulse is running...")
+	if err := binance.CheckFixtures(); err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	// STORAGE_BACKEND only covers candles, indicators and quarantine, depth, futures, ticker and the rest still live in postgres
 	err := database.ConnectDB()
 	if err != nil {
 		log.Fatal("❌ Failed to connect to database:", err)
 	}
 	defer database.DB.Close()
 
+	// the migrations only cover the default currencies, tables of pairs from INSTRUMENTS are created here
+	for _, key := range instrument.TrackedKeys() {
+		if err := database.EnsureInstrumentTables(key); err != nil {
+			log.Fatal("❌ Failed to provision instrument tables:", err)
+		}
+	}
+
 	err = redis.ConnectRedis()
 	if err != nil {
 		log.Fatal("❌ Failed to connect to redis:", err)
@@ -32,14 +45,33 @@ func main() {
 
 	app.NewContainer()
 
+	// the currencies added at runtime replace the default ones
+	if err := app.App.CurrencyService.Load(); err != nil {
+		log.Printf("❌ Failed to load currencies: %v", err)
+	}
+
+	// track the symbols enabled by discovery and skip the ones binance halted or delisted
+	if err := app.App.SymbolService.Load(); err != nil {
+		log.Printf("❌ Failed to load binance symbols: %v", err)
+	}
+
+	// Run a one-off subcommand instead of the server, ex: go run ./cmd backfill -currency=btc -from=2024-01-01
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
 	// Start the scheduler to fetch market data every hour
 	scheduler.StartScheduler()
 
+	// Ingest live 1h candles from the Binance WebSocket stream (BINANCE_STREAM_ENABLED)
+	scheduler.StartKlineStream()
+
 	app.App.EventListener.Listen()
 
 	go grpc.StartGRPCServer(app.App.GrpcServer)
 
-	//getAlotOfData()
+	go metrics.StartMetricsServer()
 
 	// for _, currency := range config.DefaultCurrencies {
 	// 	go func(curr string) {
@@ -72,32 +104,3 @@ func main() {
 		time.Sleep(1 * time.Hour)
 	}
 }
-
-func getAlotOfData() {
-	startTime := time.Now() // Start timing execution
-	log.Println("get a lot of data from binance...")
-	var wg sync.WaitGroup
-	for _, currency := range config.DefaultCurrencies {
-		wg.Add(1)
-		go func(curr string) {
-
-			defer wg.Done()
-
-			records, err := binance.FetchKline(curr, config.ONE_HOUR, 1000)
-			if err != nil {
-				log.Printf("Error fetching data for %s: %v\n", curr, err)
-				return
-			}
-
-			err = app.App.MarketDataService.UpsertBatchData(curr, records)
-			if err != nil {
-				log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
-				return
-			}
-
-		}(currency)
-	}
-	wg.Wait()
-	totalDuration := time.Since(startTime) // Measure total execution time
-	log.Printf("🚀 time: %v\n", totalDuration)
-}
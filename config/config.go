@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -12,17 +14,30 @@ var APPLICATION_NAME = getEnv("APPLICATION_PORT", "MarketPulse")
 // var DefaultCurrencies = []string{"btc", "eth", "bnb", "sol", "trump"}
 var DefaultCurrencies = []string{"btc"}
 var DefaultTimeframes = []string{"1h", "4h", "1d"}
+
+// composite timeframes are partitions holding candles consolidated across COMPOSITE_SOURCES, e.g. data_btc_1h_composite
+var CompositeTimeframes = []string{"1h_composite", "4h_composite", "1d_composite"}
 var HoursByTimeframe = map[string]int{
 	"1h":  1,
 	"4h":  4,
 	"1d":  24,
 	"7d":  168,
 	"30d": 30 * 24, // TODO assuming 1 month is 30 days
+
+	"1h_composite": 1,
+	"4h_composite": 4,
+	"1d_composite": 24,
 }
 
 var EVENT_NEW_DATA_ADDED = "NewDataAdded"
 var EVENT_NEW_GROUP_DATA_ADDED = "NewGroupDataAdded"
 var EVENT_NEW_INDICATOR_ADDED = "NewIndicatorAdded"
+var EVENT_NEW_DEPTH_ADDED = "NewDepthAdded"
+var EVENT_NEW_COMPOSITE_DATA_ADDED = "NewCompositeDataAdded"
+var EVENT_NEW_FUTURES_DATA_ADDED = "NewFuturesDataAdded"
+var EVENT_NEW_TICKER_ADDED = "NewTickerAdded"
+var EVENT_CIRCUIT_BREAKER_OPENED = "CircuitBreakerOpened"
+var EVENT_CIRCUIT_BREAKER_CLOSED = "CircuitBreakerClosed"
 
 var ONE_HOUR = "1h"
 var FOUR_HOUR = "4h"
@@ -30,6 +45,41 @@ var ONE_DAY = "1d"
 var SEVEN_DAY = "7d"
 var THIRTY_DAY = "30d"
 
+var EXCHANGE_BINANCE = "binance"
+var EXCHANGE_COINBASE = "coinbase"
+var EXCHANGE_KRAKEN = "kraken"
+var EXCHANGE_OKX = "okx"
+var EXCHANGE_BINANCE_FUTURES = "binance_futures"
+
+var SOURCE_COMPOSITE = "composite"
+var COMPOSITE_SUFFIX = "_composite"
+
+var SYMBOL_STATUS_TRADING = "TRADING"
+
+var STORAGE_BACKEND_POSTGRES = "postgres"
+var STORAGE_BACKEND_MEMORY = "memory"
+var STORAGE_BACKEND_TIMESCALE = "timescale"
+
+var EXPORT_FORMAT_CSV = "csv"
+var EXPORT_FORMAT_NDJSON = "ndjson"
+var EXPORT_FORMAT_PARQUET = "parquet"
+
+var EXPORT_TIME_FORMAT_ISO = "iso"
+var EXPORT_TIME_FORMAT_UNIX = "unix"
+var EXPORT_TIME_FORMAT_UNIX_MS = "unix_ms"
+
+var CURRENCY_STATUS_ACTIVE = "active"
+var CURRENCY_STATUS_PAUSED = "paused"
+
+var DISCOVERY_MODE_OFF = "off"
+var DISCOVERY_MODE_PROPOSE = "propose"
+var DISCOVERY_MODE_AUTO = "auto"
+
+var BINANCE_MODE_LIVE = "live"
+var BINANCE_MODE_RECORD = "record"
+var BINANCE_MODE_REPLAY = "replay"
+var BINANCE_MODE_MOCK = "mock"
+
 var DEFAULT_LIMIT = 100
 var DEFAULT_DATA_REQUEST_SORT_FIELD = "timestamp"
 var DEFAULT_DATA_REQUEST_SORT_ORDER = "DESC"
@@ -54,12 +104,71 @@ type Config struct {
 	DBName            string
 	DBUser            string
 	DBPassword        string
-	MockBinance       bool
 	BinanceBaseAPIUrl string
 	RedisHost         string
 	RedisPort         string
 	RedisPassword     string
 	GrpcPort          string
+
+	StorageBackend             string // postgres, memory or timescale, where candles and indicators are stored
+	TimescaleChunkDays         int    // days of 1h candles per hypertable chunk
+	TimescaleCompressAfterDays int    // age in days of the chunks that are compressed
+
+	BinanceMode                 string // live, record, replay or mock
+	BinanceFixturesDir          string
+	BinanceStreamEnabled        bool
+	BinanceStreamUrl            string
+	BinanceStreamUpsertInterval int // seconds between upserts of a forming candle
+	BinanceWeightLimit          int // request weight per minute allowed by binance
+	BinanceFuturesWeightLimit   int // request weight per minute allowed by the binance futures API
+	BinanceWeightThreshold      int // percent of the weight limit after which requests are queued
+
+	HttpRetries               int // retries of a failed exchange call
+	HttpRetryBaseDelay        int // milliseconds, doubled on every retry
+	HttpRetryMaxDelay         int // milliseconds
+	HttpRequestTimeout        int // seconds per attempt
+	CircuitBreakerThreshold   int // consecutive failures after which the exchange is not called
+	CircuitBreakerOpenSeconds int // seconds before a trial call is let through
+	FailedFetchRetryCron      string
+
+	GapScanCron    string
+	GapMaxAttempts int // scans that try to fill a gap before it is left alone
+
+	HourlyFetchDelay int // seconds after the hour the closed 1h candles are fetched
+	ClockSyncCron    string
+
+	Instruments []string // pairs tracked besides the default currencies, e.g. "eth/btc,btc/eur,sol/usdc"
+
+	TradeCandleCurrencies []string // currencies whose 1h candles are built from aggregate trades instead of klines
+
+	DepthSnapshotCron string
+	DepthLimit        int // order book levels per depth snapshot
+
+	TickerCron string
+
+	QualityMaxSigma int // standard deviations of the hourly returns a close may jump before the candle is quarantined, 0 disables the check
+	QualityWindow   int // previous returns the standard deviation is computed from
+
+	SymbolSyncCron string
+	DiscoveryMode  string // off, propose or auto
+	DiscoveryTopN  int    // symbols ranked by 24h quote volume that are proposed or enabled
+	DiscoveryQuote string // quote asset of the discovered symbols
+
+	FuturesCurrencies        []string // currencies whose USDⓈ-M perpetual funding, open interest and mark price are ingested
+	FuturesCron              string
+	BinanceFuturesBaseAPIUrl string
+
+	CompositeSources      []string // exchanges consolidated into composite candles, disabled when empty
+	CompositeMaxDeviation int      // basis points a venue close may deviate from the median before it is ignored
+
+	RetentionPolicies map[string]string // timeframe => how long its candles and indicators are kept, e.g. 730d, forever when not listed
+	RetentionCron     string
+	ArchiveDir        string // expired rows are archived there as parquet files before they are deleted
+
+	MarketDataSources  map[string]string // currency => exchange, binance when not listed
+	CoinbaseBaseAPIUrl string
+	KrakenBaseAPIUrl   string
+	OkxBaseAPIUrl      string
 }
 
 // LoadConfig reads from .env and loads it into Config struct
@@ -77,15 +186,82 @@ func LoadConfig() *Config {
 		DBName:            getEnv("DB_DATABASE", "db"),
 		DBUser:            getEnv("DB_USERNAME", "postgres"),
 		DBPassword:        getEnv("DB_PASSWORD", "password"),
-		MockBinance:       os.Getenv("MOCK_BINANCE") == "true",
 		BinanceBaseAPIUrl: getEnv("BINANCE_BASE_API_URL", "https://api.binance.com/api/v3/"),
 		RedisHost:         getEnv("REDIS_HOST", "localhost"),
 		RedisPort:         getEnv("REDIS_PORT", "6379"),
 		RedisPassword:     getEnv("REDIS_PASSWORD", ""),
 		GrpcPort:          getEnv("GRPC_PORT", "50051"),
+
+		StorageBackend:             getEnv("STORAGE_BACKEND", STORAGE_BACKEND_POSTGRES),
+		TimescaleChunkDays:         getEnvAsInt("TIMESCALE_CHUNK_DAYS", 30),
+		TimescaleCompressAfterDays: getEnvAsInt("TIMESCALE_COMPRESS_AFTER_DAYS", 30),
+
+		BinanceMode:                 getBinanceMode(),
+		BinanceFixturesDir:          getEnv("BINANCE_FIXTURES_DIR", "fixtures/binance"),
+		BinanceStreamEnabled:        os.Getenv("BINANCE_STREAM_ENABLED") == "true",
+		BinanceStreamUrl:            getEnv("BINANCE_STREAM_URL", "wss://stream.binance.com:9443/ws"),
+		BinanceStreamUpsertInterval: getEnvAsInt("BINANCE_STREAM_UPSERT_INTERVAL", 60),
+		BinanceWeightLimit:          getEnvAsInt("BINANCE_WEIGHT_LIMIT", 6000),
+		BinanceFuturesWeightLimit:   getEnvAsInt("BINANCE_FUTURES_WEIGHT_LIMIT", 2400),
+		BinanceWeightThreshold:      getEnvAsInt("BINANCE_WEIGHT_THRESHOLD", 90),
+
+		HttpRetries:               getEnvAsInt("HTTP_RETRIES", 3),
+		HttpRetryBaseDelay:        getEnvAsInt("HTTP_RETRY_BASE_DELAY", 500),
+		HttpRetryMaxDelay:         getEnvAsInt("HTTP_RETRY_MAX_DELAY", 10000),
+		HttpRequestTimeout:        getEnvAsInt("HTTP_REQUEST_TIMEOUT", 10),
+		CircuitBreakerThreshold:   getEnvAsInt("CIRCUIT_BREAKER_THRESHOLD", 5),
+		CircuitBreakerOpenSeconds: getEnvAsInt("CIRCUIT_BREAKER_OPEN_SECONDS", 60),
+		FailedFetchRetryCron:      getEnv("FAILED_FETCH_RETRY_CRON", "*/5 * * * *"),
+
+		GapScanCron:    getEnv("GAP_SCAN_CRON", "15 * * * *"),
+		GapMaxAttempts: getEnvAsInt("GAP_MAX_ATTEMPTS", 24),
+
+		HourlyFetchDelay: getEnvAsInt("HOURLY_FETCH_DELAY", 5),
+		ClockSyncCron:    getEnv("CLOCK_SYNC_CRON", "*/10 * * * *"),
+
+		Instruments: getEnvAsSlice("INSTRUMENTS"),
+
+		TradeCandleCurrencies: getEnvAsSlice("TRADE_CANDLE_CURRENCIES"),
+
+		DepthSnapshotCron: getEnv("DEPTH_SNAPSHOT_CRON", "* * * * *"),
+		DepthLimit:        getEnvAsInt("DEPTH_LIMIT", 1000),
+
+		TickerCron: getEnv("TICKER_CRON", "* * * * *"),
+
+		QualityMaxSigma: getEnvAsInt("QUALITY_MAX_SIGMA", 10),
+		QualityWindow:   getEnvAsInt("QUALITY_WINDOW", 100),
+
+		SymbolSyncCron: getEnv("SYMBOL_SYNC_CRON", "30 0 * * *"),
+		DiscoveryMode:  getEnv("DISCOVERY_MODE", DISCOVERY_MODE_OFF),
+		DiscoveryTopN:  getEnvAsInt("DISCOVERY_TOP_N", 10),
+		DiscoveryQuote: getEnv("DISCOVERY_QUOTE", "usdt"),
+
+		FuturesCurrencies:        getEnvAsSlice("FUTURES_CURRENCIES"),
+		FuturesCron:              getEnv("FUTURES_CRON", "2 * * * *"),
+		BinanceFuturesBaseAPIUrl: getEnv("BINANCE_FUTURES_BASE_API_URL", "https://fapi.binance.com/fapi/v1/"),
+
+		CompositeSources:      getEnvAsSlice("COMPOSITE_SOURCES"),
+		CompositeMaxDeviation: getEnvAsInt("COMPOSITE_MAX_DEVIATION", 100),
+
+		RetentionPolicies: getEnvAsMap("RETENTION_POLICIES"),
+		RetentionCron:     getEnv("RETENTION_CRON", "30 3 * * *"),
+		ArchiveDir:        getEnv("ARCHIVE_DIR", "archive"),
+
+		MarketDataSources:  getEnvAsMap("MARKET_DATA_SOURCES"),
+		CoinbaseBaseAPIUrl: getEnv("COINBASE_BASE_API_URL", "https://api.exchange.coinbase.com/"),
+		KrakenBaseAPIUrl:   getEnv("KRAKEN_BASE_API_URL", "https://api.kraken.com/0/public/"),
+		OkxBaseAPIUrl:      getEnv("OKX_BASE_API_URL", "https://www.okx.com/api/v5/"),
 	}
 }
 
+// getBinanceMode reads BINANCE_MODE, MOCK_BINANCE=true is kept as an alias of mock
+func getBinanceMode() string {
+	if os.Getenv("MOCK_BINANCE") == "true" {
+		return getEnv("BINANCE_MODE", BINANCE_MODE_MOCK)
+	}
+	return getEnv("BINANCE_MODE", BINANCE_MODE_LIVE)
+}
+
 // getEnv retrieves the environment variable or returns a default value
 func getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -94,6 +270,42 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getEnvAsInt retrieves the environment variable as int or returns a default value
+func getEnvAsInt(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+// getEnvAsMap parses a "key:value,key:value" environment variable, ex: MARKET_DATA_SOURCES=btc:binance,eth:okx
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if found {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// getEnvAsSlice parses a comma separated list, e.g. "btc,eth"
+func getEnvAsSlice(key string) []string {
+	var result []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
 func loadEnvFile() error {
 	rootDir := os.Getenv("ROOT_DIR")
 	envFileName := rootDir + "/.env"
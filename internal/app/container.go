@@ -2,8 +2,19 @@ package app
 
 import (
 	"github.com/chyngyz-sydykov/marketpulse/internal/app/event"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/backfill"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/composite"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/currency"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/export"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/futures"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/gap"
 	indicator "github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/orderbook"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/retention"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/symbol"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/ticker"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/trade"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/grpc"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
 )
@@ -15,6 +26,17 @@ type Container struct {
 	RedisService      redis.RedisServiceInterface
 	MarketDataService *marketdata.MarketDataService
 	IndicatorService  *indicator.IndicatorService
+	BackfillService   *backfill.BackfillService
+	GapService        *gap.GapService
+	RetentionService  *retention.RetentionService
+	OrderBookService  *orderbook.OrderBookService
+	TradeService      *trade.TradeService
+	CompositeService  *composite.CompositeService
+	FuturesService    *futures.FuturesService
+	TickerService     *ticker.TickerService
+	SymbolService     *symbol.SymbolService
+	CurrencyService   *currency.CurrencyService
+	ExportService     *export.ExportService
 	EventListener     *event.EventListener
 	GrpcServer        *grpc.GrpcServer
 }
@@ -24,11 +46,23 @@ func NewContainer() *Container {
 	redisService := redis.NewRedisService(redis.Redis)
 	marketDataService := marketdata.NewMarketDataService(redisService)
 	indicatorService := indicator.NewIndicatorService(redisService)
-	GrpcServcer := grpc.NewGrpcService(marketDataService, indicatorService)
+	backfillService := backfill.NewBackfillService(marketDataService, indicatorService)
+	gapService := gap.NewGapService(marketDataService, indicatorService)
+	retentionService := retention.NewRetentionService(marketDataService, indicatorService)
+	orderBookService := orderbook.NewOrderBookService(redisService)
+	tradeService := trade.NewTradeService(marketDataService)
+	compositeService := composite.NewCompositeService(marketDataService, redisService)
+	futuresService := futures.NewFuturesService(redisService)
+	tickerService := ticker.NewTickerService(redisService)
+	symbolService := symbol.NewSymbolService()
+	currencyService := currency.NewCurrencyService()
+	exportService := export.NewExportService()
+	GrpcServcer := grpc.NewGrpcService(marketDataService, indicatorService, backfillService, gapService, orderBookService, tradeService, futuresService, tickerService, currencyService, exportService)
 
 	EventListener := event.NewEventListener(
 		marketDataService,
 		indicatorService,
+		orderBookService,
 		redisService,
 	)
 
@@ -36,6 +70,17 @@ func NewContainer() *Container {
 		RedisService:      redisService,
 		MarketDataService: marketDataService,
 		IndicatorService:  indicatorService,
+		BackfillService:   backfillService,
+		GapService:        gapService,
+		RetentionService:  retentionService,
+		OrderBookService:  orderBookService,
+		TradeService:      tradeService,
+		CompositeService:  compositeService,
+		FuturesService:    futuresService,
+		TickerService:     tickerService,
+		SymbolService:     symbolService,
+		CurrencyService:   currencyService,
+		ExportService:     exportService,
 		EventListener:     EventListener,
 		GrpcServer:        GrpcServcer,
 	}
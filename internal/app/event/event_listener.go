@@ -8,22 +8,28 @@ import (
 	"github.com/chyngyz-sydykov/marketpulse/config"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/orderbook"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
 )
 
 type EventListener struct {
 	MarketDataService *marketdata.MarketDataService
 	IndicatorService  *indicator.IndicatorService
+	OrderBookService  *orderbook.OrderBookService
 	RedisService      redis.RedisServiceInterface
 }
 
 func NewEventListener(
 	marketDataService *marketdata.MarketDataService,
 	inidicatorService *indicator.IndicatorService,
+	orderBookService *orderbook.OrderBookService,
 	redis redis.RedisServiceInterface) *EventListener {
 	return &EventListener{
 		MarketDataService: marketDataService,
 		IndicatorService:  inidicatorService,
+		OrderBookService:  orderBookService,
 		RedisService:      redis,
 	}
 }
@@ -42,6 +48,12 @@ func (el *EventListener) Listen() {
 		if err != nil {
 			log.Printf("Error storing 1D records for %s: %v", currency, err)
 		}
+
+		// every new candle gets the order book depth of the moment it was stored, the depth cron shares the per minute snapshot
+		_, err = el.OrderBookService.CollectSnapshot(currency)
+		if err != nil {
+			log.Printf("Error collecting depth snapshot for %s: %v", currency, err)
+		}
 	})
 	el.subscribeToEvent(ctx, config.EVENT_NEW_GROUP_DATA_ADDED, func(currency, eventName, eventSource string) {
 		err := el.IndicatorService.ComputeAndUpsertBatch(currency, config.FOUR_HOUR)
@@ -54,16 +66,38 @@ func (el *EventListener) Listen() {
 			log.Printf("Error Indicator for 1D records %s: %v", currency, err)
 		}
 	})
+	el.subscribeToEvent(ctx, config.EVENT_NEW_COMPOSITE_DATA_ADDED, func(currency, eventName, eventSource string) {
+		// composite candles are grouped and get indicators like the candles of a single exchange
+		for _, timeframe := range []string{config.FOUR_HOUR, config.ONE_DAY} {
+			compositeTimeframe := utils.GetCompositeTimeframe(timeframe)
+			err := el.MarketDataService.StoreGroupedRecords(currency, compositeTimeframe)
+			if err != nil {
+				log.Printf("Error storing %s records for %s: %v", compositeTimeframe, currency, err)
+				continue
+			}
+
+			err = el.IndicatorService.ComputeAndUpsertBatch(currency, compositeTimeframe)
+			if err != nil {
+				log.Printf("Error Indicator for %s records %s: %v", compositeTimeframe, currency, err)
+			}
+		}
+	})
 	el.subscribeToEvent(ctx, config.EVENT_NEW_INDICATOR_ADDED, func(currency, eventName, source string) {
 		log.Printf("EVENT_NEW_INDICATOR_ADDED")
 	})
+	el.subscribeToEvent(ctx, config.EVENT_NEW_DEPTH_ADDED, func(currency, eventName, source string) {
+		log.Printf("EVENT_NEW_DEPTH_ADDED")
+	})
+	el.subscribeToEvent(ctx, config.EVENT_NEW_FUTURES_DATA_ADDED, func(currency, eventName, source string) {
+		log.Printf("EVENT_NEW_FUTURES_DATA_ADDED")
+	})
 }
 func (el *EventListener) subscribeToEvent(ctx context.Context, eventName string, callback func(curr, eventName, eventSource string)) {
 	go el.RedisService.SubscribeToEvent(ctx, eventName, func(event redis.Event) {
 		log.Printf("Event received: %s from %s", event.Name, event.Source)
 
 		var wg sync.WaitGroup
-		for _, currency := range config.DefaultCurrencies {
+		for _, currency := range instrument.TrackedKeys() {
 			wg.Add(1)
 			go func(curr string) {
 				defer wg.Done()
@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/app"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/exchange"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/resilience"
+)
+
+// failedHours keeps the hours the hourly fetch could not get, per currency
+var failedHours = newRetryQueue()
+
+type retryQueue struct {
+	mu    sync.Mutex
+	hours map[string]map[time.Time]struct{}
+}
+
+func newRetryQueue() *retryQueue {
+	return &retryQueue{hours: make(map[string]map[time.Time]struct{})}
+}
+
+func (queue *retryQueue) add(currency string, hour time.Time) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	if queue.hours[currency] == nil {
+		queue.hours[currency] = make(map[time.Time]struct{})
+	}
+	queue.hours[currency][hour.UTC().Truncate(time.Hour)] = struct{}{}
+}
+
+// take removes and returns the queued hours of the currency, oldest first
+func (queue *retryQueue) take(currency string) []time.Time {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	hours := make([]time.Time, 0, len(queue.hours[currency]))
+	for hour := range queue.hours[currency] {
+		hours = append(hours, hour)
+	}
+	delete(queue.hours, currency)
+	sort.Slice(hours, func(i, j int) bool { return hours[i].Before(hours[j]) })
+	return hours
+}
+
+func (queue *retryQueue) currencies() []string {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	currencies := make([]string, 0, len(queue.hours))
+	for currency := range queue.hours {
+		currencies = append(currencies, currency)
+	}
+	return currencies
+}
+
+// retryFailedHours fetches the queued hours again, hours that still fail stay in the queue
+func retryFailedHours() {
+	currencies := failedHours.currencies()
+	if len(currencies) == 0 {
+		return
+	}
+	log.Println("retrying failed hours...")
+	executeForCurrencies(tradableCurrencies(currencies), func(currency string) {
+		hours := failedHours.take(currency)
+		source, err := exchange.GetSourceForCurrency(currency)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+			return
+		}
+		tradeBuilt := slices.Contains(tradeCandleCurrencies(), currency)
+		for i, hour := range hours {
+			if tradeBuilt {
+				_, err = app.App.TradeService.IngestHours(currency, hour, hour.Add(time.Hour))
+			} else {
+				var records []*dto.DataDto
+				records, err = source.FetchKlineRange(currency, config.ONE_HOUR, hour, hour, 1)
+				if err == nil && len(records) == 0 {
+					err = fmt.Errorf("no kline returned for %s", hour.Format(time.RFC3339))
+				}
+				if err == nil {
+					records, err = app.App.MarketDataService.ScreenData(currency, records[:1])
+				}
+				if err == nil && len(records) > 0 {
+					err = app.App.MarketDataService.UpsertBatchData(currency, records)
+				}
+			}
+			if err != nil {
+				log.Printf("Error retrying %s %s: %v\n", currency, hour.Format(time.RFC3339), err)
+				// the exchange is still failing or has not published the hour yet, keep this and the remaining hours for the next run
+				for _, remaining := range hours[i:] {
+					failedHours.add(currency, remaining)
+				}
+				return
+			}
+			log.Printf(config.COLOR_BLUE+"recovered %s %s"+config.COLOR_RESET, currency, hour.Format(time.RFC3339))
+		}
+	})
+}
+
+// watchCircuitBreakers publishes an event when an exchange breaker opens or closes
+// and retries the failed hours as soon as the exchange is back
+func watchCircuitBreakers() {
+	resilience.SetStateChangeListener(func(name string, state resilience.State) {
+		eventName := config.EVENT_CIRCUIT_BREAKER_OPENED
+		if state == resilience.StateClosed {
+			eventName = config.EVENT_CIRCUIT_BREAKER_CLOSED
+			go retryFailedHours()
+		}
+		err := app.App.RedisService.PublishEvent(context.Background(), eventName, name)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		}
+	})
+}
@@ -1,35 +1,36 @@
 package scheduler
 
 import (
+	"fmt"
 	"log"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/chyngyz-sydykov/marketpulse/config"
 	"github.com/chyngyz-sydykov/marketpulse/internal/app"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/composite"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/exchange"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
 
 	"github.com/go-co-op/gocron"
 )
 
 func StartScheduler() {
+	cfg := config.LoadConfig()
 	scheduler := gocron.NewScheduler(time.UTC)
 
-	scheduler.Every(1).Hour().Do(func() {
-		log.Println("hourly scheduler...")
-		executeForAllCurrencies(func(currency string) {
-			records, err := binance.FetchKline(currency, config.ONE_HOUR, 1)
-			if err != nil {
-				log.Printf("Error fetching data for %s: %v\n", currency, err)
-				return
-			}
-			err = app.App.MarketDataService.StoreData(currency, records[0])
-			//err = app.App.MarketDataService.UpsertBatchData(currency, records)
-			if err != nil {
-				log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
-			}
-		})
-	})
+	watchCircuitBreakers()
+	// sync with the binance server time on startup and then by CLOCK_SYNC_CRON (every 10 minutes by default)
+	syncClock()
+	scheduler.Cron(cfg.ClockSyncCron).Do(syncClock)
+	// currencies streamed by StartKlineStream are not polled
+	scheduleHourlyFetch()
+	// hours the hourly fetch failed to get are retried by FAILED_FETCH_RETRY_CRON (every 5 minutes by default)
+	scheduler.Cron(cfg.FailedFetchRetryCron).Do(retryFailedHours)
 	// run every 4 hours at 5 minutes past the hour (00:05, 04:05, 08:05, etc.)
 	scheduler.Cron("5 */4 * * *").Do(func() {
 		log.Println("4 hour scheduler...")
@@ -46,6 +47,21 @@ func StartScheduler() {
 		})
 
 	})
+	// collect order book depth snapshots by DEPTH_SNAPSHOT_CRON (every minute by default)
+	scheduler.Cron(cfg.DepthSnapshotCron).Do(collectDepthSnapshots)
+	// collect 24h ticker snapshots by TICKER_CRON (every minute by default)
+	scheduler.Cron(cfg.TickerCron).Do(collectTickers)
+	// ingest perpetual funding rates, mark prices and open interest by FUTURES_CRON (every hour at 2 minutes past by default)
+	scheduler.Cron(cfg.FuturesCron).Do(ingestFutures)
+	// sync binance exchangeInfo on startup and then by SYMBOL_SYNC_CRON (every day at 00:30 by default)
+	go syncSymbols()
+	scheduler.Cron(cfg.SymbolSyncCron).Do(syncSymbols)
+	// scan for missing 1h records on startup and then by GAP_SCAN_CRON (every hour at 15 minutes past by default)
+	go scanGaps()
+	scheduler.Cron(cfg.GapScanCron).Do(scanGaps)
+	// archive and delete the rows expired by RETENTION_POLICIES by RETENTION_CRON (every day at 03:30 by default)
+	scheduler.Cron(cfg.RetentionCron).Do(applyRetention)
+
 	// run every day at 00:10
 	// scheduler.Cron("10 0 * * *").Do(func() {
 	// 	log.Println("daily scheduler...")
@@ -60,9 +76,176 @@ func StartScheduler() {
 
 	scheduler.StartAsync()
 }
+
+// scheduleHourlyFetch fetches the 1h candle that just closed HOURLY_FETCH_DELAY seconds after every hour of the binance clock
+func scheduleHourlyFetch() {
+	delay := time.Duration(config.LoadConfig().HourlyFetchDelay) * time.Second
+	clock.Schedule(time.Hour, delay, fetchClosedHour)
+}
+
+func fetchClosedHour(hour time.Time) {
+	log.Println("hourly scheduler...")
+	closed := hour.Add(-time.Hour)
+	executeForCurrencies(tradableCurrencies(polledCurrencies()), func(currency string) {
+		source, err := exchange.GetSourceForCurrency(currency)
+		if err != nil {
+			log.Printf("Error fetching data for %s: %v\n", currency, err)
+			return
+		}
+		records, err := source.FetchKlineRange(currency, config.ONE_HOUR, closed, closed, 1)
+		if err == nil && len(records) == 0 {
+			err = fmt.Errorf("no kline returned for %s", closed.Format(time.RFC3339))
+		}
+		if err != nil {
+			log.Printf("Error fetching data for %s, queued for retry: %v\n", currency, err)
+			failedHours.add(currency, closed)
+			return
+		}
+		records, err = app.App.MarketDataService.ScreenData(currency, records[:1])
+		if err != nil || len(records) == 0 {
+			if err != nil {
+				log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+			}
+			return
+		}
+		err = app.App.MarketDataService.StoreData(currency, records[0])
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		}
+	})
+	// the hour that just ended is built from its aggregate trades
+	executeForCurrencies(tradableCurrencies(tradeCandleCurrencies()), func(currency string) {
+		_, err := app.App.TradeService.IngestHours(currency, closed, hour)
+		if err != nil {
+			log.Printf("Error building candles from trades for %s, queued for retry: %v\n", currency, err)
+			failedHours.add(currency, closed)
+		}
+	})
+	// the hour that just ended is consolidated across COMPOSITE_SOURCES
+	if composite.IsEnabled() {
+		executeForAllCurrencies(func(currency string) {
+			_, err := app.App.CompositeService.BuildHours(currency, closed, hour)
+			if err != nil {
+				log.Printf("Error building composite candles for %s: %v\n", currency, err)
+			}
+		})
+	}
+}
+
+// syncClock measures the offset of the local clock to the binance server time
+func syncClock() {
+	offset, err := clock.Sync(binance.FetchServerTime)
+	if err != nil {
+		log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		return
+	}
+	log.Printf(config.COLOR_BLUE+"clock synced with binance, offset:%s"+config.COLOR_RESET, offset)
+}
+
+func collectDepthSnapshots() {
+	executeForCurrencies(tradableCurrencies(instrument.TrackedKeys()), func(curr string) {
+		_, err := app.App.OrderBookService.CollectSnapshot(curr)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		}
+	})
+}
+
+func collectTickers() {
+	executeForCurrencies(tradableCurrencies(instrument.TrackedKeys()), func(curr string) {
+		_, err := app.App.TickerService.CollectSnapshot(curr)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		}
+	})
+}
+
+func ingestFutures() {
+	hour := clock.Now().Truncate(time.Hour)
+	executeForCurrencies(tradableCurrencies(futuresCurrencies()), func(curr string) {
+		err := app.App.FuturesService.IngestHours(curr, hour.Add(-time.Hour), hour)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		}
+
+		_, err = app.App.FuturesService.CollectOpenInterest(curr)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		}
+	})
+}
+
+// futuresCurrencies returns the tracked currencies listed in FUTURES_CURRENCIES
+func futuresCurrencies() []string {
+	var currencies []string
+	for _, currency := range config.LoadConfig().FuturesCurrencies {
+		if slices.Contains(instrument.TrackedKeys(), currency) {
+			currencies = append(currencies, currency)
+		}
+	}
+	return currencies
+}
+
+func syncSymbols() {
+	_, err := app.App.SymbolService.Sync()
+	if err != nil {
+		log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		return
+	}
+	if config.LoadConfig().DiscoveryMode == config.DISCOVERY_MODE_OFF {
+		return
+	}
+	_, err = app.App.SymbolService.Discover()
+	if err != nil {
+		log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+	}
+}
+
+func scanGaps() {
+	log.Println("gap scanner...")
+	executeForAllCurrencies(func(curr string) {
+		_, err := app.App.GapService.ScanAndRepair(curr)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		}
+	})
+}
+
+func applyRetention() {
+	log.Println("retention...")
+	executeForAllCurrencies(func(curr string) {
+		reports, err := app.App.RetentionService.Apply(curr)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		}
+		for _, report := range reports {
+			log.Printf(config.COLOR_BLUE+"archived %s %s before %s: %d records, %d indicators"+config.COLOR_RESET,
+				report.Currency, report.Timeframe, report.Cutoff.Format(time.RFC3339), report.ArchivedRecords, report.ArchivedIndicators)
+		}
+	})
+}
+
+// tradableCurrencies drops the binance sourced currencies whose symbol is halted or delisted,
+// only the jobs fetching from the exchange are gated: grouping, gap scans and retention keep running on the stored history
+func tradableCurrencies(currencies []string) []string {
+	validator := validator.NewValidator()
+	var tradable []string
+	for _, currency := range currencies {
+		if err := validator.ValidateTradable(currency); err != nil {
+			continue
+		}
+		tradable = append(tradable, currency)
+	}
+	return tradable
+}
+
 func executeForAllCurrencies(callback func(curr string)) {
+	executeForCurrencies(instrument.TrackedKeys(), callback)
+}
+
+func executeForCurrencies(currencies []string, callback func(curr string)) {
 	var wg sync.WaitGroup
-	for _, currency := range config.DefaultCurrencies {
+	for _, currency := range currencies {
 		wg.Add(1)
 		go func(curr string) {
 			defer wg.Done()
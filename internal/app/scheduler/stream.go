@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/app"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/exchange"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+)
+
+// StartKlineStream ingests live 1h candles from the Binance WebSocket kline stream.
+// A forming candle is upserted at most once per BinanceStreamUpsertInterval, a closed candle is always upserted.
+func StartKlineStream() {
+	currencies := streamedCurrencies()
+	if len(currencies) == 0 {
+		return
+	}
+
+	cfg := config.LoadConfig()
+	upsertInterval := time.Duration(cfg.BinanceStreamUpsertInterval) * time.Second
+	lastUpsert := make(map[string]time.Time)
+
+	stream := binance.NewKlineStream(currencies, config.ONE_HOUR, func(currency string, record *dto.DataDto) {
+		if !record.IsComplete && time.Since(lastUpsert[currency]) < upsertInterval {
+			return
+		}
+
+		records, err := app.App.MarketDataService.ScreenData(currency, []*dto.DataDto{record})
+		if err != nil || len(records) == 0 {
+			if err != nil {
+				log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+			}
+			return
+		}
+		err = app.App.MarketDataService.UpsertBatchData(currency, records)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+			return
+		}
+
+		if record.IsComplete {
+			// the next forming candle should be stored as soon as it arrives
+			delete(lastUpsert, currency)
+		} else {
+			lastUpsert[currency] = time.Now()
+		}
+	})
+
+	go stream.Run(context.Background())
+}
+
+// streamedCurrencies returns the tradable currencies ingested from the binance kline stream,
+// the stream is not available in replay and mock mode
+func streamedCurrencies() []string {
+	cfg := config.LoadConfig()
+	if !cfg.BinanceStreamEnabled || cfg.BinanceMode == config.BINANCE_MODE_REPLAY || cfg.BinanceMode == config.BINANCE_MODE_MOCK {
+		return nil
+	}
+
+	var currencies []string
+	tradeBuilt := tradeCandleCurrencies()
+	for _, currency := range tradableCurrencies(instrument.TrackedKeys()) {
+		if exchange.GetSourceNameForCurrency(currency) == config.EXCHANGE_BINANCE && !slices.Contains(tradeBuilt, currency) {
+			currencies = append(currencies, currency)
+		}
+	}
+	return currencies
+}
+
+// tradeCandleCurrencies returns the currencies whose 1h candles are built from binance aggregate trades
+func tradeCandleCurrencies() []string {
+	var currencies []string
+	for _, currency := range config.LoadConfig().TradeCandleCurrencies {
+		if slices.Contains(instrument.TrackedKeys(), currency) {
+			currencies = append(currencies, currency)
+		}
+	}
+	return currencies
+}
+
+// polledCurrencies returns the currencies whose klines are fetched by the hourly job
+func polledCurrencies() []string {
+	streamed := streamedCurrencies()
+	tradeBuilt := tradeCandleCurrencies()
+
+	var currencies []string
+	for _, currency := range instrument.TrackedKeys() {
+		if !slices.Contains(streamed, currency) && !slices.Contains(tradeBuilt, currency) {
+			currencies = append(currencies, currency)
+		}
+	}
+	return currencies
+}
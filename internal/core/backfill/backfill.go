@@ -0,0 +1,196 @@
+package backfill
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/exchange"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+)
+
+type BackfillService struct {
+	repository        BackfillStorage
+	marketDataService *marketdata.MarketDataService
+	indicatorService  *indicator.IndicatorService
+	validator         validator.Validator
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func NewBackfillService(marketDataService *marketdata.MarketDataService, indicatorService *indicator.IndicatorService) *BackfillService {
+	validator := validator.NewValidator()
+	return &BackfillService{
+		repository:        NewBackfillStorage(),
+		marketDataService: marketDataService,
+		indicatorService:  indicatorService,
+		validator:         *validator,
+		running:           make(map[string]bool),
+	}
+}
+
+// Backfill walks 1h klines page by page from "from" up to now and blocks until the run is done.
+// An interrupted run is resumed from its checkpoint, a finished run is continued from its last stored kline.
+func (service *BackfillService) Backfill(currency string, from time.Time) (*dto.BackfillCheckpointDto, error) {
+	checkpoint, err := service.begin(currency, from)
+	if err != nil {
+		return nil, err
+	}
+	defer service.release(currency)
+
+	err = service.run(checkpoint)
+	return checkpoint, err
+}
+
+// StartBackfill runs Backfill in the background and returns the checkpoint the run starts from
+func (service *BackfillService) StartBackfill(currency string, from time.Time) (*dto.BackfillCheckpointDto, error) {
+	checkpoint, err := service.begin(currency, from)
+	if err != nil {
+		return nil, err
+	}
+	started := *checkpoint
+
+	go func() {
+		defer service.release(currency)
+		if err := service.run(checkpoint); err != nil {
+			log.Printf("%sError backfilling %s: %s %s\n", config.COLOR_RED, currency, err, config.COLOR_RESET)
+		}
+	}()
+	return &started, nil
+}
+
+func (service *BackfillService) begin(currency string, from time.Time) (*dto.BackfillCheckpointDto, error) {
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, config.ONE_HOUR); err != nil {
+		return nil, err
+	}
+	if err := service.validator.ValidateTradable(currency); err != nil {
+		return nil, err
+	}
+	if !from.Before(clock.Now()) {
+		return nil, fmt.Errorf("backfill start time %s is in the future", from)
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.running[currency] {
+		return nil, fmt.Errorf("backfill for %s is already running", currency)
+	}
+
+	checkpoint, err := service.repository.getCheckpoint(currency, config.ONE_HOUR)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint = service.nextCheckpoint(checkpoint, currency, from.Truncate(time.Hour))
+	if err := service.repository.saveCheckpoint(checkpoint); err != nil {
+		return nil, err
+	}
+
+	service.running[currency] = true
+	return checkpoint, nil
+}
+
+func (service *BackfillService) nextCheckpoint(previous *dto.BackfillCheckpointDto, currency string, from time.Time) *dto.BackfillCheckpointDto {
+	if previous != nil && previous.LastTimestamp != nil && !from.Before(previous.StartTime) {
+		if !previous.IsComplete {
+			log.Printf(config.COLOR_YELLOW+"resuming backfill for %s after %s"+config.COLOR_RESET, currency, previous.LastTimestamp)
+			return previous
+		}
+		from = previous.LastTimestamp.Add(time.Hour)
+	}
+	return &dto.BackfillCheckpointDto{
+		Currency:  currency,
+		Timeframe: config.ONE_HOUR,
+		StartTime: from,
+	}
+}
+
+func (service *BackfillService) release(currency string) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	delete(service.running, currency)
+}
+
+func (service *BackfillService) run(checkpoint *dto.BackfillCheckpointDto) error {
+	startTime := time.Now()
+	cursor := checkpoint.StartTime
+	if checkpoint.LastTimestamp != nil {
+		cursor = checkpoint.LastTimestamp.Add(time.Hour)
+	}
+	endTime := clock.Now()
+	source, err := exchange.GetSourceForCurrency(checkpoint.Currency)
+	if err != nil {
+		return err
+	}
+	log.Printf(config.COLOR_BLUE+"backfilling currency:%s from:%s to:%s source:%s"+config.COLOR_RESET, checkpoint.Currency, cursor, endTime, source.Name())
+
+	for cursor.Before(endTime) {
+		records, err := source.FetchKlineRange(checkpoint.Currency, config.ONE_HOUR, cursor, endTime, source.MaxKlineLimit())
+		if err != nil {
+			return fmt.Errorf("backfill->FetchKlineRange: %w", err)
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		accepted, err := service.marketDataService.ScreenData(checkpoint.Currency, records)
+		if err != nil {
+			return fmt.Errorf("backfill->ScreenData: %w", err)
+		}
+		if err := service.marketDataService.ImportBatchData(checkpoint.Currency, accepted); err != nil {
+			return fmt.Errorf("backfill->ImportBatchData: %w", err)
+		}
+
+		// the forming kline is stored but not checkpointed, so the next run picks it up again
+		for _, record := range records {
+			if record.IsComplete {
+				lastTimestamp := record.Timestamp
+				checkpoint.LastTimestamp = &lastTimestamp
+			}
+		}
+		if err := service.repository.saveCheckpoint(checkpoint); err != nil {
+			return err
+		}
+		log.Printf("backfill %s: stored %d records up to %s", checkpoint.Currency, len(records), records[len(records)-1].Timestamp)
+
+		if len(records) < source.MaxKlineLimit() {
+			break
+		}
+		cursor = records[len(records)-1].Timestamp.Add(time.Hour)
+	}
+
+	if err := service.finish(checkpoint); err != nil {
+		return err
+	}
+	log.Printf("🚀 backfill %s done in %v\n", checkpoint.Currency, time.Since(startTime))
+	return nil
+}
+
+// finish groups the backfilled range and computes its indicators once for the whole run
+func (service *BackfillService) finish(checkpoint *dto.BackfillCheckpointDto) error {
+	if err := service.regroup(checkpoint.Currency, checkpoint.StartTime); err != nil {
+		return err
+	}
+
+	checkpoint.IsComplete = true
+	return service.repository.saveCheckpoint(checkpoint)
+}
+
+// regroup rebuilds the 4h/1d records from the group that contains "from" and computes their indicators
+func (service *BackfillService) regroup(currency string, from time.Time) error {
+	for _, timeframe := range []string{config.FOUR_HOUR, config.ONE_DAY} {
+		if err := service.marketDataService.StoreGroupedRecordsFrom(currency, timeframe, from); err != nil {
+			return fmt.Errorf("backfill->StoreGroupedRecordsFrom %s: %w", timeframe, err)
+		}
+		if err := service.indicatorService.ComputeAndUpsertBatch(currency, timeframe); err != nil {
+			return fmt.Errorf("backfill->ComputeAndUpsertBatch %s: %w", timeframe, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package backfill
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+)
+
+// Import loads the 1h kline dumps of data.binance.vision found in dir (and its subdirectories) for a currency.
+// Every dump is verified against its CHECKSUM file and bulk-loaded, the 4h/1d records and indicators are
+// rebuilt once at the end. Importing the same dumps again stores nothing new, rows already stored are reported as overlapping.
+func (service *BackfillService) Import(currency string, dir string) (*dto.ImportReportDto, error) {
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, config.ONE_HOUR); err != nil {
+		return nil, err
+	}
+	if err := service.acquire(currency); err != nil {
+		return nil, err
+	}
+	defer service.release(currency)
+
+	paths, err := findKlineArchives(dir)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf(config.COLOR_BLUE+"importing currency:%s from:%s dumps:%d"+config.COLOR_RESET, currency, dir, len(paths))
+
+	report := &dto.ImportReportDto{Currency: currency}
+	startTime := time.Now()
+	for _, path := range paths {
+		symbol, interval, _ := binance.ParseKlineArchiveName(path)
+		if symbol != instrument.Symbol(currency) || interval != config.ONE_HOUR {
+			continue
+		}
+		if err := binance.VerifyKlineArchive(path); err != nil {
+			report.Skipped = append(report.Skipped, err.Error())
+			continue
+		}
+		records, err := binance.ReadKlineArchive(path, currency, interval)
+		if err != nil {
+			report.Skipped = append(report.Skipped, err.Error())
+			continue
+		}
+		if err := service.importRecords(currency, records, report); err != nil {
+			return report, fmt.Errorf("import %s: %w", filepath.Base(path), err)
+		}
+		report.Files++
+		log.Printf("import %s: loaded %d records from %s", currency, len(records), filepath.Base(path))
+	}
+
+	if report.From != nil {
+		if err := service.regroup(currency, *report.From); err != nil {
+			return report, err
+		}
+	}
+	log.Printf("🚀 import %s done in %v\n", currency, time.Since(startTime))
+	return report, nil
+}
+
+// importRecords counts the rows of a dump that are already stored and upserts the ones passing the quality checks
+func (service *BackfillService) importRecords(currency string, records []*dto.DataDto, report *dto.ImportReportDto) error {
+	if len(records) == 0 {
+		return nil
+	}
+	slices.SortFunc(records, func(a, b *dto.DataDto) int { return a.Timestamp.Compare(b.Timestamp) })
+	first, last := records[0].Timestamp, records[len(records)-1].Timestamp
+
+	stored, err := service.marketDataService.GetRecordsByRequest(dto.OHLCRequestDto{
+		Currency:  currency,
+		Timeframe: config.ONE_HOUR,
+		StartTime: &first,
+		EndTime:   &last,
+		Limit:     int32(last.Sub(first)/time.Hour) + 1,
+		SortField: config.DEFAULT_DATA_REQUEST_SORT_FIELD,
+		SortOrder: "ASC",
+	})
+	if err != nil {
+		return err
+	}
+	storedByTimestamp := make(map[time.Time]dto.DataDto, len(stored))
+	for _, record := range stored {
+		storedByTimestamp[record.Timestamp.UTC()] = record
+	}
+	for _, record := range records {
+		existing, ok := storedByTimestamp[record.Timestamp]
+		if !ok {
+			continue
+		}
+		report.Overlapping++
+		if !existing.Open.Equal(record.Open) || !existing.High.Equal(record.High) || !existing.Low.Equal(record.Low) ||
+			!existing.Close.Equal(record.Close) || !existing.Volume.Equal(record.Volume) {
+			report.Conflicting++
+		}
+	}
+
+	accepted, err := service.marketDataService.ScreenData(currency, records)
+	if err != nil {
+		return err
+	}
+	if err := service.marketDataService.ImportBatchData(currency, accepted); err != nil {
+		return err
+	}
+
+	report.Records += len(records)
+	report.Quarantined += len(records) - len(accepted)
+	if report.From == nil || first.Before(*report.From) {
+		report.From = &first
+	}
+	if report.To == nil || last.After(*report.To) {
+		report.To = &last
+	}
+	return nil
+}
+
+// acquire keeps an import from running next to a backfill of the same currency
+func (service *BackfillService) acquire(currency string) error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	if service.running[currency] {
+		return fmt.Errorf("backfill for %s is already running", currency)
+	}
+	service.running[currency] = true
+	return nil
+}
+
+// findKlineArchives returns the kline dumps under dir ordered by name, which orders the dumps of a symbol chronologically
+func findKlineArchives(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".zip") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list %s: %v", dir, err)
+	}
+	slices.SortFunc(paths, func(a, b string) int { return strings.Compare(filepath.Base(a), filepath.Base(b)) })
+	return paths, nil
+}
@@ -0,0 +1,35 @@
+package backfill
+
+import (
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+)
+
+// MemoryBackfillRepository keeps the checkpoints in a memory.Store with the semantics of the postgres BackfillRepository
+type MemoryBackfillRepository struct {
+	store *memory.Store
+}
+
+func NewMemoryBackfillRepository(store *memory.Store) *MemoryBackfillRepository {
+	return &MemoryBackfillRepository{store: store}
+}
+
+func (repository *MemoryBackfillRepository) getCheckpoint(currency string, timeframe string) (*dto.BackfillCheckpointDto, error) {
+	checkpoint, ok := repository.store.Checkpoint(currency, timeframe)
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+func (repository *MemoryBackfillRepository) saveCheckpoint(checkpoint *dto.BackfillCheckpointDto) error {
+	saved := *checkpoint
+	if checkpoint.LastTimestamp != nil {
+		lastTimestamp := *checkpoint.LastTimestamp
+		saved.LastTimestamp = &lastTimestamp
+	}
+	saved.UpdatedAt = clock.Now()
+	repository.store.SaveCheckpoint(saved)
+	return nil
+}
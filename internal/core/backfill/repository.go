@@ -0,0 +1,49 @@
+package backfill
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+)
+
+type BackfillRepository struct {
+}
+
+func NewBackfillRepository() *BackfillRepository {
+	return &BackfillRepository{}
+}
+
+func (repository *BackfillRepository) getCheckpoint(currency, timeframe string) (*dto.BackfillCheckpointDto, error) {
+	query := `SELECT currency, timeframe, start_time, last_timestamp, is_complete, updated_at
+			  FROM backfill_checkpoints
+			  WHERE currency = $1 AND timeframe = $2`
+
+	var checkpoint dto.BackfillCheckpointDto
+	err := database.DB.QueryRow(query, currency, timeframe).Scan(&checkpoint.Currency, &checkpoint.Timeframe,
+		&checkpoint.StartTime, &checkpoint.LastTimestamp, &checkpoint.IsComplete, &checkpoint.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("💾 error fetching backfill checkpoint: %v", err)
+	}
+	return &checkpoint, nil
+}
+
+func (repository *BackfillRepository) saveCheckpoint(checkpoint *dto.BackfillCheckpointDto) error {
+	query := `INSERT INTO backfill_checkpoints (currency, timeframe, start_time, last_timestamp, is_complete, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, NOW())
+			  ON CONFLICT (currency, timeframe) DO UPDATE
+			  SET start_time = EXCLUDED.start_time,
+			  last_timestamp = EXCLUDED.last_timestamp,
+			  is_complete = EXCLUDED.is_complete,
+			  updated_at = EXCLUDED.updated_at`
+
+	_, err := database.DB.Exec(query, checkpoint.Currency, checkpoint.Timeframe, checkpoint.StartTime, checkpoint.LastTimestamp, checkpoint.IsComplete)
+	if err != nil {
+		return fmt.Errorf("💾 error saving backfill checkpoint: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package backfill
+
+import (
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// BackfillStorage is implemented by the postgres BackfillRepository and the in-memory MemoryBackfillRepository
+type BackfillStorage interface {
+	getCheckpoint(currency string, timeframe string) (*dto.BackfillCheckpointDto, error)
+	saveCheckpoint(checkpoint *dto.BackfillCheckpointDto) error
+}
+
+// NewBackfillStorage returns the repository of the STORAGE_BACKEND, the timescale backend keeps its checkpoints in postgres
+func NewBackfillStorage() BackfillStorage {
+	if config.LoadConfig().StorageBackend == config.STORAGE_BACKEND_MEMORY {
+		return NewMemoryBackfillRepository(memory.DB)
+	}
+	return NewBackfillRepository()
+}
@@ -0,0 +1,90 @@
+package composite
+
+import (
+	"slices"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/shopspring/decimal"
+)
+
+// BuildCandle consolidates the candles of the same hour from several venues into one composite candle.
+// Venues whose close deviates from the median close by more than maxDeviation (0.01 = 1%) are ignored,
+// the prices of the remaining ones are weighted by their base volume. Returns nil when there are no venues.
+func BuildCandle(symbol string, timeframe string, venues []*dto.DataDto, maxDeviation float64) *dto.DataDto {
+	if len(venues) == 0 {
+		return nil
+	}
+
+	medianPrice := median(venues)
+	deviation := decimal.NewFromFloat(maxDeviation)
+	var included []*dto.DataDto
+	for _, venue := range venues {
+		if medianPrice.IsZero() || venue.Close.Sub(medianPrice).Abs().Div(medianPrice).LessThanOrEqual(deviation) {
+			included = append(included, venue)
+		}
+	}
+	// two venues that disagree both deviate from their median, there is no outlier to tell apart
+	if len(included) == 0 {
+		included = venues
+	}
+
+	candle := &dto.DataDto{
+		Symbol:      symbol,
+		Timeframe:   timeframe,
+		Timestamp:   venues[0].Timestamp,
+		IsComplete:  true,
+		MedianPrice: medianPrice,
+	}
+	for _, venue := range included {
+		candle.Volume = candle.Volume.Add(venue.Volume)
+		candle.QuoteVolume = candle.QuoteVolume.Add(venue.QuoteVolume)
+		candle.TradeCount += venue.TradeCount
+		candle.TakerBuyBaseVolume = candle.TakerBuyBaseVolume.Add(venue.TakerBuyBaseVolume)
+		candle.TakerBuyQuoteVolume = candle.TakerBuyQuoteVolume.Add(venue.TakerBuyQuoteVolume)
+		candle.IsComplete = candle.IsComplete && venue.IsComplete
+	}
+
+	candle.Open = weightedAverage(included, func(venue *dto.DataDto) decimal.Decimal { return venue.Open })
+	candle.High = weightedAverage(included, func(venue *dto.DataDto) decimal.Decimal { return venue.High })
+	candle.Low = weightedAverage(included, func(venue *dto.DataDto) decimal.Decimal { return venue.Low })
+	candle.Close = weightedAverage(included, func(venue *dto.DataDto) decimal.Decimal { return venue.Close })
+	candle.Vwap = weightedAverage(included, func(venue *dto.DataDto) decimal.Decimal {
+		// not every exchange reports the quote volume a vwap is computed from
+		if venue.Vwap.IsZero() {
+			return venue.Close
+		}
+		return venue.Vwap
+	})
+	return candle
+}
+
+func median(venues []*dto.DataDto) decimal.Decimal {
+	closes := make([]decimal.Decimal, len(venues))
+	for i, venue := range venues {
+		closes[i] = venue.Close
+	}
+	slices.SortFunc(closes, decimal.Decimal.Cmp)
+
+	middle := len(closes) / 2
+	if len(closes)%2 == 0 {
+		return closes[middle-1].Add(closes[middle]).Div(decimal.NewFromInt(2))
+	}
+	return closes[middle]
+}
+
+// weightedAverage weights every venue by its base volume, venues count equally when none has traded
+func weightedAverage(venues []*dto.DataDto, value func(venue *dto.DataDto) decimal.Decimal) decimal.Decimal {
+	var total, weights decimal.Decimal
+	for _, venue := range venues {
+		total = total.Add(value(venue).Mul(venue.Volume))
+		weights = weights.Add(venue.Volume)
+	}
+	if weights.IsPositive() {
+		return total.Div(weights)
+	}
+
+	for _, venue := range venues {
+		total = total.Add(value(venue))
+	}
+	return total.Div(decimal.NewFromInt(int64(len(venues))))
+}
@@ -0,0 +1,107 @@
+package composite
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/exchange"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+type CompositeService struct {
+	marketDataService *marketdata.MarketDataService
+	redis             redis.RedisServiceInterface
+	validator         validator.Validator
+}
+
+func NewCompositeService(marketDataService *marketdata.MarketDataService, redis redis.RedisServiceInterface) *CompositeService {
+	validator := validator.NewValidator()
+	return &CompositeService{
+		marketDataService: marketDataService,
+		redis:             redis,
+		validator:         *validator,
+	}
+}
+
+// IsEnabled reports whether COMPOSITE_SOURCES lists any exchange
+func IsEnabled() bool {
+	return len(config.LoadConfig().CompositeSources) > 0
+}
+
+// BuildHours fetches the 1h candles of [from, to) from every exchange of COMPOSITE_SOURCES and stores
+// one composite candle per hour in the 1h_composite partition. An exchange that fails is left out of the composite.
+func (service *CompositeService) BuildHours(currency string, from time.Time, to time.Time) ([]*dto.DataDto, error) {
+	timeframe := utils.GetCompositeTimeframe(config.ONE_HOUR)
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, timeframe); err != nil {
+		return nil, err
+	}
+	from = from.UTC().Truncate(time.Hour)
+	to = to.UTC().Truncate(time.Hour)
+	if !from.Before(to) {
+		return nil, fmt.Errorf("empty range %s - %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	cfg := config.LoadConfig()
+	venuesByHour := make(map[time.Time][]*dto.DataDto)
+	for _, name := range cfg.CompositeSources {
+		records, err := service.fetchHours(name, currency, from, to)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+			continue
+		}
+		for _, record := range records {
+			hour := record.Timestamp.UTC()
+			if !hour.Before(from) && hour.Before(to) {
+				venuesByHour[hour] = append(venuesByHour[hour], record)
+			}
+		}
+	}
+
+	var candles []*dto.DataDto
+	maxDeviation := float64(cfg.CompositeMaxDeviation) / 10000
+	for hour := from; hour.Before(to); hour = hour.Add(time.Hour) {
+		candle := BuildCandle(instrument.Symbol(currency), timeframe, venuesByHour[hour], maxDeviation)
+		if candle != nil {
+			candles = append(candles, candle)
+		}
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no composite candles for %s between %s and %s", currency, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	log.Printf(config.COLOR_BLUE+"built %d composite candles currency:%s"+config.COLOR_RESET, len(candles), currency)
+
+	candles, err := service.marketDataService.ScreenData(currency, candles)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.marketDataService.ImportBatchData(currency, candles); err != nil {
+		return nil, err
+	}
+	return candles, service.publishEvent(config.EVENT_NEW_COMPOSITE_DATA_ADDED)
+}
+
+func (service *CompositeService) fetchHours(name string, currency string, from time.Time, to time.Time) ([]*dto.DataDto, error) {
+	source, err := exchange.NewMarketDataSource(name)
+	if err != nil {
+		return nil, err
+	}
+	limit := min(int(to.Sub(from).Hours()), source.MaxKlineLimit())
+	records, err := source.FetchKlineRange(currency, config.ONE_HOUR, from, to.Add(-time.Millisecond), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return records, nil
+}
+
+func (service *CompositeService) publishEvent(eventName string) error {
+	ctx := context.Background()
+	return service.redis.PublishEvent(ctx, eventName, config.APPLICATION_NAME)
+}
@@ -0,0 +1,102 @@
+package currency
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+)
+
+type CurrencyService struct {
+	repository CurrencyRepository
+}
+
+func NewCurrencyService() *CurrencyService {
+	repository := NewCurrencyRepository()
+	return &CurrencyService{
+		repository: *repository,
+	}
+}
+
+// Load reads the currencies table into the instrument registry and provisions the tables of the registered currencies
+func (service *CurrencyService) Load() error {
+	records, err := service.repository.getCurrencies()
+	if err != nil {
+		return err
+	}
+
+	currencies := make(map[string]string, len(records))
+	for _, record := range records {
+		if err := database.EnsureInstrumentTables(record.Key); err != nil {
+			return err
+		}
+		currencies[record.Key] = record.Status
+	}
+	instrument.SetCurrencies(currencies)
+	return nil
+}
+
+func (service *CurrencyService) GetCurrencies() ([]*dto.CurrencyDto, error) {
+	return service.repository.getCurrencies()
+}
+
+// AddCurrency provisions the tables of the instrument ("eth", "eth/btc"...) and registers it as active,
+// adding a paused currency resumes it
+func (service *CurrencyService) AddCurrency(value string) (*dto.CurrencyDto, error) {
+	parsed, err := instrument.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.EnsureInstrumentTables(parsed.Key()); err != nil {
+		return nil, err
+	}
+
+	record, err := service.repository.upsertCurrency(&dto.CurrencyDto{
+		Key:        parsed.Key(),
+		BaseAsset:  strings.ToUpper(parsed.Base),
+		QuoteAsset: strings.ToUpper(parsed.Quote),
+		Status:     config.CURRENCY_STATUS_ACTIVE,
+	})
+	if err != nil {
+		return nil, err
+	}
+	instrument.Register(record.Key, record.Status)
+	log.Printf(config.COLOR_GREEN+"added currency %s"+config.COLOR_RESET, parsed)
+	return record, nil
+}
+
+// PauseCurrency stops ingesting the currency, its stored data is still served
+func (service *CurrencyService) PauseCurrency(key string) (*dto.CurrencyDto, error) {
+	record, err := service.repository.setStatus(key, config.CURRENCY_STATUS_PAUSED)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("unknown currency: %s", key)
+	}
+	instrument.Register(record.Key, record.Status)
+	log.Printf(config.COLOR_YELLOW+"paused currency %s"+config.COLOR_RESET, instrument.FromKey(key))
+	return record, nil
+}
+
+// RemoveCurrency unregisters the currency and stops discovery from tracking it, its tables are kept
+func (service *CurrencyService) RemoveCurrency(key string) error {
+	deleted, err := service.repository.deleteCurrency(key)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("unknown currency: %s", key)
+	}
+	if err := service.repository.disableSymbol(instrument.Symbol(key)); err != nil {
+		return err
+	}
+	instrument.Unregister(key)
+	instrument.Disable(key)
+	log.Printf(config.COLOR_YELLOW+"removed currency %s"+config.COLOR_RESET, instrument.FromKey(key))
+	return nil
+}
@@ -0,0 +1,98 @@
+package currency
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+)
+
+type CurrencyRepository struct {
+}
+
+func NewCurrencyRepository() *CurrencyRepository {
+	return &CurrencyRepository{}
+}
+
+const currencyColumns = `key, base_asset, quote_asset, status, created_at, updated_at`
+
+// upsertCurrency registers the currency or reactivates it with the given status
+func (repository *CurrencyRepository) upsertCurrency(record *dto.CurrencyDto) (*dto.CurrencyDto, error) {
+	rows, err := database.DB.Query(`INSERT INTO currencies (key, base_asset, quote_asset, status)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key) DO UPDATE
+		SET status = EXCLUDED.status,
+		updated_at = NOW()
+		RETURNING `+currencyColumns,
+		record.Key, record.BaseAsset, record.QuoteAsset, record.Status)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error upserting currency %s: %v", record.Key, err)
+	}
+	return first(scanCurrencies(rows))
+}
+
+// setStatus returns nil when the currency is not registered
+func (repository *CurrencyRepository) setStatus(key string, status string) (*dto.CurrencyDto, error) {
+	rows, err := database.DB.Query(`UPDATE currencies SET status = $2, updated_at = NOW() WHERE key = $1 RETURNING `+currencyColumns, key, status)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error updating currency %s: %v", key, err)
+	}
+	return first(scanCurrencies(rows))
+}
+
+// deleteCurrency returns false when the currency is not registered
+func (repository *CurrencyRepository) deleteCurrency(key string) (bool, error) {
+	result, err := database.DB.Exec(`DELETE FROM currencies WHERE key = $1`, key)
+	if err != nil {
+		return false, fmt.Errorf("💾 error deleting currency %s: %v", key, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("💾 error deleting currency %s: %v", key, err)
+	}
+	return affected > 0, nil
+}
+
+// disableSymbol stops discovery from tracking the binance symbol again on the next load
+func (repository *CurrencyRepository) disableSymbol(symbol string) error {
+	_, err := database.DB.Exec(`UPDATE symbols SET enabled = FALSE WHERE symbol = $1`, symbol)
+	if err != nil {
+		return fmt.Errorf("💾 error disabling symbol %s: %v", symbol, err)
+	}
+	return nil
+}
+
+func (repository *CurrencyRepository) getCurrencies() ([]*dto.CurrencyDto, error) {
+	rows, err := database.DB.Query(`SELECT ` + currencyColumns + ` FROM currencies ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching currencies: %v", err)
+	}
+	return scanCurrencies(rows)
+}
+
+func first(records []*dto.CurrencyDto, err error) (*dto.CurrencyDto, error) {
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0], nil
+}
+
+func scanCurrencies(rows *sql.Rows) ([]*dto.CurrencyDto, error) {
+	defer rows.Close()
+
+	var records []*dto.CurrencyDto
+	for rows.Next() {
+		var record dto.CurrencyDto
+		err := rows.Scan(&record.Key, &record.BaseAsset, &record.QuoteAsset, &record.Status, &record.CreatedAt, &record.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return records, nil
+}
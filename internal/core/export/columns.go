@@ -0,0 +1,105 @@
+package export
+
+import (
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+)
+
+type columnKind int
+
+const (
+	kindTime columnKind = iota
+	kindString
+	kindDecimal
+	kindInt
+	kindBool
+	kindFloat
+)
+
+// column is an exported field of a candle joined with its indicator, value returns nil for a missing indicator
+type column struct {
+	name  string
+	kind  columnKind
+	value func(row dto.ExportRowDto) any
+}
+
+// exportColumns are the exported columns in their default order
+var exportColumns = []column{
+	{"timestamp", kindTime, func(row dto.ExportRowDto) any { return row.Data.Timestamp }},
+	{"symbol", kindString, func(row dto.ExportRowDto) any { return row.Data.Symbol }},
+	{"timeframe", kindString, func(row dto.ExportRowDto) any { return row.Data.Timeframe }},
+	{"open", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.Open }},
+	{"high", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.High }},
+	{"low", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.Low }},
+	{"close", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.Close }},
+	{"volume", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.Volume }},
+	{"trend", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.Trend }},
+	{"is_complete", kindBool, func(row dto.ExportRowDto) any { return row.Data.IsComplete }},
+	{"quote_volume", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.QuoteVolume }},
+	{"trade_count", kindInt, func(row dto.ExportRowDto) any { return row.Data.TradeCount }},
+	{"taker_buy_base_volume", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.TakerBuyBaseVolume }},
+	{"taker_buy_quote_volume", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.TakerBuyQuoteVolume }},
+	{"vwap", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.Vwap }},
+	{"median_price", kindDecimal, func(row dto.ExportRowDto) any { return row.Data.MedianPrice }},
+	{"sma", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.SMA })},
+	{"ema", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.EMA })},
+	{"tr", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.TR })},
+	{"std_dev", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.StdDev })},
+	{"lower_bollinger", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.LowerBollinger })},
+	{"upper_bollinger", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.UpperBollinger })},
+	{"rsi", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.RSI })},
+	{"volatility", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.Volatility })},
+	{"macd", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.MACD })},
+	{"macd_signal", kindFloat, indicatorValue(func(indicator *dto.IndicatorDto) float64 { return indicator.MACDSignal })},
+}
+
+func indicatorValue(field func(indicator *dto.IndicatorDto) float64) func(row dto.ExportRowDto) any {
+	return func(row dto.ExportRowDto) any {
+		if row.Indicator == nil {
+			return nil
+		}
+		return field(row.Indicator)
+	}
+}
+
+// ColumnNames returns the names of the exported columns in their default order
+func ColumnNames() []string {
+	names := make([]string, len(exportColumns))
+	for i, column := range exportColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// selectColumns returns the columns in the requested order, every column when none is requested
+func selectColumns(names []string) ([]column, bool) {
+	if len(names) == 0 {
+		return exportColumns, true
+	}
+	byName := make(map[string]column, len(exportColumns))
+	for _, column := range exportColumns {
+		byName[column.name] = column
+	}
+	selected := make([]column, 0, len(names))
+	for _, name := range names {
+		column, ok := byName[name]
+		if !ok {
+			return nil, false
+		}
+		selected = append(selected, column)
+	}
+	return selected, true
+}
+
+// formatTimestamp renders a timestamp of the text formats as ISO 8601 in UTC or as unix seconds or milliseconds
+func formatTimestamp(timestamp time.Time, timeFormat string) any {
+	switch timeFormat {
+	case config.EXPORT_TIME_FORMAT_UNIX:
+		return timestamp.Unix()
+	case config.EXPORT_TIME_FORMAT_UNIX_MS:
+		return timestamp.UnixMilli()
+	}
+	return timestamp.UTC().Format(time.RFC3339)
+}
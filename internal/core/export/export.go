@@ -0,0 +1,65 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+)
+
+type ExportService struct {
+	repository ExportStorage
+	validator  validator.Validator
+}
+
+func NewExportService() *ExportService {
+	repository := NewExportStorage()
+	validator := validator.NewValidator()
+	return &ExportService{
+		repository: repository,
+		validator:  *validator,
+	}
+}
+
+// Export streams the candles of a currency and timeframe joined with their indicators into output as CSV,
+// NDJSON or parquet. Rows are encoded while they are read, a multi-year export is never held in memory.
+// It returns the number of exported rows.
+func (service *ExportService) Export(ctx context.Context, request dto.ExportRequestDto, output io.Writer) (int64, error) {
+	if err := service.validator.ValidateCurrencyAndTimeframe(request.Currency, request.Timeframe); err != nil {
+		return 0, err
+	}
+	if request.Format == "" {
+		request.Format = config.EXPORT_FORMAT_CSV
+	}
+	if request.TimeFormat == "" {
+		request.TimeFormat = config.EXPORT_TIME_FORMAT_ISO
+	}
+	if !slices.Contains([]string{config.EXPORT_TIME_FORMAT_ISO, config.EXPORT_TIME_FORMAT_UNIX, config.EXPORT_TIME_FORMAT_UNIX_MS}, request.TimeFormat) {
+		return 0, fmt.Errorf("unknown time format: %s", request.TimeFormat)
+	}
+	columns, ok := selectColumns(request.Columns)
+	if !ok {
+		return 0, fmt.Errorf("unknown columns: %v, available: %v", request.Columns, ColumnNames())
+	}
+
+	writer, err := newRowWriter(request.Format, columns, request.TimeFormat, output)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Printf(config.COLOR_BLUE+"exporting currency:%s timeframe:%s format:%s"+config.COLOR_RESET, request.Currency, request.Timeframe, request.Format)
+	var count int64
+	err = service.repository.streamRows(ctx, request, func(row dto.ExportRowDto) error {
+		count++
+		return writer.write(row)
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writer.close()
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"context"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// MemoryExportRepository joins the candles and indicators of a memory.Store like the postgres ExportRepository
+type MemoryExportRepository struct {
+	store *memory.Store
+}
+
+func NewMemoryExportRepository(store *memory.Store) *MemoryExportRepository {
+	return &MemoryExportRepository{store: store}
+}
+
+func (repository *MemoryExportRepository) streamRows(ctx context.Context, request dto.ExportRequestDto, write func(row dto.ExportRowDto) error) error {
+	indicators := make(map[time.Time]dto.IndicatorDto)
+	for _, indicator := range repository.store.Indicators(request.Currency, request.Timeframe) {
+		indicators[indicator.DataTimestamp] = indicator
+	}
+
+	for _, record := range repository.store.Candles(request.Currency, request.Timeframe) {
+		if request.StartTime != nil && record.Timestamp.Before(*request.StartTime) {
+			continue
+		}
+		if request.EndTime != nil && record.Timestamp.After(*request.EndTime) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		row := dto.ExportRowDto{Data: record}
+		if indicator, ok := indicators[record.Timestamp]; ok {
+			row.Indicator = &indicator
+		}
+		if err := write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+)
+
+type ExportRepository struct{}
+
+func NewExportRepository() *ExportRepository {
+	return &ExportRepository{}
+}
+
+func (repository *ExportRepository) streamRows(ctx context.Context, request dto.ExportRequestDto, write func(row dto.ExportRowDto) error) error {
+	query := fmt.Sprintf(`
+	SELECT d.symbol, d.timeframe, d.timestamp, d.open, d.high, d.low, d.close, d.volume, d.trend, d.is_complete,
+	d.quote_volume, d.trade_count, d.taker_buy_base_volume, d.taker_buy_quote_volume, d.vwap, d.median_price,
+	i.timestamp, i.sma, i.ema, i.tr, i.std_dev, i.lower_bollinger, i.upper_bollinger, i.volatility, i.rsi, i.macd, i.macd_signal
+	FROM (SELECT * FROM %s) d
+	LEFT JOIN %s i ON i.data_timestamp = d.timestamp
+	WHERE ($1::TIMESTAMPTZ IS NULL OR d.timestamp >= $1) AND ($2::TIMESTAMPTZ IS NULL OR d.timestamp <= $2)
+	ORDER BY d.timestamp ASC`, database.CandleTable(request.Currency, request.Timeframe), database.IndicatorTable(request.Currency, request.Timeframe))
+
+	rows, err := database.DB.QueryContext(ctx, query, request.StartTime, request.EndTime)
+	if err != nil {
+		return fmt.Errorf("💾 error fetching export rows: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row dto.ExportRowDto
+		var timestamp sql.NullTime
+		var sma, ema, tr, stdDev, lowerBollinger, upperBollinger, volatility, rsi, macd, macdSignal sql.NullFloat64
+		err := rows.Scan(&row.Data.Symbol, &row.Data.Timeframe, &row.Data.Timestamp,
+			&row.Data.Open, &row.Data.High, &row.Data.Low, &row.Data.Close, &row.Data.Volume, &row.Data.Trend, &row.Data.IsComplete,
+			&row.Data.QuoteVolume, &row.Data.TradeCount, &row.Data.TakerBuyBaseVolume, &row.Data.TakerBuyQuoteVolume, &row.Data.Vwap, &row.Data.MedianPrice,
+			&timestamp, &sma, &ema, &tr, &stdDev, &lowerBollinger, &upperBollinger, &volatility, &rsi, &macd, &macdSignal)
+		if err != nil {
+			return fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		if timestamp.Valid {
+			row.Indicator = &dto.IndicatorDto{
+				Timeframe:      row.Data.Timeframe,
+				Timestamp:      timestamp.Time,
+				DataTimestamp:  row.Data.Timestamp,
+				SMA:            sma.Float64,
+				EMA:            ema.Float64,
+				TR:             tr.Float64,
+				StdDev:         stdDev.Float64,
+				LowerBollinger: lowerBollinger.Float64,
+				UpperBollinger: upperBollinger.Float64,
+				Volatility:     volatility.Float64,
+				RSI:            rsi.Float64,
+				MACD:           macd.Float64,
+				MACDSignal:     macdSignal.Float64,
+			}
+		}
+		if err := write(row); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package export
+
+import (
+	"context"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// ExportStorage is implemented by the postgres ExportRepository (on plain or timescale tables)
+// and the in-memory MemoryExportRepository
+type ExportStorage interface {
+	// streamRows calls write for every candle of the request joined with its indicator, in chronological order,
+	// without loading the range into memory
+	streamRows(ctx context.Context, request dto.ExportRequestDto, write func(row dto.ExportRowDto) error) error
+}
+
+// NewExportStorage returns the repository of the STORAGE_BACKEND
+func NewExportStorage() ExportStorage {
+	if config.LoadConfig().StorageBackend == config.STORAGE_BACKEND_MEMORY {
+		return NewMemoryExportRepository(memory.DB)
+	}
+	return NewExportRepository()
+}
@@ -0,0 +1,218 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+)
+
+// parquetRowGroupSize bounds the rows the parquet writer buffers before flushing a row group
+const parquetRowGroupSize = 10000
+
+// rowWriter encodes the exported rows one by one into an output
+type rowWriter interface {
+	write(row dto.ExportRowDto) error
+	close() error
+}
+
+func newRowWriter(format string, columns []column, timeFormat string, output io.Writer) (rowWriter, error) {
+	switch format {
+	case config.EXPORT_FORMAT_CSV:
+		return newCsvWriter(columns, timeFormat, output)
+	case config.EXPORT_FORMAT_NDJSON:
+		return &ndjsonWriter{columns: columns, timeFormat: timeFormat, output: bufio.NewWriter(output)}, nil
+	case config.EXPORT_FORMAT_PARQUET:
+		return newParquetWriter(columns, output), nil
+	}
+	return nil, fmt.Errorf("unknown export format: %s", format)
+}
+
+// csvWriter writes a header line with the column names, missing indicators are empty fields
+type csvWriter struct {
+	columns    []column
+	timeFormat string
+	output     *csv.Writer
+	record     []string
+}
+
+func newCsvWriter(columns []column, timeFormat string, output io.Writer) (*csvWriter, error) {
+	writer := &csvWriter{columns: columns, timeFormat: timeFormat, output: csv.NewWriter(output), record: make([]string, len(columns))}
+	for i, column := range columns {
+		writer.record[i] = column.name
+	}
+	if err := writer.output.Write(writer.record); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (writer *csvWriter) write(row dto.ExportRowDto) error {
+	for i, column := range writer.columns {
+		writer.record[i] = formatText(column.value(row), writer.timeFormat)
+	}
+	return writer.output.Write(writer.record)
+}
+
+func (writer *csvWriter) close() error {
+	writer.output.Flush()
+	return writer.output.Error()
+}
+
+// ndjsonWriter writes one JSON object per line with the keys in column order,
+// decimals are JSON numbers with their exact digits and missing indicators are null
+type ndjsonWriter struct {
+	columns    []column
+	timeFormat string
+	output     *bufio.Writer
+	line       []byte
+}
+
+func (writer *ndjsonWriter) write(row dto.ExportRowDto) error {
+	line := append(writer.line[:0], '{')
+	for i, column := range writer.columns {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		line = strconv.AppendQuote(line, column.name)
+		line = append(line, ':')
+
+		switch value := column.value(row).(type) {
+		case nil:
+			line = append(line, "null"...)
+		case time.Time:
+			encoded, _ := json.Marshal(formatTimestamp(value, writer.timeFormat))
+			line = append(line, encoded...)
+		case string:
+			encoded, _ := json.Marshal(value)
+			line = append(line, encoded...)
+		case float64:
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				line = append(line, "null"...)
+			} else {
+				line = strconv.AppendFloat(line, value, 'f', -1, 64)
+			}
+		default:
+			line = append(line, formatText(value, writer.timeFormat)...)
+		}
+	}
+	writer.line = append(line, '}', '\n')
+	_, err := writer.output.Write(writer.line)
+	return err
+}
+
+func (writer *ndjsonWriter) close() error {
+	return writer.output.Flush()
+}
+
+// parquetWriter writes zstd compressed row groups of parquetRowGroupSize rows. Timestamps are stored as
+// millisecond timestamps, prices and volumes as doubles and the indicator columns are optional.
+type parquetWriter struct {
+	columns []column
+	leaves  []parquet.LeafColumn // position of every column in the schema
+	output  *parquet.Writer
+	rows    []parquet.Row
+}
+
+func newParquetWriter(columns []column, output io.Writer) *parquetWriter {
+	group := parquet.Group{}
+	for _, column := range columns {
+		group[column.name] = parquetNode(column.kind)
+	}
+	schema := parquet.NewSchema("export", group)
+
+	leaves := make([]parquet.LeafColumn, len(columns))
+	for i, column := range columns {
+		leaves[i], _ = schema.Lookup(column.name)
+	}
+
+	return &parquetWriter{
+		columns: columns,
+		leaves:  leaves,
+		output:  parquet.NewWriter(output, schema, parquet.Compression(&parquet.Zstd), parquet.MaxRowsPerRowGroup(parquetRowGroupSize)),
+		rows:    []parquet.Row{make(parquet.Row, len(columns))},
+	}
+}
+
+func (writer *parquetWriter) write(row dto.ExportRowDto) error {
+	values := writer.rows[0]
+	for i, column := range writer.columns {
+		leaf := writer.leaves[i]
+		value := column.value(row)
+		if value == nil {
+			values[leaf.ColumnIndex] = parquet.NullValue().Level(0, 0, leaf.ColumnIndex)
+			continue
+		}
+		values[leaf.ColumnIndex] = parquetValue(value).Level(0, leaf.MaxDefinitionLevel, leaf.ColumnIndex)
+	}
+	_, err := writer.output.WriteRows(writer.rows)
+	return err
+}
+
+func (writer *parquetWriter) close() error {
+	return writer.output.Close()
+}
+
+func parquetNode(kind columnKind) parquet.Node {
+	switch kind {
+	case kindTime:
+		return parquet.Timestamp(parquet.Millisecond)
+	case kindString:
+		return parquet.String()
+	case kindInt:
+		return parquet.Int(64)
+	case kindBool:
+		return parquet.Leaf(parquet.BooleanType)
+	case kindFloat:
+		return parquet.Optional(parquet.Leaf(parquet.DoubleType))
+	}
+	return parquet.Leaf(parquet.DoubleType)
+}
+
+func parquetValue(value any) parquet.Value {
+	switch value := value.(type) {
+	case time.Time:
+		return parquet.Int64Value(value.UnixMilli())
+	case string:
+		return parquet.ByteArrayValue([]byte(value))
+	case decimal.Decimal:
+		return parquet.DoubleValue(value.InexactFloat64())
+	case float64:
+		return parquet.DoubleValue(value)
+	case int64:
+		return parquet.Int64Value(value)
+	case bool:
+		return parquet.BooleanValue(value)
+	}
+	return parquet.NullValue()
+}
+
+// formatText renders a value for the text formats, decimals keep their exact digits
+func formatText(value any, timeFormat string) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return fmt.Sprint(formatTimestamp(value, timeFormat))
+	case string:
+		return value
+	case decimal.Decimal:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return fmt.Sprint(value)
+}
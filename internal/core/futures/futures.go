@@ -0,0 +1,112 @@
+package futures
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
+)
+
+type FuturesService struct {
+	repository FuturesRepository
+	redis      redis.RedisServiceInterface
+	validator  validator.Validator
+}
+
+func NewFuturesService(redis redis.RedisServiceInterface) *FuturesService {
+	repository := NewFuturesRepository()
+	validator := validator.NewValidator()
+	return &FuturesService{
+		repository: *repository,
+		redis:      redis,
+		validator:  *validator,
+	}
+}
+
+func (service *FuturesService) GetFundingRates(request dto.FuturesRequestDto) ([]dto.FundingRateDto, error) {
+	if err := service.validator.ValidateCurrency(request.Currency); err != nil {
+		return nil, err
+	}
+	return service.repository.getFundingRates(request)
+}
+
+func (service *FuturesService) GetOpenInterest(request dto.FuturesRequestDto) ([]dto.OpenInterestDto, error) {
+	if err := service.validator.ValidateCurrency(request.Currency); err != nil {
+		return nil, err
+	}
+	return service.repository.getOpenInterest(request)
+}
+
+func (service *FuturesService) GetMarkPrices(request dto.FuturesRequestDto) ([]dto.MarkPriceDto, error) {
+	if err := service.validator.ValidateCurrency(request.Currency); err != nil {
+		return nil, err
+	}
+	return service.repository.getMarkPrices(request)
+}
+
+func (service *FuturesService) GetBasis(request dto.FuturesRequestDto) ([]dto.BasisDto, error) {
+	if err := service.validator.ValidateCurrency(request.Currency); err != nil {
+		return nil, err
+	}
+	return service.repository.getBasis(request)
+}
+
+// IngestHours stores the funding rates and 1h mark price klines of the perpetual contract within [from, to)
+func (service *FuturesService) IngestHours(currency string, from time.Time, to time.Time) error {
+	if err := service.validator.ValidateCurrency(currency); err != nil {
+		return err
+	}
+	from = from.UTC().Truncate(time.Hour)
+	to = to.UTC().Truncate(time.Hour)
+	if !from.Before(to) {
+		return fmt.Errorf("empty range %s - %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	fundingRates, err := binance.FetchFundingRates(currency, from, to.Add(-time.Millisecond))
+	if err != nil {
+		return err
+	}
+	if err := service.repository.upsertFundingRates(currency, fundingRates); err != nil {
+		return err
+	}
+
+	limit := min(int(to.Sub(from).Hours()), binance.MaxFuturesLimit)
+	markPrices, err := binance.FetchMarkPriceKlines(currency, config.ONE_HOUR, from, to.Add(-time.Millisecond), limit)
+	if err != nil {
+		return err
+	}
+	if err := service.repository.upsertMarkPrices(currency, markPrices); err != nil {
+		return err
+	}
+
+	log.Printf(config.COLOR_BLUE+"futures data currency:%s funding rates:%d mark prices:%d"+config.COLOR_RESET, currency, len(fundingRates), len(markPrices))
+	return service.publishEvent(config.EVENT_NEW_FUTURES_DATA_ADDED)
+}
+
+// CollectOpenInterest stores the current open interest of the perpetual contract, one row per minute
+func (service *FuturesService) CollectOpenInterest(currency string) (*dto.OpenInterestDto, error) {
+	if err := service.validator.ValidateCurrency(currency); err != nil {
+		return nil, err
+	}
+
+	openInterest, err := binance.FetchOpenInterest(currency)
+	if err != nil {
+		return nil, err
+	}
+	openInterest.Timestamp = openInterest.Timestamp.Truncate(time.Minute)
+	if err := service.repository.upsertOpenInterest(currency, openInterest); err != nil {
+		return nil, err
+	}
+	return openInterest, nil
+}
+
+func (service *FuturesService) publishEvent(eventName string) error {
+	ctx := context.Background()
+	return service.redis.PublishEvent(ctx, eventName, config.APPLICATION_NAME)
+}
@@ -0,0 +1,203 @@
+package futures
+
+import (
+	"fmt"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+	"github.com/shopspring/decimal"
+)
+
+type FuturesRepository struct {
+}
+
+func NewFuturesRepository() *FuturesRepository {
+	return &FuturesRepository{}
+}
+
+func (repository *FuturesRepository) getFundingRates(request dto.FuturesRequestDto) ([]dto.FundingRateDto, error) {
+	query, args := rangeQuery(fmt.Sprintf(`
+	SELECT id, symbol, timestamp, funding_rate, mark_price
+	FROM funding_rate_%s`, request.Currency), "timestamp", request)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching funding rates: %v", err)
+	}
+	defer rows.Close()
+
+	var records []dto.FundingRateDto
+	for rows.Next() {
+		var record dto.FundingRateDto
+		err := rows.Scan(&record.Id, &record.Symbol, &record.Timestamp, &record.FundingRate, &record.MarkPrice)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return records, nil
+}
+
+func (repository *FuturesRepository) getOpenInterest(request dto.FuturesRequestDto) ([]dto.OpenInterestDto, error) {
+	query, args := rangeQuery(fmt.Sprintf(`
+	SELECT id, symbol, timestamp, open_interest
+	FROM open_interest_%s`, request.Currency), "timestamp", request)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching open interest: %v", err)
+	}
+	defer rows.Close()
+
+	var records []dto.OpenInterestDto
+	for rows.Next() {
+		var record dto.OpenInterestDto
+		err := rows.Scan(&record.Id, &record.Symbol, &record.Timestamp, &record.OpenInterest)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return records, nil
+}
+
+func (repository *FuturesRepository) getMarkPrices(request dto.FuturesRequestDto) ([]dto.MarkPriceDto, error) {
+	query, args := rangeQuery(fmt.Sprintf(`
+	SELECT id, symbol, timestamp, open, high, low, close, is_complete
+	FROM mark_price_%s`, request.Currency), "timestamp", request)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching mark prices: %v", err)
+	}
+	defer rows.Close()
+
+	var records []dto.MarkPriceDto
+	for rows.Next() {
+		var record dto.MarkPriceDto
+		err := rows.Scan(&record.Id, &record.Symbol, &record.Timestamp, &record.Open, &record.High, &record.Low, &record.Close, &record.IsComplete)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return records, nil
+}
+
+// getBasis joins the mark price closes with the 1h spot closes of the same hours
+func (repository *FuturesRepository) getBasis(request dto.FuturesRequestDto) ([]dto.BasisDto, error) {
+	query, args := rangeQuery(fmt.Sprintf(`
+	SELECT mark.timestamp, spot.close AS spot_price, mark.close AS mark_price,
+	mark.close - spot.close AS basis, (mark.close - spot.close) / NULLIF(spot.close, 0) AS basis_rate
+	FROM mark_price_%s mark
+	JOIN %s spot ON spot.timestamp = mark.timestamp`, request.Currency, database.CandleTable(request.Currency, config.ONE_HOUR)), "mark.timestamp", request)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching basis: %v", err)
+	}
+	defer rows.Close()
+
+	var records []dto.BasisDto
+	for rows.Next() {
+		var record dto.BasisDto
+		var basisRate decimal.NullDecimal
+		err := rows.Scan(&record.Timestamp, &record.SpotPrice, &record.MarkPrice, &record.Basis, &basisRate)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		record.BasisRate = basisRate.Decimal
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return records, nil
+}
+
+func (repository *FuturesRepository) upsertFundingRates(currency string, records []*dto.FundingRateDto) error {
+	query := fmt.Sprintf(`
+		INSERT INTO funding_rate_%s (symbol, timestamp, funding_rate, mark_price)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (timestamp) DO UPDATE
+		SET funding_rate = EXCLUDED.funding_rate,
+			mark_price = EXCLUDED.mark_price`, currency)
+
+	for _, record := range records {
+		_, err := database.DB.Exec(query, record.Symbol, record.Timestamp, record.FundingRate, record.MarkPrice)
+		if err != nil {
+			return fmt.Errorf("💾 error upserting funding rate: %v", err)
+		}
+	}
+	return nil
+}
+
+func (repository *FuturesRepository) upsertOpenInterest(currency string, record *dto.OpenInterestDto) error {
+	query := fmt.Sprintf(`
+		INSERT INTO open_interest_%s (symbol, timestamp, open_interest)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (timestamp) DO UPDATE
+		SET open_interest = EXCLUDED.open_interest`, currency)
+
+	_, err := database.DB.Exec(query, record.Symbol, record.Timestamp, record.OpenInterest)
+	if err != nil {
+		return fmt.Errorf("💾 error upserting open interest: %v", err)
+	}
+	return nil
+}
+
+func (repository *FuturesRepository) upsertMarkPrices(currency string, records []*dto.MarkPriceDto) error {
+	query := fmt.Sprintf(`
+		INSERT INTO mark_price_%s (symbol, timestamp, open, high, low, close, is_complete)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (timestamp) DO UPDATE
+		SET open = EXCLUDED.open,
+			high = EXCLUDED.high,
+			low = EXCLUDED.low,
+			close = EXCLUDED.close,
+			is_complete = EXCLUDED.is_complete`, currency)
+
+	for _, record := range records {
+		_, err := database.DB.Exec(query, record.Symbol, record.Timestamp, record.Open, record.High, record.Low, record.Close, record.IsComplete)
+		if err != nil {
+			return fmt.Errorf("💾 error upserting mark price: %v", err)
+		}
+	}
+	return nil
+}
+
+// rangeQuery appends the time range, sort and limit of a request the way GetOHLC applies them
+func rangeQuery(query string, timestampColumn string, request dto.FuturesRequestDto) (string, []any) {
+	var args []any
+
+	if request.StartTime != nil {
+		query += fmt.Sprintf(" WHERE %s >= $1", timestampColumn)
+		args = append(args, request.StartTime)
+	}
+	if request.EndTime != nil {
+		if request.StartTime == nil {
+			query += fmt.Sprintf(" WHERE %s <= $1", timestampColumn)
+		} else {
+			query += fmt.Sprintf(" AND %s <= $2", timestampColumn)
+		}
+		args = append(args, request.EndTime)
+	}
+	query += fmt.Sprintf(` 
+	ORDER BY %s %s
+	LIMIT %d`, request.SortField, request.SortOrder, request.Limit)
+	return query, args
+}
@@ -0,0 +1,199 @@
+package gap
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/exchange"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+type GapService struct {
+	repository        GapStorage
+	marketDataService *marketdata.MarketDataService
+	indicatorService  *indicator.IndicatorService
+	validator         validator.Validator
+}
+
+type timeRange struct {
+	start time.Time
+	end   time.Time
+}
+
+func NewGapService(marketDataService *marketdata.MarketDataService, indicatorService *indicator.IndicatorService) *GapService {
+	validator := validator.NewValidator()
+	return &GapService{
+		repository:        NewGapStorage(),
+		marketDataService: marketDataService,
+		indicatorService:  indicatorService,
+		validator:         *validator,
+	}
+}
+
+// ScanAndRepair finds missing 1h records, re-fetches exactly those ranges from binance,
+// regroups the 4h/1d records they belong to and records the gaps that could not be filled.
+// A gap is left alone once GAP_MAX_ATTEMPTS scans failed to fill it, until it changes.
+func (service *GapService) ScanAndRepair(currency string) (*dto.GapReportDto, error) {
+	log.Printf(config.COLOR_BLUE+"scanning gaps currency:%s"+config.COLOR_RESET, currency)
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, config.ONE_HOUR); err != nil {
+		return nil, err
+	}
+
+	// the current hour is still forming, the last complete one is the latest that must exist
+	until := clock.Now().Truncate(time.Hour).Add(-1 * time.Hour)
+	missing, err := service.marketDataService.GetMissingTimestamps(currency, config.ONE_HOUR, until)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := service.repository.getGaps(currency, config.ONE_HOUR)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[time.Time]dto.GapDto, len(recorded))
+	for _, gap := range recorded {
+		previous[gap.StartTime.UTC()] = gap
+	}
+	maxAttempts := config.LoadConfig().GapMaxAttempts
+
+	report := &dto.GapReportDto{
+		Currency:     currency,
+		Timeframe:    config.ONE_HOUR,
+		MissingHours: len(missing),
+	}
+
+	var ranges []timeRange
+	for _, gap := range groupConsecutive(missing) {
+		if isExhausted(previous, gap, maxAttempts) {
+			log.Printf("gap %s - %s for %s is left alone after %d attempts", gap.start, gap.end, currency, maxAttempts)
+			continue
+		}
+		ranges = append(ranges, gap)
+		if err := service.fill(currency, gap); err != nil {
+			log.Printf("%sError filling gap %s - %s for %s: %s %s\n", config.COLOR_RED, gap.start, gap.end, currency, err, config.COLOR_RESET)
+		}
+	}
+
+	remaining := missing
+	if len(ranges) > 0 {
+		remaining, err = service.marketDataService.GetMissingTimestamps(currency, config.ONE_HOUR, until)
+		if err != nil {
+			return nil, err
+		}
+	}
+	report.FilledHours = len(missing) - len(remaining)
+
+	now := clock.Now()
+	for _, gap := range groupConsecutive(remaining) {
+		unfilled := dto.GapDto{
+			Currency:      currency,
+			Timeframe:     config.ONE_HOUR,
+			StartTime:     gap.start,
+			EndTime:       gap.end,
+			Attempts:      1,
+			DetectedAt:    now,
+			LastAttemptAt: now,
+		}
+		if recordedGap, ok := previous[gap.start.UTC()]; ok {
+			unfilled.DetectedAt = recordedGap.DetectedAt
+			unfilled.Attempts = recordedGap.Attempts + 1
+			if isExhausted(previous, gap, maxAttempts) {
+				unfilled.Attempts = recordedGap.Attempts
+				unfilled.LastAttemptAt = recordedGap.LastAttemptAt
+			}
+		}
+		report.Unfilled = append(report.Unfilled, unfilled)
+	}
+	if err := service.repository.replaceGaps(currency, config.ONE_HOUR, report.Unfilled); err != nil {
+		return nil, err
+	}
+
+	if report.FilledHours > 0 {
+		if err := service.regroup(currency, ranges); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("gaps %s: missing %d, filled %d, unfilled ranges %d", currency, report.MissingHours, report.FilledHours, len(report.Unfilled))
+	return report, nil
+}
+
+// GetGaps returns the gaps recorded by the last scan
+func (service *GapService) GetGaps(currency string) ([]dto.GapDto, error) {
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, config.ONE_HOUR); err != nil {
+		return nil, err
+	}
+	return service.repository.getGaps(currency, config.ONE_HOUR)
+}
+
+func (service *GapService) fill(currency string, gap timeRange) error {
+	source, err := exchange.GetSourceForCurrency(currency)
+	if err != nil {
+		return err
+	}
+
+	cursor := gap.start
+	for !cursor.After(gap.end) {
+		records, err := source.FetchKlineRange(currency, config.ONE_HOUR, cursor, gap.end, source.MaxKlineLimit())
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		accepted, err := service.marketDataService.ScreenData(currency, records)
+		if err != nil {
+			return err
+		}
+		if err := service.marketDataService.ImportBatchData(currency, accepted); err != nil {
+			return err
+		}
+		cursor = records[len(records)-1].Timestamp.Add(time.Hour)
+	}
+	return nil
+}
+
+// regroup rebuilds only the group records and indicators that contain a repaired hour
+func (service *GapService) regroup(currency string, ranges []timeRange) error {
+	for _, timeframe := range []string{config.FOUR_HOUR, config.ONE_DAY} {
+		for _, gap := range ranges {
+			if err := service.marketDataService.StoreGroupedRecordsBetween(currency, timeframe, gap.start, gap.end); err != nil {
+				return fmt.Errorf("gap->StoreGroupedRecordsBetween %s: %w", timeframe, err)
+			}
+			after, until := utils.GetGroupBoundaries(timeframe, gap.start, gap.end)
+			if err := service.indicatorService.DeleteRecordsBetween(currency, timeframe, after, until); err != nil {
+				return fmt.Errorf("gap->DeleteRecordsBetween %s: %w", timeframe, err)
+			}
+		}
+		if err := service.indicatorService.ComputeAndUpsertBatch(currency, timeframe); err != nil {
+			return fmt.Errorf("gap->ComputeAndUpsertBatch %s: %w", timeframe, err)
+		}
+	}
+	return nil
+}
+
+// isExhausted reports whether the gap is recorded unchanged and ran out of attempts
+func isExhausted(previous map[time.Time]dto.GapDto, gap timeRange, maxAttempts int) bool {
+	recordedGap, ok := previous[gap.start.UTC()]
+	return ok && recordedGap.EndTime.Equal(gap.end) && recordedGap.Attempts >= maxAttempts
+}
+
+func groupConsecutive(timestamps []time.Time) []timeRange {
+	var ranges []timeRange
+	for _, timestamp := range timestamps {
+		last := len(ranges) - 1
+		if last >= 0 && timestamp.Sub(ranges[last].end) == time.Hour {
+			ranges[last].end = timestamp
+			continue
+		}
+		ranges = append(ranges, timeRange{start: timestamp, end: timestamp})
+	}
+	return ranges
+}
@@ -0,0 +1,24 @@
+package gap
+
+import (
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// MemoryGapRepository keeps the gaps in a memory.Store with the semantics of the postgres GapRepository
+type MemoryGapRepository struct {
+	store *memory.Store
+}
+
+func NewMemoryGapRepository(store *memory.Store) *MemoryGapRepository {
+	return &MemoryGapRepository{store: store}
+}
+
+func (repository *MemoryGapRepository) getGaps(currency string, timeframe string) ([]dto.GapDto, error) {
+	return repository.store.Gaps(currency, timeframe), nil
+}
+
+func (repository *MemoryGapRepository) replaceGaps(currency string, timeframe string, gaps []dto.GapDto) error {
+	repository.store.ReplaceGaps(currency, timeframe, gaps)
+	return nil
+}
@@ -0,0 +1,85 @@
+package gap
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+	"github.com/lib/pq"
+)
+
+type GapRepository struct {
+}
+
+func NewGapRepository() *GapRepository {
+	return &GapRepository{}
+}
+
+func (repository *GapRepository) getGaps(currency, timeframe string) ([]dto.GapDto, error) {
+	query := `SELECT currency, timeframe, start_time, end_time, attempts, detected_at, last_attempt_at
+			  FROM data_gaps
+			  WHERE currency = $1 AND timeframe = $2
+			  ORDER BY start_time ASC`
+
+	rows, err := database.DB.Query(query, currency, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching gaps: %v", err)
+	}
+	defer rows.Close()
+
+	var gaps []dto.GapDto
+	for rows.Next() {
+		var gap dto.GapDto
+		err := rows.Scan(&gap.Currency, &gap.Timeframe, &gap.StartTime, &gap.EndTime, &gap.Attempts, &gap.DetectedAt, &gap.LastAttemptAt)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		gaps = append(gaps, gap)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+
+	return gaps, nil
+}
+
+// replaceGaps stores the gaps that could not be filled with their attempts and forgets the ones that are gone
+func (repository *GapRepository) replaceGaps(currency, timeframe string, gaps []dto.GapDto) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("💾 error starting transaction: %v", err)
+	}
+
+	startTimes := make([]time.Time, 0, len(gaps))
+	for _, gap := range gaps {
+		startTimes = append(startTimes, gap.StartTime)
+	}
+	_, err = tx.Exec(`DELETE FROM data_gaps WHERE currency = $1 AND timeframe = $2 AND NOT (start_time = ANY($3::timestamptz[]))`,
+		currency, timeframe, pq.Array(startTimes))
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("💾 error deleting gaps: %v", err)
+	}
+
+	for _, gap := range gaps {
+		_, err = tx.Exec(`INSERT INTO data_gaps (currency, timeframe, start_time, end_time, attempts, detected_at, last_attempt_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (currency, timeframe, start_time) DO UPDATE
+			SET end_time = EXCLUDED.end_time,
+			attempts = EXCLUDED.attempts,
+			last_attempt_at = EXCLUDED.last_attempt_at`,
+			currency, timeframe, gap.StartTime, gap.EndTime, gap.Attempts, gap.DetectedAt, gap.LastAttemptAt)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("💾 error upserting gap: %v", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("💾 error committing transaction: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package gap
+
+import (
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// GapStorage is implemented by the postgres GapRepository and the in-memory MemoryGapRepository
+type GapStorage interface {
+	getGaps(currency string, timeframe string) ([]dto.GapDto, error)
+	replaceGaps(currency string, timeframe string, gaps []dto.GapDto) error
+}
+
+// NewGapStorage returns the repository of the STORAGE_BACKEND, the timescale backend keeps its gaps in postgres
+func NewGapStorage() GapStorage {
+	if config.LoadConfig().StorageBackend == config.STORAGE_BACKEND_MEMORY {
+		return NewMemoryGapRepository(memory.DB)
+	}
+	return NewGapRepository()
+}
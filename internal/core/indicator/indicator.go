@@ -11,23 +11,28 @@ import (
 	"github.com/chyngyz-sydykov/marketpulse/config"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/archive"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+	"github.com/shopspring/decimal"
 )
 
 type IndicatorService struct {
-	repository IndicatorRepository
+	repository IndicatorStorage
 	validator  validator.Validator
 	redis      redis.RedisServiceInterface
 	currency   string
+	archive    *archive.Archive
 }
 
 func NewIndicatorService(redis redis.RedisServiceInterface) *IndicatorService {
-	repository := NewIndicatorRepository()
+	repository := NewIndicatorStorage()
 	validator := validator.NewValidator()
 	return &IndicatorService{
-		repository: *repository,
+		repository: repository,
 		validator:  *validator,
 		redis:      redis,
+		archive:    archive.NewArchive(config.LoadConfig().ArchiveDir),
 	}
 }
 func (service *IndicatorService) GetRecordsByRequest(indicatorRequestDto dto.IndicatorRequestDto) ([]dto.IndicatorDto, error) {
@@ -55,9 +60,10 @@ func (service *IndicatorService) ComputeAndUpsertBatch(currency string, timefram
 	}
 
 	hoursInGroup := config.HoursByTimeframe[timeframe]
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	oneHourRecordsChan, err := service.repository.StreamOneHourRecords(ctx, "btc", "1h")
+	oneHourRecordsChan, err := service.repository.StreamOneHourRecords(ctx, currency, utils.GetBaseTimeframe(timeframe))
 	if err != nil {
 		return fmt.Errorf("indicator->StreamOneHourRecords: %w", err)
 	}
@@ -91,6 +97,50 @@ func (service *IndicatorService) ComputeAndUpsertBatch(currency string, timefram
 
 }
 
+// DeleteRecordsBetween removes indicators in (after, until] so ComputeAndUpsertBatch computes them again
+func (service *IndicatorService) DeleteRecordsBetween(currency string, timeframe string, after time.Time, until time.Time) error {
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, timeframe); err != nil {
+		return err
+	}
+	return service.repository.deleteRecordsBetween(currency, timeframe, after, until)
+}
+
+// ArchiveRecordsBefore moves the indicators preceding "before" to the parquet archive one month at a time
+func (service *IndicatorService) ArchiveRecordsBefore(currency string, timeframe string, before time.Time) (int64, error) {
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, timeframe); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for {
+		first, err := service.repository.getFirstTimestamp(currency, timeframe)
+		if err != nil || first == nil || !first.Before(before) {
+			return deleted, err
+		}
+		monthEnd := utils.NextMonth(*first)
+		if before.Before(monthEnd) {
+			monthEnd = before
+		}
+		rowsAffected, err := service.archiveRecordsBefore(currency, timeframe, monthEnd)
+		deleted += rowsAffected
+		if err != nil || rowsAffected == 0 {
+			return deleted, err
+		}
+	}
+}
+
+// archiveRecordsBefore archives and deletes the indicators preceding monthEnd, the older months are archived already
+func (service *IndicatorService) archiveRecordsBefore(currency string, timeframe string, monthEnd time.Time) (int64, error) {
+	records, err := service.repository.getRecordsBefore(currency, timeframe, monthEnd)
+	if err != nil || len(records) == 0 {
+		return 0, err
+	}
+	if err := service.archive.WriteIndicators(currency, timeframe, records); err != nil {
+		return 0, err
+	}
+	return service.repository.deleteRecordsBefore(currency, timeframe, monthEnd)
+}
+
 func (service *IndicatorService) filter1HRecords(groupRecord dto.DataDto, oneHourRecordsChan <-chan dto.DataDto, hoursInGroup int) []dto.DataDto {
 
 	startTime := groupRecord.Timestamp.Add(-1 * time.Duration(hoursInGroup) * time.Hour)
@@ -114,9 +164,9 @@ func (service *IndicatorService) CalculateAllIndicators(history []dto.DataDto, i
 	var highPrices []float64
 	var lowPrices []float64
 	for _, record := range history {
-		closePrices = append(closePrices, record.Close)
-		highPrices = append(highPrices, record.High)
-		lowPrices = append(lowPrices, record.Low)
+		closePrices = append(closePrices, record.Close.InexactFloat64())
+		highPrices = append(highPrices, record.High.InexactFloat64())
+		lowPrices = append(lowPrices, record.Low.InexactFloat64())
 	}
 
 	// Compute each indicator using historical close prices
@@ -180,15 +230,15 @@ func (service *IndicatorService) TR(history []dto.DataDto, indicatorDto *dto.Ind
 	if err != nil && err != sql.ErrNoRows {
 		return
 	}
-	high := history[0].High
-	low := history[0].Low
+	high := history[0].High.InexactFloat64()
+	low := history[0].Low.InexactFloat64()
 	previousClose := 0.0
 	for i := 1; i < len(history); i++ {
-		high = math.Max(history[i].High, high)
-		low = math.Min(history[i].Low, low)
+		high = math.Max(history[i].High.InexactFloat64(), high)
+		low = math.Min(history[i].Low.InexactFloat64(), low)
 	}
 	if (previousData == dto.DataDto{}) {
-		previousClose = previousData.Close
+		previousClose = previousData.Close.InexactFloat64()
 	}
 
 	indicatorDto.TR = math.Max(high-low, math.Max(math.Abs(high-previousClose), math.Abs(low-previousClose)))
@@ -221,7 +271,7 @@ func (service *IndicatorService) RSI(prices []float64, indicatorDto *dto.Indicat
 	gain, loss := 0.0, 0.0
 	period := len(previousDataList)
 	for i := 1; i < period; i++ {
-		change := previousDataList[i].Close - previousDataList[i-1].Close
+		change := previousDataList[i].Close.Sub(previousDataList[i-1].Close).InexactFloat64()
 		if change > 0 {
 			gain += change
 		} else {
@@ -251,8 +301,12 @@ func (service *IndicatorService) RSI(prices []float64, indicatorDto *dto.Indicat
 // 	return indicator.EMA(macdValues, signalPeriod)
 // }
 
-func (service *IndicatorService) Trend(data *dto.DataDto) float64 {
-	return math.Round((data.Close-data.Open)/data.Open*10000) / 10000
+// Trend is the change of the close relative to the open, rounded to 4 decimal places
+func (service *IndicatorService) Trend(data *dto.DataDto) decimal.Decimal {
+	if data.Open.IsZero() {
+		return decimal.Zero
+	}
+	return data.Close.Sub(data.Open).Div(data.Open).Round(4)
 }
 func (service *IndicatorService) Volatility(highPrices []float64, lowPrices []float64, closePrices []float64, indicatorDto *dto.IndicatorDto) {
 	indicatorDto.Volatility = (highPrices[len(highPrices)-1] - lowPrices[len(lowPrices)-1]) / closePrices[len(highPrices)-1]
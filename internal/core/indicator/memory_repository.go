@@ -0,0 +1,141 @@
+package indicator
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// MemoryIndicatorRepository keeps indicators in a memory.Store with the semantics of the postgres IndicatorRepository
+type MemoryIndicatorRepository struct {
+	store *memory.Store
+}
+
+func NewMemoryIndicatorRepository(store *memory.Store) *MemoryIndicatorRepository {
+	return &MemoryIndicatorRepository{store: store}
+}
+
+func (repository *MemoryIndicatorRepository) GetRecordsByRequest(request dto.IndicatorRequestDto) ([]dto.IndicatorDto, error) {
+	var records []dto.IndicatorDto
+	for _, record := range repository.store.Indicators(request.Currency, request.Timeframe) {
+		if request.StartTime != nil && record.Timestamp.Before(*request.StartTime) {
+			continue
+		}
+		if request.EndTime != nil && record.Timestamp.After(*request.EndTime) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	if request.SortOrder == "DESC" {
+		slices.Reverse(records)
+	}
+	if request.Limit >= 0 && len(records) > int(request.Limit) {
+		records = records[:request.Limit]
+	}
+	return records, nil
+}
+
+// 1. Have no linked indicator record
+// 2. OR Have is_complete = false in data table
+func (repository *MemoryIndicatorRepository) GetUnprocessedMarketData(currency string, timeframe string) ([]dto.DataDto, error) {
+	linked := make(map[time.Time]bool)
+	for _, indicator := range repository.store.Indicators(currency, timeframe) {
+		linked[indicator.DataTimestamp] = true
+	}
+
+	var records []dto.DataDto
+	for _, record := range repository.store.Candles(currency, timeframe) {
+		if !record.IsComplete || !linked[record.Timestamp] {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (repository *MemoryIndicatorRepository) StreamOneHourRecords(ctx context.Context, currency string, timeframe string) (<-chan dto.DataDto, error) {
+	records := repository.store.Candles(currency, timeframe)
+
+	dataChan := make(chan dto.DataDto)
+	go func() {
+		defer close(dataChan)
+		for _, record := range records {
+			select {
+			case dataChan <- record:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return dataChan, nil
+}
+
+func (repository *MemoryIndicatorRepository) getLastRecord(currency string, timeframe string) (*dto.IndicatorDto, error) {
+	record, ok := repository.store.LastIndicator(currency, timeframe)
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (repository *MemoryIndicatorRepository) getPreviousMarketData(currency string, timeframe string, timestamp time.Time) (dto.DataDto, error) {
+	records, _ := repository.getPreviousMarketDataList(currency, timeframe, timestamp, 1)
+	if len(records) == 0 {
+		return dto.DataDto{}, nil
+	}
+	return records[0], nil
+}
+
+// getPreviousMarketDataList returns up to limit records preceding timestamp, the latest first
+func (repository *MemoryIndicatorRepository) getPreviousMarketDataList(currency string, timeframe string, timestamp time.Time, limit int) ([]dto.DataDto, error) {
+	return repository.store.CandlesBefore(currency, timeframe, timestamp, limit), nil
+}
+
+// getRecordsBefore returns the indicators preceding "before" in chronological order
+func (repository *MemoryIndicatorRepository) getRecordsBefore(currency string, timeframe string, before time.Time) ([]dto.IndicatorDto, error) {
+	var records []dto.IndicatorDto
+	for _, record := range repository.store.Indicators(currency, timeframe) {
+		if record.Timestamp.Before(before) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (repository *MemoryIndicatorRepository) getFirstTimestamp(currency string, timeframe string) (*time.Time, error) {
+	records := repository.store.Indicators(currency, timeframe)
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return &records[0].Timestamp, nil
+}
+
+func (repository *MemoryIndicatorRepository) deleteRecordsBefore(currency string, timeframe string, before time.Time) (int64, error) {
+	rowsAffected := repository.store.DeleteIndicators(currency, timeframe, func(record dto.IndicatorDto) bool {
+		return record.Timestamp.Before(before)
+	})
+
+	log.Printf("💾 ✅ deleted indicators %d", rowsAffected)
+	return int64(rowsAffected), nil
+}
+
+func (repository *MemoryIndicatorRepository) deleteRecordsBetween(currency string, timeframe string, after time.Time, until time.Time) error {
+	rowsAffected := repository.store.DeleteIndicators(currency, timeframe, func(record dto.IndicatorDto) bool {
+		return record.Timestamp.After(after) && !record.Timestamp.After(until)
+	})
+
+	log.Printf("💾 ✅ deleted indicators %d", rowsAffected)
+	return nil
+}
+
+func (repository *MemoryIndicatorRepository) upsertBatchByTimeFrame(currency string, timeFrame string, records []*dto.IndicatorDto) error {
+	repository.store.UpsertIndicators(currency, timeFrame, records)
+
+	log.Printf("💾 ✅ upsert batch indicator %d, %d", len(records), len(records))
+	return nil
+}
@@ -13,15 +13,37 @@ import (
 )
 
 type IndicatorRepository struct {
+	timescale bool // candles are read from the ts_data_ hypertables and continuous aggregates, indicators are stored in ts_indicator_ tables
 }
 
 func NewIndicatorRepository() *IndicatorRepository {
 	return &IndicatorRepository{}
 }
+
+func NewTimescaleIndicatorRepository() *IndicatorRepository {
+	return &IndicatorRepository{timescale: true}
+}
+
+// source is the table or continuous aggregate the candles of a timeframe are read from
+func (repository *IndicatorRepository) source(currency string, timeframe string) string {
+	if repository.timescale {
+		return database.TimescaleCandleSource(currency, timeframe)
+	}
+	return fmt.Sprintf("data_%s_%s", currency, timeframe)
+}
+
+// table is the table the indicators of a timeframe are stored in
+func (repository *IndicatorRepository) table(currency string, timeframe string) string {
+	if repository.timescale {
+		return fmt.Sprintf("ts_indicator_%s_%s", currency, timeframe)
+	}
+	return fmt.Sprintf("indicator_%s_%s", currency, timeframe)
+}
+
 func (indicator *IndicatorRepository) GetRecordsByRequest(request dto.IndicatorRequestDto) ([]dto.IndicatorDto, error) {
 	query := fmt.Sprintf(`
 	SELECT id, timeframe, timestamp, sma, ema, tr, std_dev, lower_bollinger, upper_bollinger, volatility, rsi, macd, macd_signal, data_timestamp
-	FROM indicator_%s_%s`, request.Currency, request.Timeframe)
+	FROM %s`, indicator.table(request.Currency, request.Timeframe))
 
 	var args []any
 
@@ -71,15 +93,15 @@ func (repo *IndicatorRepository) GetUnprocessedMarketData(currency, timeframe st
 	db := database.DB
 
 	query := fmt.Sprintf(`
-		SELECT d.*
-		FROM data_%s_%s d
+		SELECT d.id, d.symbol, d.timeframe, d.timestamp, d.open, d.close, d.high, d.low, d.volume, d.trend, d.is_complete
+		FROM %s d
 		WHERE d.is_complete = false
 		OR NOT EXISTS (
-			SELECT 1 FROM indicator_%s_%s i
+			SELECT 1 FROM %s i
 			WHERE i.data_timestamp = d.timestamp
 		)
 		ORDER BY d.timestamp ASC;
-	`, currency, timeframe, currency, timeframe)
+	`, repo.source(currency, timeframe), repo.table(currency, timeframe))
 
 	rows, err := db.Query(query)
 	if err != nil {
@@ -103,9 +125,9 @@ func (repo *IndicatorRepository) GetUnprocessedMarketData(currency, timeframe st
 func (repository *IndicatorRepository) StreamOneHourRecords(ctx context.Context, currency, timeframe string) (<-chan dto.DataDto, error) {
 	db := database.DB
 	query := fmt.Sprintf(`
-		SELECT id, symbol, timeframe, timestamp, open, close, low, high, volume, trend, is_complete FROM data_%s_%s
+		SELECT id, symbol, timeframe, timestamp, open, close, low, high, volume, trend, is_complete FROM %s
 		ORDER BY timestamp ASC
-	`, currency, timeframe)
+	`, repository.source(currency, timeframe))
 
 	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
@@ -133,7 +155,7 @@ func (repository *IndicatorRepository) StreamOneHourRecords(ctx context.Context,
 
 func (repository *IndicatorRepository) getLastRecord(currency, timeframe string) (*dto.IndicatorDto, error) {
 	query := fmt.Sprintf(`SELECT timeframe, timestamp, sma, ema, std_dev, lower_bollinger, upper_bollinger, volatility, rsi, macd, macd_signal, data_timestamp 
-						  FROM indicator_%s_%s ORDER BY timestamp DESC LIMIT 1`, currency, timeframe)
+						  FROM %s ORDER BY timestamp DESC LIMIT 1`, repository.table(currency, timeframe))
 
 	row := database.DB.QueryRow(query)
 
@@ -153,11 +175,11 @@ func (repository *IndicatorRepository) getLastRecord(currency, timeframe string)
 func (indicator *IndicatorRepository) getPreviousMarketData(currency, timeframe string, timestamp time.Time) (dto.DataDto, error) {
 	query := fmt.Sprintf(`
 		SELECT id, symbol, timeframe, timestamp, open, close, low, high, volume, trend, is_complete
-		FROM data_%s_%s
+		FROM %s
 		WHERE timestamp < $1
 		ORDER BY timestamp DESC
 		LIMIT 1
-	`, currency, timeframe)
+	`, indicator.source(currency, timeframe))
 
 	row := database.DB.QueryRow(query, timestamp)
 
@@ -176,11 +198,11 @@ func (indicator *IndicatorRepository) getPreviousMarketData(currency, timeframe
 func (indicator *IndicatorRepository) getPreviousMarketDataList(currency, timeframe string, timestamp time.Time, limit int) ([]dto.DataDto, error) {
 	query := fmt.Sprintf(`
 		SELECT id, symbol, timeframe, timestamp, open, close, low, high, volume, trend, is_complete
-		FROM data_%s_%s
+		FROM %s
 		WHERE timestamp < $1
 		ORDER BY timestamp DESC
 		LIMIT $2
-	`, currency, timeframe)
+	`, indicator.source(currency, timeframe))
 
 	rows, err := database.DB.Query(query, timestamp, limit)
 
@@ -206,12 +228,102 @@ func (indicator *IndicatorRepository) getPreviousMarketDataList(currency, timefr
 	return records, nil
 }
 
+// getRecordsBefore returns the indicators preceding "before" in chronological order
+func (repository *IndicatorRepository) getRecordsBefore(currency string, timeframe string, before time.Time) ([]dto.IndicatorDto, error) {
+	query := fmt.Sprintf(`SELECT id, timeframe, timestamp, sma, ema, tr, std_dev, lower_bollinger, upper_bollinger, volatility, rsi, macd, macd_signal, data_timestamp
+						  FROM %s WHERE timestamp < $1
+						  ORDER BY timestamp ASC`, repository.table(currency, timeframe))
+
+	rows, err := database.DB.Query(query, before)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching expired indicators: %v", err)
+	}
+	defer rows.Close()
+
+	var indicators []dto.IndicatorDto
+	for rows.Next() {
+		var indicator dto.IndicatorDto
+		err := rows.Scan(&indicator.Id, &indicator.Timeframe, &indicator.Timestamp, &indicator.SMA, &indicator.EMA, &indicator.TR, &indicator.StdDev, &indicator.LowerBollinger, &indicator.UpperBollinger, &indicator.Volatility, &indicator.RSI, &indicator.MACD, &indicator.MACDSignal, &indicator.DataTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		indicators = append(indicators, indicator)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return indicators, nil
+}
+
+// getFirstTimestamp returns the timestamp of the oldest stored indicator, nil when there is none
+func (repository *IndicatorRepository) getFirstTimestamp(currency string, timeframe string) (*time.Time, error) {
+	query := fmt.Sprintf(`SELECT MIN(timestamp) FROM %s`, repository.table(currency, timeframe))
+
+	var first sql.NullTime
+	if err := database.DB.QueryRow(query).Scan(&first); err != nil {
+		return nil, fmt.Errorf("💾 error fetching first indicator: %v", err)
+	}
+	if !first.Valid {
+		return nil, nil
+	}
+	return &first.Time, nil
+}
+
+func (repository *IndicatorRepository) deleteRecordsBefore(currency string, timeframe string, before time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE timestamp < $1`, repository.table(currency, timeframe))
+
+	result, err := database.DB.Exec(query, before)
+	if err != nil {
+		return 0, fmt.Errorf("💾 error deleting expired indicators: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+
+	log.Printf("💾 ✅ deleted indicators %d", rowsAffected)
+	return rowsAffected, nil
+}
+
+func (repository *IndicatorRepository) deleteRecordsBetween(currency string, timeframe string, after time.Time, until time.Time) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE timestamp > $1 AND timestamp <= $2`, repository.table(currency, timeframe))
+
+	result, err := database.DB.Exec(query, after, until)
+	if err != nil {
+		return fmt.Errorf("💾 error deleting indicators: %v", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+
+	log.Printf("💾 ✅ deleted indicators %d", rowsAffected)
+	return nil
+}
+
 func (repository *IndicatorRepository) upsertBatchByTimeFrame(currency string, timeFrame string, records []*dto.IndicatorDto) error {
 	tx, err := database.DB.Begin()
 	if err != nil {
 		log.Println("💾 Error starting transaction:", err)
 		return err
 	}
+
+	var rowsAffected int64
+	for _, batch := range database.Batches(records, database.UpsertBatchSize) {
+		affected, err := repository.upsertBatch(tx, currency, timeFrame, batch)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		rowsAffected += affected
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("💾 error committing transaction: %v", err)
+	}
+
+	log.Printf("💾 ✅ upsert batch indicator %d, %d", len(records), rowsAffected)
+	return nil
+}
+
+// upsertBatch upserts up to database.UpsertBatchSize indicators with a single multi-row INSERT
+func (repository *IndicatorRepository) upsertBatch(tx *sql.Tx, currency string, timeFrame string, records []*dto.IndicatorDto) (int64, error) {
 	var values []interface{}
 	var placeholders []string
 
@@ -222,7 +334,7 @@ func (repository *IndicatorRepository) upsertBatchByTimeFrame(currency string, t
 	}
 
 	query := fmt.Sprintf(`
-		INSERT INTO indicator_%s_%s (timeframe, timestamp, sma, ema, tr, std_dev, lower_bollinger, upper_bollinger, volatility, rsi, macd, macd_signal, data_timestamp)
+		INSERT INTO %s (timeframe, timestamp, sma, ema, tr, std_dev, lower_bollinger, upper_bollinger, volatility, rsi, macd, macd_signal, data_timestamp)
 		VALUES %s
 		ON CONFLICT (timestamp) DO UPDATE SET
 			sma = EXCLUDED.sma,
@@ -236,21 +348,11 @@ func (repository *IndicatorRepository) upsertBatchByTimeFrame(currency string, t
 			macd = EXCLUDED.macd,
 			macd_signal = EXCLUDED.macd_signal,
 			data_timestamp = EXCLUDED.data_timestamp
-	`, currency, timeFrame, strings.Join(placeholders, ","))
+	`, repository.table(currency, timeFrame), strings.Join(placeholders, ","))
 
 	result, err := tx.Exec(query, values...)
-
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("💾 error batch upsert: %v", err)
+		return 0, fmt.Errorf("💾 error batch upsert: %v", err)
 	}
-
-	err = tx.Commit()
-	if err != nil {
-		return fmt.Errorf("💾 error committing transaction: %v", err)
-	}
-	rowsAffected, _ := result.RowsAffected()
-
-	log.Printf("💾 ✅ upsert batch indicator %d, %d", len(records), rowsAffected)
-	return nil
+	return result.RowsAffected()
 }
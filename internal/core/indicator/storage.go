@@ -0,0 +1,37 @@
+package indicator
+
+import (
+	"context"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// IndicatorStorage is implemented by the postgres IndicatorRepository (on plain or timescale tables)
+// and the in-memory MemoryIndicatorRepository
+type IndicatorStorage interface {
+	GetRecordsByRequest(request dto.IndicatorRequestDto) ([]dto.IndicatorDto, error)
+	GetUnprocessedMarketData(currency string, timeframe string) ([]dto.DataDto, error)
+	StreamOneHourRecords(ctx context.Context, currency string, timeframe string) (<-chan dto.DataDto, error)
+	getLastRecord(currency string, timeframe string) (*dto.IndicatorDto, error)
+	getPreviousMarketData(currency string, timeframe string, timestamp time.Time) (dto.DataDto, error)
+	getPreviousMarketDataList(currency string, timeframe string, timestamp time.Time, limit int) ([]dto.DataDto, error)
+	getRecordsBefore(currency string, timeframe string, before time.Time) ([]dto.IndicatorDto, error)
+	getFirstTimestamp(currency string, timeframe string) (*time.Time, error)
+	deleteRecordsBefore(currency string, timeframe string, before time.Time) (int64, error)
+	deleteRecordsBetween(currency string, timeframe string, after time.Time, until time.Time) error
+	upsertBatchByTimeFrame(currency string, timeFrame string, records []*dto.IndicatorDto) error
+}
+
+// NewIndicatorStorage returns the repository of the STORAGE_BACKEND
+func NewIndicatorStorage() IndicatorStorage {
+	switch config.LoadConfig().StorageBackend {
+	case config.STORAGE_BACKEND_MEMORY:
+		return NewMemoryIndicatorRepository(memory.DB)
+	case config.STORAGE_BACKEND_TIMESCALE:
+		return NewTimescaleIndicatorRepository()
+	}
+	return NewIndicatorRepository()
+}
@@ -3,8 +3,10 @@ package marketdata
 import (
 	"time"
 
+	"github.com/chyngyz-sydykov/marketpulse/config"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/shopspring/decimal"
 )
 
 type Aggregator struct {
@@ -47,12 +49,9 @@ func (a *Aggregator) createCompleteAggregatedRecord(records []dto.DataDto, timef
 func (a *Aggregator) createIncompleteAggregatedRecord(records []dto.DataDto, timeframe string) *dto.DataDto {
 	aggregatedRecord := a.aggregate(records, timeframe)
 	recordTimestamp := aggregatedRecord.Timestamp
-	if timeframe == "4h" {
-		// set the timestamp to nearest future 4h timestamp (4h, 8h, 12h, ...)
-		aggregatedRecord.Timestamp = recordTimestamp.Truncate(4 * time.Hour).Add(4 * time.Hour)
-	} else if timeframe == "1d" {
-		// set the timestamp to nearest future 1d timestamp
-		aggregatedRecord.Timestamp = recordTimestamp.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	if groupDuration := time.Duration(config.HoursByTimeframe[timeframe]) * time.Hour; groupDuration > time.Hour {
+		// set the timestamp to nearest future group timestamp (4h, 8h, 12h, ... or the next day)
+		aggregatedRecord.Timestamp = recordTimestamp.Truncate(groupDuration).Add(groupDuration)
 	}
 	aggregatedRecord.Trend = a.indicator.Trend(aggregatedRecord)
 	aggregatedRecord.IsComplete = false
@@ -60,7 +59,7 @@ func (a *Aggregator) createIncompleteAggregatedRecord(records []dto.DataDto, tim
 }
 
 func (a *Aggregator) aggregate(group []dto.DataDto, timeFrame string) *dto.DataDto {
-	return &dto.DataDto{
+	record := &dto.DataDto{
 		Timeframe: timeFrame,
 		Symbol:    group[0].Symbol,
 		Timestamp: group[len(group)-1].Timestamp,
@@ -69,33 +68,60 @@ func (a *Aggregator) aggregate(group []dto.DataDto, timeFrame string) *dto.DataD
 		Close:     group[len(group)-1].Close,
 		Open:      group[0].Open,
 		Volume:    a.totalVolume(group),
+
+		QuoteVolume:         a.sum(group, func(data dto.DataDto) decimal.Decimal { return data.QuoteVolume }),
+		TradeCount:          a.totalTradeCount(group),
+		TakerBuyBaseVolume:  a.sum(group, func(data dto.DataDto) decimal.Decimal { return data.TakerBuyBaseVolume }),
+		TakerBuyQuoteVolume: a.sum(group, func(data dto.DataDto) decimal.Decimal { return data.TakerBuyQuoteVolume }),
+
+		MedianPrice: group[len(group)-1].MedianPrice,
 	}
+	if record.Volume.IsPositive() {
+		record.Vwap = record.QuoteVolume.Div(record.Volume)
+	}
+	return record
 }
 
-func (a *Aggregator) maxHigh(group []dto.DataDto) float64 {
+func (a *Aggregator) maxHigh(group []dto.DataDto) decimal.Decimal {
 	max := group[0].High
 	for _, data := range group {
-		if data.High > max {
+		if data.High.GreaterThan(max) {
 			max = data.High
 		}
 	}
 	return max
 }
 
-func (a *Aggregator) minLow(group []dto.DataDto) float64 {
+func (a *Aggregator) minLow(group []dto.DataDto) decimal.Decimal {
 	min := group[0].Low
 	for _, data := range group {
-		if data.Low < min {
+		if data.Low.LessThan(min) {
 			min = data.Low
 		}
 	}
 	return min
 }
 
-func (a *Aggregator) totalVolume(group []dto.DataDto) float64 {
-	total := 0.0
+func (a *Aggregator) totalVolume(group []dto.DataDto) decimal.Decimal {
+	total := decimal.Zero
+	for _, data := range group {
+		total = total.Add(data.Volume)
+	}
+	return total
+}
+
+func (a *Aggregator) totalTradeCount(group []dto.DataDto) int64 {
+	var total int64
+	for _, data := range group {
+		total += data.TradeCount
+	}
+	return total
+}
+
+func (a *Aggregator) sum(group []dto.DataDto, value func(data dto.DataDto) decimal.Decimal) decimal.Decimal {
+	total := decimal.Zero
 	for _, data := range group {
-		total += data.Volume
+		total = total.Add(value(data))
 	}
 	return total
 }
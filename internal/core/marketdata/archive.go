@@ -0,0 +1,130 @@
+package marketdata
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+// ArchiveRecordsBefore moves the records preceding "before" to the parquet archive one month at a time. Expiring 1h
+// records are grouped into the longer timeframes first so their downsampled history outlives them.
+func (service *MarketDataService) ArchiveRecordsBefore(currency string, timeframe string, before time.Time) (int64, error) {
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, timeframe); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for {
+		first, err := service.repository.getFirstTimestamp(currency, timeframe)
+		if err != nil || first == nil || !first.Before(before) {
+			return deleted, err
+		}
+		// the first hour of the next month closes the groups labeled with its start, a chunk never splits a group
+		monthEnd := utils.NextMonth(*first).Add(time.Hour)
+		if before.Before(monthEnd) {
+			monthEnd = before
+		}
+		rowsAffected, err := service.archiveRecordsBefore(currency, timeframe, monthEnd)
+		deleted += rowsAffected
+		if err != nil || rowsAffected == 0 {
+			return deleted, err
+		}
+	}
+}
+
+// archiveRecordsBefore archives and deletes the records preceding monthEnd, the older months are archived already
+func (service *MarketDataService) archiveRecordsBefore(currency string, timeframe string, monthEnd time.Time) (int64, error) {
+	records, err := service.repository.getRecordsBefore(currency, timeframe, monthEnd)
+	if err != nil || len(records) == 0 {
+		return 0, err
+	}
+
+	if utils.GetBaseTimeframe(timeframe) == timeframe {
+		for _, groupingTimeframe := range groupingTimeframes(timeframe) {
+			if err := service.StoreGroupedRecordsBetween(currency, groupingTimeframe, records[0].Timestamp, records[len(records)-1].Timestamp); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := service.archive.WriteCandles(currency, timeframe, records); err != nil {
+		return 0, err
+	}
+	return service.repository.deleteRecordsBefore(currency, timeframe, monthEnd)
+}
+
+// withArchivedRecords merges the archived records of the requested range into the stored ones. The archive is
+// skipped when the request cannot reach past the retention cutoff of the timeframe, otherwise only the months
+// before the cutoff and before the oldest stored record are read, and no more of them than the limit needs.
+func (service *MarketDataService) withArchivedRecords(request dto.OHLCRequestDto, records []dto.DataDto) ([]dto.DataDto, error) {
+	cutoff, ok, err := utils.RetentionCutoff(request.Timeframe)
+	if err != nil {
+		log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+		return records, nil
+	}
+	if !ok || (request.StartTime != nil && !request.StartTime.Before(cutoff)) {
+		return records, nil
+	}
+
+	first, err := service.repository.getFirstTimestamp(request.Currency, request.Timeframe)
+	if err != nil {
+		return records, err
+	}
+	end := cutoff
+	if first != nil && first.Before(end) {
+		end = *first
+	}
+	if request.StartTime != nil && !request.StartTime.Before(end) {
+		return records, nil
+	}
+	to := end.Add(-time.Nanosecond)
+	if request.EndTime != nil && request.EndTime.Before(to) {
+		to = *request.EndTime
+	}
+
+	// the archived records all precede the stored ones, sorted by timestamp the limit is reached before reading them all
+	limit := -1
+	descending := request.SortOrder == "DESC"
+	if request.SortField == "timestamp" && request.Limit >= 0 {
+		limit = int(request.Limit)
+		if descending {
+			limit -= len(records)
+		}
+		if limit <= 0 {
+			return records, nil
+		}
+	}
+
+	archived, err := service.archive.ReadCandles(request.Currency, request.Timeframe, request.StartTime, &to, limit, descending)
+	if err != nil || len(archived) == 0 {
+		return records, err
+	}
+	records = append(records, archived...)
+
+	slices.SortStableFunc(records, func(a, b dto.DataDto) int {
+		order := compareBySortField(a, b, request.SortField)
+		if request.SortOrder == "DESC" {
+			return -order
+		}
+		return order
+	})
+	if request.Limit >= 0 && len(records) > int(request.Limit) {
+		records = records[:request.Limit]
+	}
+	return records, nil
+}
+
+// groupingTimeframes returns the tracked timeframes grouped from a 1h timeframe, e.g. 1h => 4h, 1d
+func groupingTimeframes(baseTimeframe string) []string {
+	var timeframes []string
+	for _, timeframe := range slices.Concat(config.DefaultTimeframes, config.CompositeTimeframes) {
+		if timeframe != baseTimeframe && utils.GetBaseTimeframe(timeframe) == baseTimeframe {
+			timeframes = append(timeframes, timeframe)
+		}
+	}
+	return timeframes
+}
@@ -3,34 +3,39 @@ package marketdata
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/chyngyz-sydykov/marketpulse/config"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
 	aggregator "github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata/aggregator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/archive"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
 )
 
 type MarketDataService struct {
-	repository MarketDataRepository
+	repository MarketDataStorage
 	aggregator aggregator.Aggregator
 	indicator  indicator.IndicatorService
 	redis      redis.RedisServiceInterface
 	validator  validator.Validator
+	archive    *archive.Archive
 }
 
 func NewMarketDataService(redis redis.RedisServiceInterface) *MarketDataService {
-	repository := NewMarketDataRepository()
+	repository := NewMarketDataStorage()
 	indicator := indicator.NewIndicatorService(redis)
 	aggregator := aggregator.NewAggregator(indicator)
 	validator := validator.NewValidator()
 	return &MarketDataService{
-		repository: *repository,
+		repository: repository,
 		aggregator: *aggregator,
 		indicator:  *indicator,
 		redis:      redis,
 		validator:  *validator,
+		archive:    archive.NewArchive(config.LoadConfig().ArchiveDir),
 	}
 }
 
@@ -38,7 +43,11 @@ func (service *MarketDataService) GetRecordsByRequest(ohlcRequestDto dto.OHLCReq
 	if err := service.validator.ValidateCurrencyAndTimeframe(ohlcRequestDto.Currency, ohlcRequestDto.Timeframe); err != nil {
 		return nil, err
 	}
-	return service.repository.GetRecordsByRequest(ohlcRequestDto)
+	records, err := service.repository.GetRecordsByRequest(ohlcRequestDto)
+	if err != nil {
+		return nil, err
+	}
+	return service.withArchivedRecords(ohlcRequestDto, records)
 }
 
 func (service *MarketDataService) StoreData(currency string, data *dto.DataDto) error {
@@ -66,24 +75,32 @@ func (service *MarketDataService) StoreData(currency string, data *dto.DataDto)
 }
 
 func (service *MarketDataService) UpsertBatchData(currency string, data []*dto.DataDto) error {
-	timeFrame := data[0].Timeframe
-	if err := service.validator.ValidateCurrencyAndTimeframe(currency, timeFrame); err != nil {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := service.ImportBatchData(currency, data); err != nil {
 		return err
 	}
+	return service.publishEvent(config.EVENT_NEW_DATA_ADDED)
+}
 
+// ImportBatchData upserts records without publishing EVENT_NEW_DATA_ADDED,
+// the caller is responsible for grouping the records and computing indicators once the import is done
+func (service *MarketDataService) ImportBatchData(currency string, data []*dto.DataDto) error {
 	if len(data) == 0 {
 		return nil
 	}
 
-	for _, record := range data {
-		record.Trend = service.indicator.Trend(record)
+	timeFrame := data[0].Timeframe
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, timeFrame); err != nil {
+		return err
 	}
 
-	if err := service.repository.upsertBatchByTimeFrame(currency, timeFrame, data); err != nil {
-		return err
+	for _, record := range data {
+		record.Trend = service.indicator.Trend(record)
 	}
 
-	return service.publishEvent(config.EVENT_NEW_DATA_ADDED)
+	return service.repository.upsertBatchByTimeFrame(currency, timeFrame, data)
 }
 
 func (service *MarketDataService) StoreGroupedRecords(currency string, groupingTimeframe string) error {
@@ -96,20 +113,78 @@ func (service *MarketDataService) StoreGroupedRecords(currency string, groupingT
 	if err != nil {
 		return err
 	}
-	hoursInGroup := config.HoursByTimeframe[groupingTimeframe]
+
+	var from *time.Time
+	if lastCompleteGroupRecord != nil {
+		from = &lastCompleteGroupRecord.Timestamp
+	}
+	if derived, err := service.deriveGroups(currency, groupingTimeframe, from, nil); derived || err != nil {
+		return err
+	}
 
 	var oneHourRecords []dto.DataDto
 	if lastCompleteGroupRecord == nil {
 		// No previous 4-hour record → Fetch all 1-hour records within the last 4-hour period
-		oneHourRecords, err = service.repository.getRecords(currency, config.ONE_HOUR) // Get all
+		oneHourRecords, err = service.repository.getRecords(currency, utils.GetBaseTimeframe(groupingTimeframe)) // Get all
 	} else {
 		// Fetch only 1-hour records after the last 4-hour timestamp
-		oneHourRecords, err = service.repository.getCompleteRecordsAfter(currency, config.ONE_HOUR, lastCompleteGroupRecord.Timestamp)
+		oneHourRecords, err = service.repository.getCompleteRecordsAfter(currency, utils.GetBaseTimeframe(groupingTimeframe), lastCompleteGroupRecord.Timestamp)
 	}
 	if err != nil || len(oneHourRecords) == 0 {
 		return nil
 	}
+	return service.storeGroupedRecords(currency, groupingTimeframe, oneHourRecords)
+}
+
+// StoreGroupedRecordsFrom regroups every 1h record starting with the group that contains "from",
+// used after importing history that is older than the last complete group record
+func (service *MarketDataService) StoreGroupedRecordsFrom(currency string, groupingTimeframe string, from time.Time) error {
+	log.Printf(config.COLOR_BLUE+"regrouping records currency:%s timeframe:%s from:%s"+config.COLOR_RESET, currency, groupingTimeframe, from)
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, groupingTimeframe); err != nil {
+		return err
+	}
 
+	if derived, err := service.deriveGroups(currency, groupingTimeframe, &from, nil); derived || err != nil {
+		return err
+	}
+
+	previousGroupEnd, _ := utils.GetGroupBoundaries(groupingTimeframe, from, from)
+	oneHourRecords, err := service.repository.getCompleteRecordsAfter(currency, utils.GetBaseTimeframe(groupingTimeframe), previousGroupEnd)
+	if err != nil || len(oneHourRecords) == 0 {
+		return err
+	}
+	return service.storeGroupedRecords(currency, groupingTimeframe, oneHourRecords)
+}
+
+// StoreGroupedRecordsBetween regroups only the groups that contain 1h records between "from" and "to"
+func (service *MarketDataService) StoreGroupedRecordsBetween(currency string, groupingTimeframe string, from time.Time, to time.Time) error {
+	log.Printf(config.COLOR_BLUE+"regrouping records currency:%s timeframe:%s from:%s to:%s"+config.COLOR_RESET, currency, groupingTimeframe, from, to)
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, groupingTimeframe); err != nil {
+		return err
+	}
+
+	if derived, err := service.deriveGroups(currency, groupingTimeframe, &from, &to); derived || err != nil {
+		return err
+	}
+
+	after, until := utils.GetGroupBoundaries(groupingTimeframe, from, to)
+	oneHourRecords, err := service.repository.getCompleteRecordsBetween(currency, utils.GetBaseTimeframe(groupingTimeframe), after, until)
+	if err != nil || len(oneHourRecords) == 0 {
+		return err
+	}
+	return service.storeGroupedRecords(currency, groupingTimeframe, oneHourRecords)
+}
+
+// GetMissingTimestamps returns every timestamp between the first stored record and "until" that has no record
+func (service *MarketDataService) GetMissingTimestamps(currency string, timeframe string, until time.Time) ([]time.Time, error) {
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, timeframe); err != nil {
+		return nil, err
+	}
+	return service.repository.getMissingTimestamps(currency, timeframe, until)
+}
+
+func (service *MarketDataService) storeGroupedRecords(currency string, groupingTimeframe string, oneHourRecords []dto.DataDto) error {
+	hoursInGroup := config.HoursByTimeframe[groupingTimeframe]
 	aggregatedRecords, err := service.aggregator.GroupRecords(oneHourRecords, hoursInGroup, groupingTimeframe)
 	if err != nil {
 		return err
@@ -124,6 +199,15 @@ func (service *MarketDataService) StoreGroupedRecords(currency string, groupingT
 	return service.publishEvent(config.EVENT_NEW_GROUP_DATA_ADDED)
 }
 
+// deriveGroups refreshes the groups of a backend that aggregates the 1h candles by itself and announces them like grouping does
+func (service *MarketDataService) deriveGroups(currency string, groupingTimeframe string, from *time.Time, to *time.Time) (bool, error) {
+	derived, err := service.repository.deriveGroups(currency, groupingTimeframe, from, to)
+	if !derived || err != nil {
+		return derived, err
+	}
+	return true, service.publishEvent(config.EVENT_NEW_GROUP_DATA_ADDED)
+}
+
 func (service *MarketDataService) publishEvent(eventName string) error {
 	ctx := context.Background()
 	err := service.redis.PublishEvent(ctx, eventName, config.APPLICATION_NAME)
@@ -0,0 +1,213 @@
+package marketdata
+
+import (
+	"cmp"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+)
+
+// MemoryMarketDataRepository keeps candles in a memory.Store with the semantics of the postgres MarketDataRepository
+type MemoryMarketDataRepository struct {
+	store *memory.Store
+}
+
+func NewMemoryMarketDataRepository(store *memory.Store) *MemoryMarketDataRepository {
+	return &MemoryMarketDataRepository{store: store}
+}
+
+func (repository *MemoryMarketDataRepository) getRecords(currency string, timeframe string) ([]dto.DataDto, error) {
+	return repository.filter(currency, timeframe, func(record dto.DataDto) bool { return true }), nil
+}
+
+func (repository *MemoryMarketDataRepository) GetRecordsByRequest(request dto.OHLCRequestDto) ([]dto.DataDto, error) {
+	records := repository.filter(request.Currency, request.Timeframe, func(record dto.DataDto) bool {
+		if request.StartTime != nil && record.Timestamp.Before(*request.StartTime) {
+			return false
+		}
+		return request.EndTime == nil || !record.Timestamp.After(*request.EndTime)
+	})
+
+	slices.SortStableFunc(records, func(a, b dto.DataDto) int {
+		order := compareBySortField(a, b, request.SortField)
+		if request.SortOrder == "DESC" {
+			return -order
+		}
+		return order
+	})
+	if request.Limit >= 0 && len(records) > int(request.Limit) {
+		records = records[:request.Limit]
+	}
+	return records, nil
+}
+
+func (repository *MemoryMarketDataRepository) getCompleteRecordsAfter(currency string, timeframe string, lastTime time.Time) ([]dto.DataDto, error) {
+	return repository.filter(currency, timeframe, func(record dto.DataDto) bool {
+		return record.IsComplete && record.Timestamp.After(lastTime)
+	}), nil
+}
+
+func (repository *MemoryMarketDataRepository) getCompleteRecordsBetween(currency string, timeframe string, after time.Time, until time.Time) ([]dto.DataDto, error) {
+	return repository.filter(currency, timeframe, func(record dto.DataDto) bool {
+		return record.IsComplete && record.Timestamp.After(after) && !record.Timestamp.After(until)
+	}), nil
+}
+
+// getCompleteRecordsBefore returns up to limit complete records preceding "before" in chronological order
+func (repository *MemoryMarketDataRepository) getCompleteRecordsBefore(currency string, timeframe string, before time.Time, limit int) ([]dto.DataDto, error) {
+	records := repository.filter(currency, timeframe, func(record dto.DataDto) bool {
+		return record.IsComplete && record.Timestamp.Before(before)
+	})
+	if len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records, nil
+}
+
+func (repository *MemoryMarketDataRepository) getLastCompleteRecord(currency string, timeframe string) (*dto.DataDto, error) {
+	records := repository.filter(currency, timeframe, func(record dto.DataDto) bool { return record.IsComplete })
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return &records[len(records)-1], nil
+}
+
+func (repository *MemoryMarketDataRepository) checkIfRecordExists(currency string, timeframe string, dateTime time.Time) (bool, error) {
+	records := repository.filter(currency, timeframe, func(record dto.DataDto) bool { return record.Timestamp.Equal(dateTime) })
+	return len(records) > 0, nil
+}
+
+func (repository *MemoryMarketDataRepository) upsert(currency string, data *dto.DataDto) error {
+	repository.store.UpsertCandles(currency, data.Timeframe, []*dto.DataDto{data})
+	return nil
+}
+
+func (repository *MemoryMarketDataRepository) upsertBatchByTimeFrame(currency string, timeFrame string, records []*dto.DataDto) error {
+	repository.store.UpsertCandles(currency, timeFrame, records)
+	return nil
+}
+
+// getRecordsBefore returns the records preceding "before" in chronological order
+func (repository *MemoryMarketDataRepository) getRecordsBefore(currency string, timeframe string, before time.Time) ([]dto.DataDto, error) {
+	return repository.filter(currency, timeframe, func(record dto.DataDto) bool { return record.Timestamp.Before(before) }), nil
+}
+
+func (repository *MemoryMarketDataRepository) getFirstTimestamp(currency string, timeframe string) (*time.Time, error) {
+	records := repository.store.Candles(currency, timeframe)
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return &records[0].Timestamp, nil
+}
+
+// getMissingTimestamps returns every timestamp between the first stored record and "until" that has no record
+func (repository *MemoryMarketDataRepository) getMissingTimestamps(currency string, timeframe string, until time.Time) ([]time.Time, error) {
+	records := repository.store.Candles(currency, timeframe)
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	step := time.Duration(config.HoursByTimeframe[timeframe]) * time.Hour
+	var timestamps []time.Time
+	next := 0
+	for timestamp := records[0].Timestamp; !timestamp.After(until); timestamp = timestamp.Add(step) {
+		for next < len(records) && records[next].Timestamp.Before(timestamp) {
+			next++
+		}
+		if next == len(records) || !records[next].Timestamp.Equal(timestamp) {
+			timestamps = append(timestamps, timestamp)
+		}
+	}
+	return timestamps, nil
+}
+
+func (repository *MemoryMarketDataRepository) deleteRecordsBefore(currency string, timeframe string, before time.Time) (int64, error) {
+	rowsAffected := repository.store.DeleteCandles(currency, timeframe, func(record dto.DataDto) bool {
+		return record.Timestamp.Before(before)
+	})
+
+	log.Printf("💾 ✅ deleted records %d", rowsAffected)
+	return int64(rowsAffected), nil
+}
+
+func (repository *MemoryMarketDataRepository) deriveGroups(currency string, groupingTimeframe string, from *time.Time, to *time.Time) (bool, error) {
+	return false, nil
+}
+
+// quarantine keeps the latest rejected version of a candle
+func (repository *MemoryMarketDataRepository) quarantine(currency string, record *dto.DataDto, reason string) error {
+	repository.store.Quarantine(dto.QuarantineDto{
+		Currency:  currency,
+		Timeframe: record.Timeframe,
+		Timestamp: record.Timestamp,
+		Reason:    reason,
+		Record:    *record,
+		CreatedAt: clock.Now(),
+	})
+	return nil
+}
+
+func (repository *MemoryMarketDataRepository) getQuarantined(request dto.QuarantineRequestDto) ([]dto.QuarantineDto, error) {
+	var records []dto.QuarantineDto
+	quarantined := repository.store.Quarantined()
+	for i := len(quarantined) - 1; i >= 0 && len(records) < request.Limit; i-- {
+		record := quarantined[i]
+		if record.Currency == request.Currency && (request.Timeframe == "" || record.Timeframe == request.Timeframe) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (repository *MemoryMarketDataRepository) getQuarantinedById(id int64) (*dto.QuarantineDto, error) {
+	record, ok := repository.store.QuarantinedById(id)
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (repository *MemoryMarketDataRepository) getLastQuarantinedBefore(currency string, timeframe string, before time.Time) (*dto.QuarantineDto, error) {
+	quarantined := repository.store.Quarantined()
+	for i := len(quarantined) - 1; i >= 0; i-- {
+		record := quarantined[i]
+		if record.Currency == currency && record.Timeframe == timeframe && record.Timestamp.Before(before) {
+			return &record, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repository *MemoryMarketDataRepository) deleteQuarantined(id int64) error {
+	repository.store.DeleteQuarantined(id)
+	return nil
+}
+
+// filter returns the matching records of data_<currency>_<timeframe> ordered by timestamp
+func (repository *MemoryMarketDataRepository) filter(currency string, timeframe string, condition func(record dto.DataDto) bool) []dto.DataDto {
+	var records []dto.DataDto
+	for _, record := range repository.store.Candles(currency, timeframe) {
+		if condition(record) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// compareBySortField orders by one of the sort fields accepted by GetOHLC, timestamp by default
+func compareBySortField(a dto.DataDto, b dto.DataDto, sortField string) int {
+	switch sortField {
+	case "volume":
+		return a.Volume.Cmp(b.Volume)
+	case "quote_volume":
+		return a.QuoteVolume.Cmp(b.QuoteVolume)
+	case "trade_count":
+		return cmp.Compare(a.TradeCount, b.TradeCount)
+	}
+	return a.Timestamp.Compare(b.Timestamp)
+}
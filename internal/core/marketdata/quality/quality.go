@@ -0,0 +1,146 @@
+package quality
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/shopspring/decimal"
+)
+
+// MinReturns is the number of previous returns needed before jumps are checked
+const MinReturns = 20
+
+// Checker screens candles of one currency and timeframe before they are stored. Every accepted candle
+// becomes the previous close of the next one, so a batch has to be checked in chronological order.
+// A close counts as a jump only when it jumps from both the last accepted close and the close of the previous
+// candle, accepted or not. A lasting level shift quarantines a single candle, a lone spike stays rejected.
+type Checker struct {
+	closes   []float64
+	previous float64
+	maxSigma float64
+	window   int
+}
+
+// NewChecker takes the stored candles preceding the ones to check in chronological order.
+// maxSigma is the number of standard deviations of the close to close log returns a close may jump, 0 disables the rule.
+func NewChecker(history []dto.DataDto, maxSigma float64, window int) *Checker {
+	checker := &Checker{maxSigma: maxSigma, window: window}
+	for _, record := range history {
+		checker.push(record.Close.InexactFloat64())
+	}
+	return checker
+}
+
+// Follow makes a quarantined candle that comes after the history the previous candle of the next check
+func (checker *Checker) Follow(record *dto.DataDto) {
+	if CheckCandle(record, clock.Now()) == "" {
+		checker.previous = record.Close.InexactFloat64()
+	}
+}
+
+// Check returns the reason a candle is rejected, an empty string when it passes every rule
+func (checker *Checker) Check(record *dto.DataDto) string {
+	reason := CheckCandle(record, clock.Now())
+	if reason != "" {
+		return reason
+	}
+	reason = checker.checkJump(record)
+	if reason == "" {
+		checker.push(record.Close.InexactFloat64())
+	}
+	checker.previous = record.Close.InexactFloat64()
+	return reason
+}
+
+// CheckCandle applies the rules that need no history: positive prices, non-negative volumes,
+// OHLC consistency and a timestamp aligned to the timeframe that is not in the future
+func CheckCandle(record *dto.DataDto, now time.Time) string {
+	for _, price := range []decimal.Decimal{record.Open, record.High, record.Low, record.Close} {
+		if !price.IsPositive() {
+			return "non-positive price"
+		}
+	}
+	for _, volume := range []decimal.Decimal{record.Volume, record.QuoteVolume, record.TakerBuyBaseVolume, record.TakerBuyQuoteVolume} {
+		if volume.IsNegative() {
+			return "negative volume"
+		}
+	}
+	if record.TradeCount < 0 {
+		return "negative volume"
+	}
+	if record.High.LessThan(record.Low) {
+		return "high below low"
+	}
+	if record.High.LessThan(decimal.Max(record.Open, record.Close)) {
+		return "high below open or close"
+	}
+	if record.Low.GreaterThan(decimal.Min(record.Open, record.Close)) {
+		return "low above open or close"
+	}
+
+	hours, ok := config.HoursByTimeframe[record.Timeframe]
+	if !ok {
+		return fmt.Sprintf("unknown timeframe %s", record.Timeframe)
+	}
+	if !record.Timestamp.Equal(record.Timestamp.Truncate(time.Duration(hours) * time.Hour)) {
+		return fmt.Sprintf("timestamp not aligned to %s", record.Timeframe)
+	}
+	if record.Timestamp.After(now) {
+		return "timestamp in the future"
+	}
+	return ""
+}
+
+func (checker *Checker) checkJump(record *dto.DataDto) string {
+	if checker.maxSigma <= 0 || len(checker.closes) <= MinReturns {
+		return ""
+	}
+
+	returns := make([]float64, 0, len(checker.closes)-1)
+	for i := 1; i < len(checker.closes); i++ {
+		returns = append(returns, math.Log(checker.closes[i]/checker.closes[i-1]))
+	}
+	sigma := standardDeviation(returns)
+	if sigma == 0 {
+		return ""
+	}
+
+	close := record.Close.InexactFloat64()
+	jump := math.Abs(math.Log(close/checker.closes[len(checker.closes)-1])) / sigma
+	if jump <= checker.maxSigma {
+		return ""
+	}
+	if checker.previous > 0 && math.Abs(math.Log(close/checker.previous))/sigma <= checker.maxSigma {
+		return ""
+	}
+	return fmt.Sprintf("close jumps %.1f sigma from the previous close", jump)
+}
+
+func (checker *Checker) push(close float64) {
+	if close <= 0 {
+		return
+	}
+	checker.closes = append(checker.closes, close)
+	checker.previous = close
+	if len(checker.closes) > checker.window+1 {
+		checker.closes = checker.closes[1:]
+	}
+}
+
+func standardDeviation(values []float64) float64 {
+	var mean float64
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
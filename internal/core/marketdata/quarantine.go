@@ -0,0 +1,90 @@
+package marketdata
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata/quality"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+)
+
+// ScreenData runs the data quality checks on fetched candles of one timeframe and returns the ones that may be stored,
+// the rejected ones are moved to the quarantine
+func (service *MarketDataService) ScreenData(currency string, data []*dto.DataDto) ([]*dto.DataDto, error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+	timeframe := data[0].Timeframe
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, timeframe); err != nil {
+		return nil, err
+	}
+
+	sorted := slices.Clone(data)
+	slices.SortFunc(sorted, func(a, b *dto.DataDto) int { return a.Timestamp.Compare(b.Timestamp) })
+
+	cfg := config.LoadConfig()
+	history, err := service.repository.getCompleteRecordsBefore(currency, timeframe, sorted[0].Timestamp, cfg.QualityWindow+1)
+	if err != nil {
+		return nil, err
+	}
+	checker := quality.NewChecker(history, float64(cfg.QualityMaxSigma), cfg.QualityWindow)
+	previous, err := service.repository.getLastQuarantinedBefore(currency, timeframe, sorted[0].Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && (len(history) == 0 || previous.Timestamp.After(history[len(history)-1].Timestamp)) {
+		checker.Follow(&previous.Record)
+	}
+
+	accepted := make([]*dto.DataDto, 0, len(sorted))
+	for _, record := range sorted {
+		reason := checker.Check(record)
+		if reason == "" {
+			accepted = append(accepted, record)
+			continue
+		}
+		log.Printf(config.COLOR_YELLOW+"quarantined %s %s %s: %s"+config.COLOR_RESET, currency, record.Timeframe, record.Timestamp, reason)
+		if err := service.repository.quarantine(currency, record, reason); err != nil {
+			return nil, err
+		}
+	}
+	return accepted, nil
+}
+
+func (service *MarketDataService) GetQuarantined(request dto.QuarantineRequestDto) ([]dto.QuarantineDto, error) {
+	if err := service.validator.ValidateCurrency(request.Currency); err != nil {
+		return nil, err
+	}
+	if request.Timeframe != "" {
+		if err := service.validator.ValidateCurrencyAndTimeframe(request.Currency, request.Timeframe); err != nil {
+			return nil, err
+		}
+	}
+	if request.Limit <= 0 {
+		request.Limit = config.DEFAULT_LIMIT
+	}
+	return service.repository.getQuarantined(request)
+}
+
+// ReleaseQuarantined stores a quarantined candle as it is and removes it from the quarantine
+func (service *MarketDataService) ReleaseQuarantined(id int64) (*dto.DataDto, error) {
+	quarantined, err := service.repository.getQuarantinedById(id)
+	if err != nil {
+		return nil, err
+	}
+	if quarantined == nil {
+		return nil, fmt.Errorf("quarantined record %d not found", id)
+	}
+
+	record := quarantined.Record
+	if err := service.UpsertBatchData(quarantined.Currency, []*dto.DataDto{&record}); err != nil {
+		return nil, err
+	}
+	if err := service.repository.deleteQuarantined(id); err != nil {
+		return nil, err
+	}
+	log.Printf(config.COLOR_BLUE+"released %s %s %s from quarantine"+config.COLOR_RESET, quarantined.Currency, record.Timeframe, record.Timestamp)
+	return &record, nil
+}
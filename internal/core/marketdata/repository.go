@@ -2,25 +2,49 @@ package marketdata
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
 	"time"
 
+	"github.com/chyngyz-sydykov/marketpulse/config"
 	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
 )
 
 type MarketDataRepository struct {
+	timescale bool // candles are stored in the ts_data_ hypertables and continuous aggregates
 }
 
 func NewMarketDataRepository() *MarketDataRepository {
 	return &MarketDataRepository{}
 }
 
+func (repository *MarketDataRepository) deriveGroups(currency string, groupingTimeframe string, from *time.Time, to *time.Time) (bool, error) {
+	return false, nil
+}
+
+// source is the table or continuous aggregate the candles of a timeframe are read from
+func (repository *MarketDataRepository) source(currency string, timeframe string) string {
+	if repository.timescale {
+		return database.TimescaleCandleSource(currency, timeframe)
+	}
+	return fmt.Sprintf("data_%s_%s", currency, timeframe)
+}
+
+// table is the table the candles of a timeframe are written to
+func (repository *MarketDataRepository) table(currency string, timeframe string) string {
+	if repository.timescale {
+		return fmt.Sprintf("ts_data_%s_%s", currency, timeframe)
+	}
+	return fmt.Sprintf("data_%s_%s", currency, timeframe)
+}
+
 func (repository *MarketDataRepository) getRecords(currency string, timeframe string) ([]dto.DataDto, error) {
-	query := fmt.Sprintf(`SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume 
-						  FROM data_%s_%s ORDER BY timestamp ASC`, currency, timeframe)
+	query := fmt.Sprintf(`SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume, 
+						  quote_volume, trade_count, taker_buy_base_volume, taker_buy_quote_volume, vwap, median_price
+						  FROM %s ORDER BY timestamp ASC`, repository.source(currency, timeframe))
 
 	rows, err := database.DB.Query(query)
 	if err != nil {
@@ -33,7 +57,8 @@ func (repository *MarketDataRepository) getRecords(currency string, timeframe st
 	for rows.Next() {
 		var record dto.DataDto
 		err := rows.Scan(&record.Id, &record.Symbol, &record.Timeframe, &record.Timestamp,
-			&record.Open, &record.High, &record.Low, &record.Close, &record.Volume)
+			&record.Open, &record.High, &record.Low, &record.Close, &record.Volume,
+			&record.QuoteVolume, &record.TradeCount, &record.TakerBuyBaseVolume, &record.TakerBuyQuoteVolume, &record.Vwap, &record.MedianPrice)
 		if err != nil {
 			return nil, fmt.Errorf("💾 error scanning row: %v", err)
 		}
@@ -49,8 +74,9 @@ func (repository *MarketDataRepository) getRecords(currency string, timeframe st
 
 func (repository *MarketDataRepository) GetRecordsByRequest(request dto.OHLCRequestDto) ([]dto.DataDto, error) {
 	query := fmt.Sprintf(`
-	SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume, trend, is_complete 
-	FROM data_%s_%s`, request.Currency, request.Timeframe)
+	SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume, trend, is_complete,
+	quote_volume, trade_count, taker_buy_base_volume, taker_buy_quote_volume, vwap, median_price
+	FROM %s`, repository.source(request.Currency, request.Timeframe))
 
 	var args []any
 
@@ -81,7 +107,8 @@ func (repository *MarketDataRepository) GetRecordsByRequest(request dto.OHLCRequ
 	for rows.Next() {
 		var record dto.DataDto
 		err := rows.Scan(&record.Id, &record.Symbol, &record.Timeframe, &record.Timestamp,
-			&record.Open, &record.High, &record.Low, &record.Close, &record.Volume, &record.Trend, &record.IsComplete)
+			&record.Open, &record.High, &record.Low, &record.Close, &record.Volume, &record.Trend, &record.IsComplete,
+			&record.QuoteVolume, &record.TradeCount, &record.TakerBuyBaseVolume, &record.TakerBuyQuoteVolume, &record.Vwap, &record.MedianPrice)
 		if err != nil {
 			return nil, fmt.Errorf("💾 error scanning row: %v", err)
 		}
@@ -96,10 +123,11 @@ func (repository *MarketDataRepository) GetRecordsByRequest(request dto.OHLCRequ
 }
 
 func (repository *MarketDataRepository) getCompleteRecordsAfter(currency string, timeframe string, lastTime time.Time) ([]dto.DataDto, error) {
-	query := fmt.Sprintf(`SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume 
-						  FROM data_%s_%s 
+	query := fmt.Sprintf(`SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume, 
+						  quote_volume, trade_count, taker_buy_base_volume, taker_buy_quote_volume, vwap, median_price
+						  FROM %s 
 						  WHERE timestamp > $1 and is_complete = true
-						  ORDER BY timestamp ASC`, currency, timeframe)
+						  ORDER BY timestamp ASC`, repository.source(currency, timeframe))
 
 	rows, err := database.DB.Query(query, lastTime)
 	if err != nil {
@@ -112,7 +140,41 @@ func (repository *MarketDataRepository) getCompleteRecordsAfter(currency string,
 	for rows.Next() {
 		var record dto.DataDto
 		err := rows.Scan(&record.Id, &record.Symbol, &record.Timeframe, &record.Timestamp,
-			&record.Open, &record.High, &record.Low, &record.Close, &record.Volume)
+			&record.Open, &record.High, &record.Low, &record.Close, &record.Volume,
+			&record.QuoteVolume, &record.TradeCount, &record.TakerBuyBaseVolume, &record.TakerBuyQuoteVolume, &record.Vwap, &record.MedianPrice)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+
+	return records, nil
+}
+
+func (repository *MarketDataRepository) getCompleteRecordsBetween(currency string, timeframe string, after time.Time, until time.Time) ([]dto.DataDto, error) {
+	query := fmt.Sprintf(`SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume, 
+						  quote_volume, trade_count, taker_buy_base_volume, taker_buy_quote_volume, vwap, median_price
+						  FROM %s 
+						  WHERE timestamp > $1 and timestamp <= $2 and is_complete = true
+						  ORDER BY timestamp ASC`, repository.source(currency, timeframe))
+
+	rows, err := database.DB.Query(query, after, until)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching data: %v", err)
+	}
+	defer rows.Close()
+
+	var records []dto.DataDto
+
+	for rows.Next() {
+		var record dto.DataDto
+		err := rows.Scan(&record.Id, &record.Symbol, &record.Timeframe, &record.Timestamp,
+			&record.Open, &record.High, &record.Low, &record.Close, &record.Volume,
+			&record.QuoteVolume, &record.TradeCount, &record.TakerBuyBaseVolume, &record.TakerBuyQuoteVolume, &record.Vwap, &record.MedianPrice)
 		if err != nil {
 			return nil, fmt.Errorf("💾 error scanning row: %v", err)
 		}
@@ -127,16 +189,18 @@ func (repository *MarketDataRepository) getCompleteRecordsAfter(currency string,
 }
 
 func (repository *MarketDataRepository) getLastCompleteRecord(currency, timeframe string) (*dto.DataDto, error) {
-	query := fmt.Sprintf(`SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume 
-						  FROM data_%s_%s 
+	query := fmt.Sprintf(`SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume, 
+						  quote_volume, trade_count, taker_buy_base_volume, taker_buy_quote_volume, vwap, median_price
+						  FROM %s 
 						  WHERE is_complete = true
-						  ORDER BY timestamp DESC LIMIT 1`, currency, timeframe)
+						  ORDER BY timestamp DESC LIMIT 1`, repository.source(currency, timeframe))
 
 	row := database.DB.QueryRow(query)
 
 	var record dto.DataDto
 	err := row.Scan(&record.Id, &record.Symbol, &record.Timeframe, &record.Timestamp,
-		&record.Open, &record.High, &record.Low, &record.Close, &record.Volume)
+		&record.Open, &record.High, &record.Low, &record.Close, &record.Volume,
+		&record.QuoteVolume, &record.TradeCount, &record.TakerBuyBaseVolume, &record.TakerBuyQuoteVolume, &record.Vwap, &record.MedianPrice)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -148,7 +212,7 @@ func (repository *MarketDataRepository) getLastCompleteRecord(currency, timefram
 	return &record, nil
 }
 func (repository *MarketDataRepository) checkIfRecordExists(currency, timeframe string, dateTime time.Time) (bool, error) {
-	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM data_%s_%s WHERE timestamp=$1)`, currency, timeframe)
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE timestamp=$1)`, repository.source(currency, timeframe))
 
 	var exists bool
 	err := database.DB.QueryRow(query, dateTime).Scan(&exists)
@@ -168,8 +232,9 @@ func (repository *MarketDataRepository) upsert(currency string, data *dto.DataDt
 		return fmt.Errorf("💾 error starting transaction: %v", err)
 	}
 	query := fmt.Sprintf(
-		`INSERT INTO data_%s_%s (symbol, timestamp, timeframe, open, high, low, close, volume, trend, is_complete) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`INSERT INTO %s (symbol, timestamp, timeframe, open, high, low, close, volume, trend, is_complete,
+		quote_volume, trade_count, taker_buy_base_volume, taker_buy_quote_volume, vwap, median_price) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT (timestamp) 
 		DO UPDATE 
 		SET symbol = EXCLUDED.symbol, 
@@ -180,9 +245,16 @@ func (repository *MarketDataRepository) upsert(currency string, data *dto.DataDt
 		close = EXCLUDED.close, 
 		volume = EXCLUDED.volume, 
 		trend = EXCLUDED.trend, 
-		is_complete = EXCLUDED.is_complete`, currency, data.Timeframe)
-
-	_, err = tx.Exec(query, data.Symbol, data.Timestamp, data.Timeframe, data.Open, data.High, data.Low, data.Close, data.Volume, data.Trend, data.IsComplete)
+		is_complete = EXCLUDED.is_complete,
+		quote_volume = EXCLUDED.quote_volume,
+		trade_count = EXCLUDED.trade_count,
+		taker_buy_base_volume = EXCLUDED.taker_buy_base_volume,
+		taker_buy_quote_volume = EXCLUDED.taker_buy_quote_volume,
+		vwap = EXCLUDED.vwap,
+		median_price = EXCLUDED.median_price`, repository.table(currency, data.Timeframe))
+
+	_, err = tx.Exec(query, data.Symbol, data.Timestamp, data.Timeframe, data.Open, data.High, data.Low, data.Close, data.Volume, data.Trend, data.IsComplete,
+		data.QuoteVolume, data.TradeCount, data.TakerBuyBaseVolume, data.TakerBuyQuoteVolume, data.Vwap, data.MedianPrice)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("💾 error upserting data: %v", err)
@@ -203,18 +275,41 @@ func (repository *MarketDataRepository) upsertBatchByTimeFrame(currency string,
 		return fmt.Errorf("💾 error starting transaction: %v", err)
 	}
 
+	var rowsAffected int64
+	for _, batch := range database.Batches(records, database.UpsertBatchSize) {
+		affected, err := repository.upsertBatch(tx, currency, timeFrame, batch)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		rowsAffected += affected
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("💾 error committing transaction: %v", err)
+	}
+
+	log.Printf("💾 ✅ upsert batch data %d, %d", len(records), rowsAffected)
+	return nil
+}
+
+// upsertBatch upserts up to database.UpsertBatchSize records with a single multi-row INSERT
+func (repository *MarketDataRepository) upsertBatch(tx *sql.Tx, currency string, timeFrame string, records []*dto.DataDto) (int64, error) {
 	var values []interface{}
 	var placeholders []string
 
 	for i, record := range records {
-		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-			i*10+1, i*10+2, i*10+3, i*10+4, i*10+5, i*10+6, i*10+7, i*10+8, i*10+9, i*10+10))
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			i*16+1, i*16+2, i*16+3, i*16+4, i*16+5, i*16+6, i*16+7, i*16+8, i*16+9, i*16+10, i*16+11, i*16+12, i*16+13, i*16+14, i*16+15, i*16+16))
 		values = append(values, record.Symbol, record.Timeframe, record.Timestamp,
-			record.Open, record.High, record.Low, record.Close, record.Volume, record.Trend, record.IsComplete)
+			record.Open, record.High, record.Low, record.Close, record.Volume, record.Trend, record.IsComplete,
+			record.QuoteVolume, record.TradeCount, record.TakerBuyBaseVolume, record.TakerBuyQuoteVolume, record.Vwap, record.MedianPrice)
 	}
 
 	query := fmt.Sprintf(`
-		INSERT INTO data_%s_%s (symbol, timeframe, timestamp, open, high, low, close, volume, trend, is_complete) 
+		INSERT INTO %s (symbol, timeframe, timestamp, open, high, low, close, volume, trend, is_complete,
+			quote_volume, trade_count, taker_buy_base_volume, taker_buy_quote_volume, vwap, median_price) 
 		VALUES %s
 		ON CONFLICT (timestamp) DO UPDATE 
 		SET open = EXCLUDED.open,
@@ -223,22 +318,236 @@ func (repository *MarketDataRepository) upsertBatchByTimeFrame(currency string,
 			close = EXCLUDED.close,
 			volume = EXCLUDED.volume,
 			trend = EXCLUDED.trend,
-			is_complete = EXCLUDED.is_complete;`,
-		currency, timeFrame, strings.Join(placeholders, ","))
+			is_complete = EXCLUDED.is_complete,
+			quote_volume = EXCLUDED.quote_volume,
+			trade_count = EXCLUDED.trade_count,
+			taker_buy_base_volume = EXCLUDED.taker_buy_base_volume,
+			taker_buy_quote_volume = EXCLUDED.taker_buy_quote_volume,
+			vwap = EXCLUDED.vwap,
+			median_price = EXCLUDED.median_price;`,
+		repository.table(currency, timeFrame), strings.Join(placeholders, ","))
 
 	result, err := tx.Exec(query, values...)
+	if err != nil {
+		return 0, fmt.Errorf("💾 error batch upsert: %v", err)
+	}
+	return result.RowsAffected()
+}
 
+// getCompleteRecordsBefore returns up to limit complete records preceding "before" in chronological order
+func (repository *MarketDataRepository) getCompleteRecordsBefore(currency string, timeframe string, before time.Time, limit int) ([]dto.DataDto, error) {
+	query := fmt.Sprintf(`SELECT timestamp, close FROM (
+						  SELECT timestamp, close FROM %s
+						  WHERE is_complete = true AND timestamp < $1
+						  ORDER BY timestamp DESC LIMIT $2) AS previous
+						  ORDER BY timestamp ASC`, repository.source(currency, timeframe))
+
+	rows, err := database.DB.Query(query, before, limit)
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("💾 error batch upsert: %v", err)
+		return nil, fmt.Errorf("💾 error fetching previous records: %v", err)
 	}
+	defer rows.Close()
 
-	err = tx.Commit()
+	var records []dto.DataDto
+	for rows.Next() {
+		var record dto.DataDto
+		if err := rows.Scan(&record.Timestamp, &record.Close); err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return records, nil
+}
+
+// getRecordsBefore returns the records preceding "before" in chronological order
+func (repository *MarketDataRepository) getRecordsBefore(currency string, timeframe string, before time.Time) ([]dto.DataDto, error) {
+	query := fmt.Sprintf(`SELECT id, symbol, timeframe, timestamp, open, high, low, close, volume, trend, is_complete,
+						  quote_volume, trade_count, taker_buy_base_volume, taker_buy_quote_volume, vwap, median_price
+						  FROM %s WHERE timestamp < $1
+						  ORDER BY timestamp ASC`, repository.source(currency, timeframe))
+
+	rows, err := database.DB.Query(query, before)
 	if err != nil {
-		return fmt.Errorf("💾 error committing transaction: %v", err)
+		return nil, fmt.Errorf("💾 error fetching expired records: %v", err)
+	}
+	defer rows.Close()
+
+	var records []dto.DataDto
+	for rows.Next() {
+		var record dto.DataDto
+		err := rows.Scan(&record.Id, &record.Symbol, &record.Timeframe, &record.Timestamp,
+			&record.Open, &record.High, &record.Low, &record.Close, &record.Volume, &record.Trend, &record.IsComplete,
+			&record.QuoteVolume, &record.TradeCount, &record.TakerBuyBaseVolume, &record.TakerBuyQuoteVolume, &record.Vwap, &record.MedianPrice)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return records, nil
+}
+
+// getFirstTimestamp returns the timestamp of the oldest stored record, nil when there is none
+func (repository *MarketDataRepository) getFirstTimestamp(currency string, timeframe string) (*time.Time, error) {
+	query := fmt.Sprintf(`SELECT MIN(timestamp) FROM %s`, repository.source(currency, timeframe))
+
+	var first sql.NullTime
+	if err := database.DB.QueryRow(query).Scan(&first); err != nil {
+		return nil, fmt.Errorf("💾 error fetching first record: %v", err)
+	}
+	if !first.Valid {
+		return nil, nil
+	}
+	return &first.Time, nil
+}
+
+// getMissingTimestamps returns every timestamp between the first stored record and "until" that has no record
+func (repository *MarketDataRepository) getMissingTimestamps(currency string, timeframe string, until time.Time) ([]time.Time, error) {
+	query := fmt.Sprintf(`
+		SELECT series.timestamp
+		FROM generate_series(
+			(SELECT MIN(timestamp) FROM %s),
+			$1::timestamptz,
+			interval '%d hour'
+		) AS series(timestamp)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM %s d
+			WHERE d.timestamp = series.timestamp
+		)
+		ORDER BY series.timestamp ASC`, repository.source(currency, timeframe), config.HoursByTimeframe[timeframe], repository.source(currency, timeframe))
+
+	rows, err := database.DB.Query(query, until)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching missing timestamps: %v", err)
+	}
+	defer rows.Close()
+
+	var timestamps []time.Time
+	for rows.Next() {
+		var timestamp time.Time
+		if err := rows.Scan(&timestamp); err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		timestamps = append(timestamps, timestamp)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+
+	return timestamps, nil
+}
+
+func (repository *MarketDataRepository) deleteRecordsBefore(currency string, timeframe string, before time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE timestamp < $1`, repository.table(currency, timeframe))
+
+	result, err := database.DB.Exec(query, before)
+	if err != nil {
+		return 0, fmt.Errorf("💾 error deleting expired records: %v", err)
 	}
 	rowsAffected, _ := result.RowsAffected()
 
-	log.Printf("💾 ✅ upsert batch data %d, %d", len(records), rowsAffected)
+	log.Printf("💾 ✅ deleted records %d", rowsAffected)
+	return rowsAffected, nil
+}
+
+// quarantine keeps the latest rejected version of a candle
+func (repository *MarketDataRepository) quarantine(currency string, record *dto.DataDto, reason string) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = database.DB.Exec(`INSERT INTO data_quarantine (currency, timeframe, timestamp, reason, record)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (currency, timeframe, timestamp) DO UPDATE
+		SET reason = EXCLUDED.reason,
+		record = EXCLUDED.record,
+		created_at = NOW()`,
+		currency, record.Timeframe, record.Timestamp, reason, value)
+	if err != nil {
+		return fmt.Errorf("💾 error quarantining record: %v", err)
+	}
+	return nil
+}
+
+func (repository *MarketDataRepository) getQuarantined(request dto.QuarantineRequestDto) ([]dto.QuarantineDto, error) {
+	query := `SELECT id, currency, timeframe, timestamp, reason, record, created_at FROM data_quarantine WHERE currency = $1`
+	args := []any{request.Currency}
+	if request.Timeframe != "" {
+		query += " AND timeframe = $2"
+		args = append(args, request.Timeframe)
+	}
+	query += fmt.Sprintf(" ORDER BY timestamp DESC LIMIT %d", request.Limit)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching quarantined records: %v", err)
+	}
+	defer rows.Close()
+
+	var records []dto.QuarantineDto
+	for rows.Next() {
+		record, err := scanQuarantined(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return records, nil
+}
+
+func (repository *MarketDataRepository) getQuarantinedById(id int64) (*dto.QuarantineDto, error) {
+	row := database.DB.QueryRow(`SELECT id, currency, timeframe, timestamp, reason, record, created_at FROM data_quarantine WHERE id = $1`, id)
+	record, err := scanQuarantined(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return record, err
+}
+
+// getLastQuarantinedBefore returns the latest quarantined candle preceding "before", nil when there is none
+func (repository *MarketDataRepository) getLastQuarantinedBefore(currency string, timeframe string, before time.Time) (*dto.QuarantineDto, error) {
+	row := database.DB.QueryRow(`SELECT id, currency, timeframe, timestamp, reason, record, created_at FROM data_quarantine
+		WHERE currency = $1 AND timeframe = $2 AND timestamp < $3
+		ORDER BY timestamp DESC LIMIT 1`, currency, timeframe, before)
+	record, err := scanQuarantined(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return record, err
+}
+
+func (repository *MarketDataRepository) deleteQuarantined(id int64) error {
+	_, err := database.DB.Exec(`DELETE FROM data_quarantine WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("💾 error deleting quarantined record: %v", err)
+	}
 	return nil
 }
+
+func scanQuarantined(row interface{ Scan(dest ...any) error }) (*dto.QuarantineDto, error) {
+	var record dto.QuarantineDto
+	var value []byte
+	err := row.Scan(&record.Id, &record.Currency, &record.Timeframe, &record.Timestamp, &record.Reason, &value, &record.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("💾 error scanning row: %v", err)
+	}
+	if err := json.Unmarshal(value, &record.Record); err != nil {
+		return nil, fmt.Errorf("💾 error decoding quarantined record: %v", err)
+	}
+	return &record, nil
+}
@@ -0,0 +1,46 @@
+package marketdata
+
+import (
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// MarketDataStorage is implemented by the postgres MarketDataRepository, the in-memory MemoryMarketDataRepository
+// and the TimescaleMarketDataRepository
+type MarketDataStorage interface {
+	GetRecordsByRequest(request dto.OHLCRequestDto) ([]dto.DataDto, error)
+	getRecords(currency string, timeframe string) ([]dto.DataDto, error)
+	getCompleteRecordsAfter(currency string, timeframe string, lastTime time.Time) ([]dto.DataDto, error)
+	getCompleteRecordsBetween(currency string, timeframe string, after time.Time, until time.Time) ([]dto.DataDto, error)
+	getCompleteRecordsBefore(currency string, timeframe string, before time.Time, limit int) ([]dto.DataDto, error)
+	getLastCompleteRecord(currency string, timeframe string) (*dto.DataDto, error)
+	checkIfRecordExists(currency string, timeframe string, dateTime time.Time) (bool, error)
+	upsert(currency string, data *dto.DataDto) error
+	upsertBatchByTimeFrame(currency string, timeFrame string, records []*dto.DataDto) error
+	getRecordsBefore(currency string, timeframe string, before time.Time) ([]dto.DataDto, error)
+	getFirstTimestamp(currency string, timeframe string) (*time.Time, error)
+	getMissingTimestamps(currency string, timeframe string, until time.Time) ([]time.Time, error)
+	deleteRecordsBefore(currency string, timeframe string, before time.Time) (int64, error)
+	// deriveGroups returns true when the backend aggregates the 1h candles by itself, grouping in go is skipped then
+	deriveGroups(currency string, groupingTimeframe string, from *time.Time, to *time.Time) (bool, error)
+
+	quarantine(currency string, record *dto.DataDto, reason string) error
+	getQuarantined(request dto.QuarantineRequestDto) ([]dto.QuarantineDto, error)
+	getQuarantinedById(id int64) (*dto.QuarantineDto, error)
+	getLastQuarantinedBefore(currency string, timeframe string, before time.Time) (*dto.QuarantineDto, error)
+	deleteQuarantined(id int64) error
+}
+
+// NewMarketDataStorage returns the repository of the STORAGE_BACKEND
+func NewMarketDataStorage() MarketDataStorage {
+	switch config.LoadConfig().StorageBackend {
+	case config.STORAGE_BACKEND_MEMORY:
+		return NewMemoryMarketDataRepository(memory.DB)
+	case config.STORAGE_BACKEND_TIMESCALE:
+		return NewTimescaleMarketDataRepository()
+	}
+	return NewMarketDataRepository()
+}
@@ -0,0 +1,66 @@
+package marketdata
+
+import (
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+)
+
+// TimescaleMarketDataRepository stores the 1h candles in hypertables, the grouped timeframes are read from continuous aggregates
+type TimescaleMarketDataRepository struct {
+	MarketDataRepository
+}
+
+func NewTimescaleMarketDataRepository() *TimescaleMarketDataRepository {
+	return &TimescaleMarketDataRepository{
+		MarketDataRepository: MarketDataRepository{timescale: true},
+	}
+}
+
+// upsert skips grouped candles, the continuous aggregates derive them
+func (repository *TimescaleMarketDataRepository) upsert(currency string, data *dto.DataDto) error {
+	if database.IsGroupedTimeframe(data.Timeframe) {
+		return nil
+	}
+	return repository.MarketDataRepository.upsert(currency, data)
+}
+
+func (repository *TimescaleMarketDataRepository) upsertBatchByTimeFrame(currency string, timeFrame string, records []*dto.DataDto) error {
+	if database.IsGroupedTimeframe(timeFrame) {
+		return nil
+	}
+	return repository.MarketDataRepository.upsertBatchByTimeFrame(currency, timeFrame, records)
+}
+
+// getRecordsBefore returns nothing for grouped timeframes, the buckets of the continuous aggregates are kept
+// as the downsampled history when the 1h candles expire
+func (repository *TimescaleMarketDataRepository) getRecordsBefore(currency string, timeframe string, before time.Time) ([]dto.DataDto, error) {
+	if database.IsGroupedTimeframe(timeframe) {
+		return nil, nil
+	}
+	return repository.MarketDataRepository.getRecordsBefore(currency, timeframe, before)
+}
+
+func (repository *TimescaleMarketDataRepository) deleteRecordsBefore(currency string, timeframe string, before time.Time) (int64, error) {
+	if database.IsGroupedTimeframe(timeframe) {
+		return 0, nil
+	}
+	return repository.MarketDataRepository.deleteRecordsBefore(currency, timeframe, before)
+}
+
+// deriveGroups materializes the buckets of the continuous aggregate around from and to,
+// the window is widened by one group so it always covers a whole bucket
+func (repository *TimescaleMarketDataRepository) deriveGroups(currency string, groupingTimeframe string, from *time.Time, to *time.Time) (bool, error) {
+	groupDuration := time.Duration(config.HoursByTimeframe[groupingTimeframe]) * time.Hour
+	if from != nil {
+		start := from.Add(-groupDuration)
+		from = &start
+	}
+	if to != nil {
+		end := to.Add(groupDuration)
+		to = &end
+	}
+	return true, database.RefreshTimescaleGroups(currency, groupingTimeframe, from, to)
+}
@@ -0,0 +1,49 @@
+package orderbook
+
+import (
+	"slices"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// MemoryOrderBookRepository keeps the depth snapshots in a memory.Store with the semantics of the postgres OrderBookRepository
+type MemoryOrderBookRepository struct {
+	store *memory.Store
+}
+
+func NewMemoryOrderBookRepository(store *memory.Store) *MemoryOrderBookRepository {
+	return &MemoryOrderBookRepository{store: store}
+}
+
+func (repository *MemoryOrderBookRepository) getRecordsByRequest(request dto.DepthRequestDto) ([]dto.DepthSnapshotDto, error) {
+	records := slices.DeleteFunc(repository.store.DepthSnapshots(request.Currency), func(record dto.DepthSnapshotDto) bool {
+		if request.StartTime != nil && record.Timestamp.Before(*request.StartTime) {
+			return true
+		}
+		return request.EndTime != nil && record.Timestamp.After(*request.EndTime)
+	})
+
+	if request.SortOrder == "DESC" {
+		slices.Reverse(records)
+	}
+	if request.Limit >= 0 && len(records) > int(request.Limit) {
+		records = records[:request.Limit]
+	}
+	return records, nil
+}
+
+func (repository *MemoryOrderBookRepository) getSnapshot(currency string, timestamp time.Time) (*dto.DepthSnapshotDto, error) {
+	for _, record := range repository.store.DepthSnapshots(currency) {
+		if record.Timestamp.Equal(timestamp) {
+			return &record, nil
+		}
+	}
+	return nil, nil
+}
+
+func (repository *MemoryOrderBookRepository) upsert(currency string, snapshot *dto.DepthSnapshotDto) error {
+	repository.store.UpsertDepthSnapshot(currency, *snapshot)
+	return nil
+}
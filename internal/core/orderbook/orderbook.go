@@ -0,0 +1,126 @@
+package orderbook
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/shopspring/decimal"
+)
+
+type OrderBookService struct {
+	repository OrderBookStorage
+	redis      redis.RedisServiceInterface
+	validator  validator.Validator
+}
+
+func NewOrderBookService(redis redis.RedisServiceInterface) *OrderBookService {
+	validator := validator.NewValidator()
+	return &OrderBookService{
+		repository: NewOrderBookStorage(),
+		redis:      redis,
+		validator:  *validator,
+	}
+}
+
+func (service *OrderBookService) GetRecordsByRequest(request dto.DepthRequestDto) ([]dto.DepthSnapshotDto, error) {
+	if err := service.validator.ValidateCurrency(request.Currency); err != nil {
+		return nil, err
+	}
+	return service.repository.getRecordsByRequest(request)
+}
+
+// CollectSnapshot fetches the binance order book of the currency and stores its liquidity summary.
+// snapshots are kept per minute, when the cron and the NewDataAdded event both collect within a minute
+// the stored snapshot is returned without fetching the order book again
+func (service *OrderBookService) CollectSnapshot(currency string) (*dto.DepthSnapshotDto, error) {
+	if err := service.validator.ValidateCurrency(currency); err != nil {
+		return nil, err
+	}
+
+	minute := clock.Now().Truncate(time.Minute)
+	stored, err := service.repository.getSnapshot(currency, minute)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		return stored, nil
+	}
+
+	book, err := binance.FetchDepth(currency, config.LoadConfig().DepthLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := Summarize(book)
+	snapshot.Timestamp = minute
+	if err := service.repository.upsert(currency, snapshot); err != nil {
+		return nil, err
+	}
+
+	log.Printf(config.COLOR_BLUE+"depth snapshot currency:%s spread:%s"+config.COLOR_RESET, currency, snapshot.Spread)
+	return snapshot, service.publishEvent(config.EVENT_NEW_DEPTH_ADDED)
+}
+
+// Summarize computes best bid/ask, spread and the cumulative quote volume within 0.5%, 1% and 2% of the mid price
+func Summarize(book *dto.OrderBookDto) *dto.DepthSnapshotDto {
+	snapshot := &dto.DepthSnapshotDto{
+		Symbol:    book.Symbol,
+		Timestamp: book.Timestamp,
+	}
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return snapshot
+	}
+
+	snapshot.BestBid = book.Bids[0].Price
+	snapshot.BestAsk = book.Asks[0].Price
+	snapshot.MidPrice = snapshot.BestBid.Add(snapshot.BestAsk).Div(decimal.NewFromInt(2))
+	snapshot.Spread = snapshot.BestAsk.Sub(snapshot.BestBid)
+
+	snapshot.BidDepth05 = bidDepth(book.Bids, snapshot.MidPrice, decimal.NewFromFloat(0.005))
+	snapshot.AskDepth05 = askDepth(book.Asks, snapshot.MidPrice, decimal.NewFromFloat(0.005))
+	snapshot.BidDepth1 = bidDepth(book.Bids, snapshot.MidPrice, decimal.NewFromFloat(0.01))
+	snapshot.AskDepth1 = askDepth(book.Asks, snapshot.MidPrice, decimal.NewFromFloat(0.01))
+	snapshot.BidDepth2 = bidDepth(book.Bids, snapshot.MidPrice, decimal.NewFromFloat(0.02))
+	snapshot.AskDepth2 = askDepth(book.Asks, snapshot.MidPrice, decimal.NewFromFloat(0.02))
+	return snapshot
+}
+
+func bidDepth(bids []dto.PriceLevelDto, midPrice decimal.Decimal, percent decimal.Decimal) decimal.Decimal {
+	floor := midPrice.Mul(decimal.NewFromInt(1).Sub(percent))
+	total := decimal.Zero
+	for _, level := range bids {
+		if level.Price.LessThan(floor) {
+			break
+		}
+		total = total.Add(level.Price.Mul(level.Quantity))
+	}
+	return total
+}
+
+func askDepth(asks []dto.PriceLevelDto, midPrice decimal.Decimal, percent decimal.Decimal) decimal.Decimal {
+	ceiling := midPrice.Mul(decimal.NewFromInt(1).Add(percent))
+	total := decimal.Zero
+	for _, level := range asks {
+		if level.Price.GreaterThan(ceiling) {
+			break
+		}
+		total = total.Add(level.Price.Mul(level.Quantity))
+	}
+	return total
+}
+
+func (service *OrderBookService) publishEvent(eventName string) error {
+	ctx := context.Background()
+	err := service.redis.PublishEvent(ctx, eventName, config.APPLICATION_NAME)
+	if err != nil {
+		return err
+	}
+	return nil
+}
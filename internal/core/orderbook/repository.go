@@ -0,0 +1,109 @@
+package orderbook
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+)
+
+type OrderBookRepository struct {
+}
+
+func NewOrderBookRepository() *OrderBookRepository {
+	return &OrderBookRepository{}
+}
+
+func (repository *OrderBookRepository) getRecordsByRequest(request dto.DepthRequestDto) ([]dto.DepthSnapshotDto, error) {
+	query := fmt.Sprintf(`
+	SELECT id, symbol, timestamp, best_bid, best_ask, mid_price, spread,
+	bid_depth_05, ask_depth_05, bid_depth_1, ask_depth_1, bid_depth_2, ask_depth_2
+	FROM depth_%s`, request.Currency)
+
+	var args []any
+
+	if request.StartTime != nil {
+		query += " WHERE timestamp >= $1"
+		args = append(args, request.StartTime)
+	}
+	if request.EndTime != nil {
+		if request.StartTime == nil {
+			query += " WHERE timestamp <= $1"
+		} else {
+			query += " AND timestamp <= $2"
+		}
+		args = append(args, request.EndTime)
+	}
+	query += fmt.Sprintf(` 
+	ORDER BY timestamp %s
+	LIMIT %d`, request.SortOrder, request.Limit)
+
+	rows, err := database.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching depth snapshots: %v", err)
+	}
+	defer rows.Close()
+
+	var records []dto.DepthSnapshotDto
+
+	for rows.Next() {
+		var record dto.DepthSnapshotDto
+		err := rows.Scan(&record.Id, &record.Symbol, &record.Timestamp, &record.BestBid, &record.BestAsk, &record.MidPrice, &record.Spread,
+			&record.BidDepth05, &record.AskDepth05, &record.BidDepth1, &record.AskDepth1, &record.BidDepth2, &record.AskDepth2)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+
+	return records, nil
+}
+
+func (repository *OrderBookRepository) getSnapshot(currency string, timestamp time.Time) (*dto.DepthSnapshotDto, error) {
+	query := fmt.Sprintf(`
+	SELECT id, symbol, timestamp, best_bid, best_ask, mid_price, spread,
+	bid_depth_05, ask_depth_05, bid_depth_1, ask_depth_1, bid_depth_2, ask_depth_2
+	FROM depth_%s WHERE timestamp = $1`, currency)
+
+	var record dto.DepthSnapshotDto
+	err := database.DB.QueryRow(query, timestamp).Scan(&record.Id, &record.Symbol, &record.Timestamp, &record.BestBid, &record.BestAsk, &record.MidPrice, &record.Spread,
+		&record.BidDepth05, &record.AskDepth05, &record.BidDepth1, &record.AskDepth1, &record.BidDepth2, &record.AskDepth2)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("💾 error fetching depth snapshot: %v", err)
+	}
+	return &record, nil
+}
+
+func (repository *OrderBookRepository) upsert(currency string, snapshot *dto.DepthSnapshotDto) error {
+	query := fmt.Sprintf(`
+		INSERT INTO depth_%s (symbol, timestamp, best_bid, best_ask, mid_price, spread,
+			bid_depth_05, ask_depth_05, bid_depth_1, ask_depth_1, bid_depth_2, ask_depth_2)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (timestamp) DO UPDATE
+		SET best_bid = EXCLUDED.best_bid,
+			best_ask = EXCLUDED.best_ask,
+			mid_price = EXCLUDED.mid_price,
+			spread = EXCLUDED.spread,
+			bid_depth_05 = EXCLUDED.bid_depth_05,
+			ask_depth_05 = EXCLUDED.ask_depth_05,
+			bid_depth_1 = EXCLUDED.bid_depth_1,
+			ask_depth_1 = EXCLUDED.ask_depth_1,
+			bid_depth_2 = EXCLUDED.bid_depth_2,
+			ask_depth_2 = EXCLUDED.ask_depth_2`, currency)
+
+	_, err := database.DB.Exec(query, snapshot.Symbol, snapshot.Timestamp, snapshot.BestBid, snapshot.BestAsk, snapshot.MidPrice, snapshot.Spread,
+		snapshot.BidDepth05, snapshot.AskDepth05, snapshot.BidDepth1, snapshot.AskDepth1, snapshot.BidDepth2, snapshot.AskDepth2)
+	if err != nil {
+		return fmt.Errorf("💾 error upserting depth snapshot: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package orderbook
+
+import (
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+)
+
+// OrderBookStorage is implemented by the postgres OrderBookRepository and the in-memory MemoryOrderBookRepository
+type OrderBookStorage interface {
+	getRecordsByRequest(request dto.DepthRequestDto) ([]dto.DepthSnapshotDto, error)
+	getSnapshot(currency string, timestamp time.Time) (*dto.DepthSnapshotDto, error)
+	upsert(currency string, snapshot *dto.DepthSnapshotDto) error
+}
+
+// NewOrderBookStorage returns the repository of the STORAGE_BACKEND, the timescale backend keeps its depth tables in postgres
+func NewOrderBookStorage() OrderBookStorage {
+	if config.LoadConfig().StorageBackend == config.STORAGE_BACKEND_MEMORY {
+		return NewMemoryOrderBookRepository(memory.DB)
+	}
+	return NewOrderBookRepository()
+}
@@ -0,0 +1,76 @@
+package retention
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+type RetentionService struct {
+	marketDataService *marketdata.MarketDataService
+	indicatorService  *indicator.IndicatorService
+}
+
+func NewRetentionService(marketDataService *marketdata.MarketDataService, indicatorService *indicator.IndicatorService) *RetentionService {
+	return &RetentionService{
+		marketDataService: marketDataService,
+		indicatorService:  indicatorService,
+	}
+}
+
+// Apply archives and deletes the candles and indicators of a currency older than the RETENTION_POLICIES of their timeframes.
+// Shorter timeframes go first so the groups derived from expiring 1h candles are subject to their own policy.
+func (service *RetentionService) Apply(currency string) ([]dto.RetentionReportDto, error) {
+	log.Printf(config.COLOR_BLUE+"applying retention policies currency:%s"+config.COLOR_RESET, currency)
+
+	var reports []dto.RetentionReportDto
+	for _, timeframe := range policyTimeframes() {
+		cutoff, ok, err := utils.RetentionCutoff(timeframe)
+		if err != nil {
+			return reports, err
+		}
+		if !ok {
+			continue
+		}
+
+		// indicators go first, they point at the candles through data_timestamp
+		archivedIndicators, err := service.indicatorService.ArchiveRecordsBefore(currency, timeframe, cutoff)
+		if err != nil {
+			return reports, err
+		}
+		archivedRecords, err := service.marketDataService.ArchiveRecordsBefore(currency, timeframe, cutoff)
+		if err != nil {
+			return reports, err
+		}
+
+		reports = append(reports, dto.RetentionReportDto{
+			Currency:           currency,
+			Timeframe:          timeframe,
+			Cutoff:             cutoff,
+			ArchivedRecords:    archivedRecords,
+			ArchivedIndicators: archivedIndicators,
+		})
+	}
+	return reports, nil
+}
+
+// policyTimeframes returns the timeframes of RETENTION_POLICIES ordered from the shortest
+func policyTimeframes() []string {
+	var timeframes []string
+	for timeframe := range config.LoadConfig().RetentionPolicies {
+		timeframes = append(timeframes, timeframe)
+	}
+	slices.SortFunc(timeframes, func(a, b string) int {
+		if order := config.HoursByTimeframe[a] - config.HoursByTimeframe[b]; order != 0 {
+			return order
+		}
+		return strings.Compare(a, b)
+	})
+	return timeframes
+}
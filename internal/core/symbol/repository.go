@@ -0,0 +1,122 @@
+package symbol
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+	"github.com/lib/pq"
+)
+
+type SymbolRepository struct {
+}
+
+func NewSymbolRepository() *SymbolRepository {
+	return &SymbolRepository{}
+}
+
+const symbolColumns = `symbol, base_asset, quote_asset, status, tick_size, step_size, min_qty, min_notional, quote_volume, enabled, updated_at`
+
+// upsertSymbols stores the exchangeInfo symbols, the enabled flag set by discovery is kept
+func (repository *SymbolRepository) upsertSymbols(records []*dto.SymbolDto) error {
+	tx, err := database.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("💾 error starting transaction: %v", err)
+	}
+
+	for _, record := range records {
+		_, err = tx.Exec(`INSERT INTO symbols (symbol, base_asset, quote_asset, status, tick_size, step_size, min_qty, min_notional, quote_volume, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+			ON CONFLICT (symbol) DO UPDATE
+			SET base_asset = EXCLUDED.base_asset,
+			quote_asset = EXCLUDED.quote_asset,
+			status = EXCLUDED.status,
+			tick_size = EXCLUDED.tick_size,
+			step_size = EXCLUDED.step_size,
+			min_qty = EXCLUDED.min_qty,
+			min_notional = EXCLUDED.min_notional,
+			quote_volume = EXCLUDED.quote_volume,
+			updated_at = NOW()`,
+			record.Symbol, record.BaseAsset, record.QuoteAsset, record.Status, record.TickSize, record.StepSize,
+			record.MinQty, record.MinNotional, record.QuoteVolume)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("💾 error upserting symbol %s: %v", record.Symbol, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("💾 error committing transaction: %v", err)
+	}
+	return nil
+}
+
+func (repository *SymbolRepository) getSymbol(symbol string) (*dto.SymbolDto, error) {
+	rows, err := database.DB.Query(`SELECT `+symbolColumns+` FROM symbols WHERE symbol = $1`, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching symbol: %v", err)
+	}
+	records, err := scanSymbols(rows)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0], nil
+}
+
+func (repository *SymbolRepository) getSymbols(symbols []string) ([]*dto.SymbolDto, error) {
+	rows, err := database.DB.Query(`SELECT `+symbolColumns+` FROM symbols WHERE symbol = ANY($1)`, pq.Array(symbols))
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching symbols: %v", err)
+	}
+	return scanSymbols(rows)
+}
+
+func (repository *SymbolRepository) getEnabled() ([]*dto.SymbolDto, error) {
+	rows, err := database.DB.Query(`SELECT ` + symbolColumns + ` FROM symbols WHERE enabled ORDER BY symbol`)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching enabled symbols: %v", err)
+	}
+	return scanSymbols(rows)
+}
+
+// getTopByQuoteVolume returns the trading symbols of the quote asset with the highest 24h quote volume
+func (repository *SymbolRepository) getTopByQuoteVolume(quoteAsset string, status string, limit int) ([]*dto.SymbolDto, error) {
+	rows, err := database.DB.Query(`SELECT `+symbolColumns+` FROM symbols
+		WHERE quote_asset = $1 AND status = $2
+		ORDER BY quote_volume DESC
+		LIMIT $3`, quoteAsset, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("💾 error fetching top symbols: %v", err)
+	}
+	return scanSymbols(rows)
+}
+
+func (repository *SymbolRepository) enable(symbol string) error {
+	_, err := database.DB.Exec(`UPDATE symbols SET enabled = TRUE WHERE symbol = $1`, symbol)
+	if err != nil {
+		return fmt.Errorf("💾 error enabling symbol %s: %v", symbol, err)
+	}
+	return nil
+}
+
+func scanSymbols(rows *sql.Rows) ([]*dto.SymbolDto, error) {
+	defer rows.Close()
+
+	var records []*dto.SymbolDto
+	for rows.Next() {
+		var record dto.SymbolDto
+		err := rows.Scan(&record.Symbol, &record.BaseAsset, &record.QuoteAsset, &record.Status, &record.TickSize, &record.StepSize,
+			&record.MinQty, &record.MinNotional, &record.QuoteVolume, &record.Enabled, &record.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("💾 error scanning row: %v", err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("💾 error iterating rows: %v", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,144 @@
+package symbol
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+)
+
+type SymbolService struct {
+	repository SymbolRepository
+	validator  validator.Validator
+}
+
+func NewSymbolService() *SymbolService {
+	repository := NewSymbolRepository()
+	validator := validator.NewValidator()
+	return &SymbolService{
+		repository: *repository,
+		validator:  *validator,
+	}
+}
+
+// Sync stores the binance exchangeInfo symbols with their 24h quote volume and refreshes the statuses of the tracked ones
+func (service *SymbolService) Sync() (int, error) {
+	symbols, err := binance.FetchExchangeInfo()
+	if err != nil {
+		return 0, err
+	}
+	volumes, err := binance.FetchQuoteVolumes()
+	if err != nil {
+		return 0, err
+	}
+	for _, symbol := range symbols {
+		symbol.QuoteVolume = volumes[symbol.Symbol]
+	}
+
+	if err := service.repository.upsertSymbols(symbols); err != nil {
+		return 0, err
+	}
+	log.Printf(config.COLOR_BLUE+"synced %d binance symbols"+config.COLOR_RESET, len(symbols))
+	return len(symbols), service.Load()
+}
+
+// Load tracks the symbols enabled by discovery and caches the status of every tracked symbol, ingestion skips the ones not trading
+func (service *SymbolService) Load() error {
+	enabled, err := service.repository.getEnabled()
+	if err != nil {
+		return err
+	}
+	for _, symbol := range enabled {
+		instrument.Enable(keyOf(symbol))
+	}
+
+	keys := instrument.TrackedKeys()
+	symbols := make([]string, len(keys))
+	for i, key := range keys {
+		symbols[i] = instrument.Symbol(key)
+	}
+	records, err := service.repository.getSymbols(symbols)
+	if err != nil {
+		return err
+	}
+
+	statuses := make(map[string]string, len(records))
+	for _, record := range records {
+		statuses[keyOf(record)] = record.Status
+		if record.Status != config.SYMBOL_STATUS_TRADING {
+			log.Printf(config.COLOR_YELLOW+"%s is %s on binance, it is not ingested"+config.COLOR_RESET, record.Symbol, record.Status)
+		}
+	}
+	instrument.SetStatuses(statuses)
+	return nil
+}
+
+// GetSymbol returns the exchangeInfo metadata of a tracked currency
+func (service *SymbolService) GetSymbol(currency string) (*dto.SymbolDto, error) {
+	if err := service.validator.ValidateCurrency(currency); err != nil {
+		return nil, err
+	}
+	record, err := service.repository.getSymbol(instrument.Symbol(currency))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("symbol %s has not been synced", instrument.Symbol(currency))
+	}
+	return record, nil
+}
+
+// Discover returns the symbols among the DISCOVERY_TOP_N of DISCOVERY_QUOTE by 24h quote volume that are not tracked yet.
+// In auto mode their tables are provisioned and they are tracked right away.
+func (service *SymbolService) Discover() ([]*dto.SymbolDto, error) {
+	cfg := config.LoadConfig()
+	top, err := service.repository.getTopByQuoteVolume(strings.ToUpper(cfg.DiscoveryQuote), config.SYMBOL_STATUS_TRADING, cfg.DiscoveryTopN)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := instrument.TrackedKeys()
+	var discovered []*dto.SymbolDto
+	for _, symbol := range top {
+		if !slices.Contains(tracked, keyOf(symbol)) {
+			discovered = append(discovered, symbol)
+		}
+	}
+
+	for _, symbol := range discovered {
+		if cfg.DiscoveryMode != config.DISCOVERY_MODE_AUTO {
+			log.Printf(config.COLOR_CYAN+"discovered %s, 24h quote volume %s"+config.COLOR_RESET, symbol.Symbol, symbol.QuoteVolume.StringFixed(0))
+			continue
+		}
+		if err := service.enable(symbol); err != nil {
+			return nil, err
+		}
+		log.Printf(config.COLOR_GREEN+"enabled %s, 24h quote volume %s"+config.COLOR_RESET, symbol.Symbol, symbol.QuoteVolume.StringFixed(0))
+	}
+	return discovered, nil
+}
+
+func (service *SymbolService) enable(symbol *dto.SymbolDto) error {
+	key := keyOf(symbol)
+	if err := database.EnsureInstrumentTables(key); err != nil {
+		return err
+	}
+	if err := service.repository.enable(symbol.Symbol); err != nil {
+		return err
+	}
+	symbol.Enabled = true
+	instrument.Enable(key)
+	return nil
+}
+
+// keyOf returns the instrument key of a binance symbol, e.g. ETH/BTC => eth_btc
+func keyOf(symbol *dto.SymbolDto) string {
+	return instrument.New(symbol.BaseAsset, symbol.QuoteAsset).Key()
+}
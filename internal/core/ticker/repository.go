@@ -0,0 +1,60 @@
+package ticker
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+)
+
+type TickerRepository struct {
+}
+
+func NewTickerRepository() *TickerRepository {
+	return &TickerRepository{}
+}
+
+func (repository *TickerRepository) getLatest(currency string) (*dto.TickerDto, error) {
+	query := fmt.Sprintf(`SELECT id, symbol, timestamp, last_price, open_price, high_price, low_price, price_change,
+						  price_change_percent, weighted_avg_price, volume, quote_volume, trade_count
+						  FROM ticker_%s
+						  ORDER BY timestamp DESC LIMIT 1`, currency)
+
+	var record dto.TickerDto
+	err := database.DB.QueryRow(query).Scan(&record.Id, &record.Symbol, &record.Timestamp, &record.LastPrice, &record.OpenPrice,
+		&record.HighPrice, &record.LowPrice, &record.PriceChange, &record.PriceChangePercent, &record.WeightedAvgPrice,
+		&record.Volume, &record.QuoteVolume, &record.TradeCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("💾 error fetching ticker: %v", err)
+	}
+	return &record, nil
+}
+
+func (repository *TickerRepository) upsert(currency string, ticker *dto.TickerDto) error {
+	query := fmt.Sprintf(`
+		INSERT INTO ticker_%s (symbol, timestamp, last_price, open_price, high_price, low_price, price_change,
+			price_change_percent, weighted_avg_price, volume, quote_volume, trade_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (timestamp) DO UPDATE
+		SET last_price = EXCLUDED.last_price,
+			open_price = EXCLUDED.open_price,
+			high_price = EXCLUDED.high_price,
+			low_price = EXCLUDED.low_price,
+			price_change = EXCLUDED.price_change,
+			price_change_percent = EXCLUDED.price_change_percent,
+			weighted_avg_price = EXCLUDED.weighted_avg_price,
+			volume = EXCLUDED.volume,
+			quote_volume = EXCLUDED.quote_volume,
+			trade_count = EXCLUDED.trade_count`, currency)
+
+	_, err := database.DB.Exec(query, ticker.Symbol, ticker.Timestamp, ticker.LastPrice, ticker.OpenPrice, ticker.HighPrice, ticker.LowPrice,
+		ticker.PriceChange, ticker.PriceChangePercent, ticker.WeightedAvgPrice, ticker.Volume, ticker.QuoteVolume, ticker.TradeCount)
+	if err != nil {
+		return fmt.Errorf("💾 error upserting ticker: %v", err)
+	}
+	return nil
+}
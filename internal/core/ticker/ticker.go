@@ -0,0 +1,104 @@
+package ticker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+)
+
+type TickerService struct {
+	repository TickerRepository
+	redis      redis.RedisServiceInterface
+	validator  validator.Validator
+}
+
+func NewTickerService(redis redis.RedisServiceInterface) *TickerService {
+	repository := NewTickerRepository()
+	validator := validator.NewValidator()
+	return &TickerService{
+		repository: *repository,
+		redis:      redis,
+		validator:  *validator,
+	}
+}
+
+// CollectSnapshot fetches the 24h stats of the currency, stores them per minute and caches them in redis as the latest ticker
+func (service *TickerService) CollectSnapshot(currency string) (*dto.TickerDto, error) {
+	if err := service.validator.ValidateCurrency(currency); err != nil {
+		return nil, err
+	}
+
+	ticker, err := binance.FetchTickerSnapshot(currency)
+	if err != nil {
+		return nil, err
+	}
+	ticker.Timestamp = ticker.Timestamp.Truncate(time.Minute)
+	if err := service.repository.upsert(currency, ticker); err != nil {
+		return nil, err
+	}
+
+	value, err := json.Marshal(ticker)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.redis.Set(context.Background(), cacheKey(currency), string(value), 0); err != nil {
+		return nil, err
+	}
+
+	log.Printf(config.COLOR_BLUE+"ticker snapshot currency:%s last price:%s"+config.COLOR_RESET, currency, ticker.LastPrice)
+	return ticker, service.publishEvent(config.EVENT_NEW_TICKER_ADDED)
+}
+
+// GetLatest returns the latest ticker of the currency from redis, or from the database when it is not cached
+func (service *TickerService) GetLatest(currency string) (*dto.TickerDto, error) {
+	if err := service.validator.ValidateCurrency(currency); err != nil {
+		return nil, err
+	}
+
+	value, err := service.redis.Get(context.Background(), cacheKey(currency))
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return service.repository.getLatest(currency)
+	}
+
+	var ticker dto.TickerDto
+	if err := json.Unmarshal([]byte(value), &ticker); err != nil {
+		return nil, fmt.Errorf("error decoding cached ticker of %s: %w", currency, err)
+	}
+	return &ticker, nil
+}
+
+// GetLatestForAll returns the latest ticker of every tracked currency, currencies without a snapshot yet are left out
+func (service *TickerService) GetLatestForAll() ([]*dto.TickerDto, error) {
+	var tickers []*dto.TickerDto
+	for _, currency := range instrument.TrackedKeys() {
+		ticker, err := service.GetLatest(currency)
+		if err != nil {
+			return nil, err
+		}
+		if ticker != nil {
+			tickers = append(tickers, ticker)
+		}
+	}
+	return tickers, nil
+}
+
+func cacheKey(currency string) string {
+	return "ticker:" + currency
+}
+
+func (service *TickerService) publishEvent(eventName string) error {
+	ctx := context.Background()
+	return service.redis.PublishEvent(ctx, eventName, config.APPLICATION_NAME)
+}
@@ -0,0 +1,50 @@
+package trade
+
+import (
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/shopspring/decimal"
+)
+
+// BuildCandles buckets trades ordered by time into candles of the given interval (1m, 1h, ...).
+// A candle is complete once its interval has ended before "now", buckets without trades are skipped.
+func BuildCandles(symbol string, timeframe string, interval time.Duration, trades []dto.AggTradeDto, now time.Time) []*dto.DataDto {
+	var candles []*dto.DataDto
+	var candle *dto.DataDto
+
+	for _, trade := range trades {
+		bucket := trade.Timestamp.Truncate(interval)
+		if candle == nil || !candle.Timestamp.Equal(bucket) {
+			candle = &dto.DataDto{
+				Symbol:     symbol,
+				Timeframe:  timeframe,
+				Timestamp:  bucket,
+				Open:       trade.Price,
+				High:       trade.Price,
+				Low:        trade.Price,
+				IsComplete: !bucket.Add(interval).After(now),
+			}
+			candles = append(candles, candle)
+		}
+
+		quoteQuantity := trade.Price.Mul(trade.Quantity)
+		candle.Close = trade.Price
+		candle.High = decimal.Max(candle.High, trade.Price)
+		candle.Low = decimal.Min(candle.Low, trade.Price)
+		candle.Volume = candle.Volume.Add(trade.Quantity)
+		candle.QuoteVolume = candle.QuoteVolume.Add(quoteQuantity)
+		candle.TradeCount += trade.TradeCount
+		if !trade.IsBuyerMaker {
+			candle.TakerBuyBaseVolume = candle.TakerBuyBaseVolume.Add(trade.Quantity)
+			candle.TakerBuyQuoteVolume = candle.TakerBuyQuoteVolume.Add(quoteQuantity)
+		}
+	}
+
+	for _, candle := range candles {
+		if candle.Volume.IsPositive() {
+			candle.Vwap = candle.QuoteVolume.Div(candle.Volume)
+		}
+	}
+	return candles
+}
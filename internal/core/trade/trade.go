@@ -0,0 +1,169 @@
+package trade
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/shopspring/decimal"
+)
+
+// relative difference under which a trade-built value is considered equal to the kline value,
+// binance rounds quote volumes to 8 decimals so summed trades may differ in the last digits
+var reconcileTolerance = decimal.New(1, -8)
+
+type TradeService struct {
+	marketDataService *marketdata.MarketDataService
+	validator         validator.Validator
+}
+
+func NewTradeService(marketDataService *marketdata.MarketDataService) *TradeService {
+	validator := validator.NewValidator()
+	return &TradeService{
+		marketDataService: marketDataService,
+		validator:         *validator,
+	}
+}
+
+// IngestHours builds 1h candles of [from, to) from binance aggregate trades and stores them like klines
+func (service *TradeService) IngestHours(currency string, from time.Time, to time.Time) ([]*dto.DataDto, error) {
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, config.ONE_HOUR); err != nil {
+		return nil, err
+	}
+
+	candles, err := service.buildHourCandles(currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf(config.COLOR_BLUE+"built %d candles from trades currency:%s"+config.COLOR_RESET, len(candles), currency)
+
+	candles, err = service.marketDataService.ScreenData(currency, candles)
+	if err != nil {
+		return nil, err
+	}
+	return candles, service.marketDataService.UpsertBatchData(currency, candles)
+}
+
+// Reconcile compares 1h candles built from aggregate trades with the binance klines of [from, to)
+func (service *TradeService) Reconcile(currency string, from time.Time, to time.Time) (*dto.ReconciliationReportDto, error) {
+	if err := service.validator.ValidateCurrencyAndTimeframe(currency, config.ONE_HOUR); err != nil {
+		return nil, err
+	}
+	from = from.UTC().Truncate(time.Hour)
+	to = to.UTC().Truncate(time.Hour)
+	if !from.Before(to) {
+		return nil, fmt.Errorf("empty range %s - %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	candles, err := service.buildHourCandles(currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	klines, err := service.fetchHourKlines(currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	candlesByHour := make(map[time.Time]*dto.DataDto, len(candles))
+	for _, candle := range candles {
+		candlesByHour[candle.Timestamp.UTC()] = candle
+	}
+	klinesByHour := make(map[time.Time]*dto.DataDto, len(klines))
+	for _, kline := range klines {
+		klinesByHour[kline.Timestamp.UTC()] = kline
+	}
+
+	report := &dto.ReconciliationReportDto{
+		Currency:  currency,
+		StartTime: from,
+		EndTime:   to,
+	}
+	for hour := from; hour.Before(to); hour = hour.Add(time.Hour) {
+		row := dto.ReconciliationDto{
+			Timestamp:   hour,
+			TradeCandle: candlesByHour[hour],
+			Kline:       klinesByHour[hour],
+		}
+		row.Mismatches = compareCandles(row.TradeCandle, row.Kline)
+		if len(row.Mismatches) == 0 {
+			report.MatchedHours++
+		} else {
+			report.MismatchedHours++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report, nil
+}
+
+func (service *TradeService) buildHourCandles(currency string, from time.Time, to time.Time) ([]*dto.DataDto, error) {
+	trades, err := binance.FetchAggTrades(currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return BuildCandles(instrument.Symbol(currency), config.ONE_HOUR, time.Hour, trades, clock.Now()), nil
+}
+
+func (service *TradeService) fetchHourKlines(currency string, from time.Time, to time.Time) ([]*dto.DataDto, error) {
+	var klines []*dto.DataDto
+	for pageStart := from; pageStart.Before(to); {
+		page, err := binance.FetchKlineRange(currency, config.ONE_HOUR, pageStart, to.Add(-time.Millisecond), binance.MaxKlineLimit)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		klines = append(klines, page...)
+		pageStart = page[len(page)-1].Timestamp.Add(time.Hour)
+	}
+	return klines, nil
+}
+
+// compareCandles returns the names of the fields that differ between the trade-built candle and the kline
+func compareCandles(candle *dto.DataDto, kline *dto.DataDto) []string {
+	if candle == nil && kline == nil {
+		return nil
+	}
+	if candle == nil {
+		return []string{"missing trades"}
+	}
+	if kline == nil {
+		return []string{"missing kline"}
+	}
+
+	fields := []struct {
+		name    string
+		trade   decimal.Decimal
+		binance decimal.Decimal
+	}{
+		{"open", candle.Open, kline.Open},
+		{"high", candle.High, kline.High},
+		{"low", candle.Low, kline.Low},
+		{"close", candle.Close, kline.Close},
+		{"volume", candle.Volume, kline.Volume},
+		{"quote_volume", candle.QuoteVolume, kline.QuoteVolume},
+		{"trade_count", decimal.NewFromInt(candle.TradeCount), decimal.NewFromInt(kline.TradeCount)},
+		{"taker_buy_base_volume", candle.TakerBuyBaseVolume, kline.TakerBuyBaseVolume},
+		{"taker_buy_quote_volume", candle.TakerBuyQuoteVolume, kline.TakerBuyQuoteVolume},
+	}
+
+	var mismatches []string
+	for _, field := range fields {
+		if !almostEqual(field.trade, field.binance) {
+			mismatches = append(mismatches, field.name)
+		}
+	}
+	return mismatches
+}
+
+func almostEqual(a decimal.Decimal, b decimal.Decimal) bool {
+	scale := decimal.Max(a.Abs(), b.Abs(), decimal.NewFromInt(1))
+	return a.Sub(b).Abs().LessThanOrEqual(reconcileTolerance.Mul(scale))
+}
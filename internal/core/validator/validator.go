@@ -5,6 +5,8 @@ import (
 	"slices"
 
 	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/exchange"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
 )
 
 type Validator struct{}
@@ -13,13 +15,35 @@ func NewValidator() *Validator {
 	return &Validator{}
 }
 
+func (v *Validator) ValidateCurrency(currency string) error {
+	if !instrument.IsKnown(currency) {
+		return fmt.Errorf("unknown currency: %s", currency)
+	}
+	return nil
+}
+
 func (v *Validator) ValidateCurrencyAndTimeframe(currency string, timeframe string) error {
-	if !slices.Contains(config.DefaultCurrencies, currency) {
+	if !instrument.IsKnown(currency) {
 		return fmt.Errorf("unknown currency: %s", currency)
 	} else {
-		if !slices.Contains(config.DefaultTimeframes, timeframe) {
+		if !slices.Contains(config.DefaultTimeframes, timeframe) && !slices.Contains(config.CompositeTimeframes, timeframe) {
 			return fmt.Errorf("unknown timeframe: %s", timeframe)
 		}
 	}
 	return nil
 }
+
+// ValidateTradable rejects currencies whose binance symbol is halted or delisted, symbols not synced yet are let through.
+// The binance status says nothing about currencies fetched from another exchange by MARKET_DATA_SOURCES, they are let through too.
+func (v *Validator) ValidateTradable(currency string) error {
+	if err := v.ValidateCurrency(currency); err != nil {
+		return err
+	}
+	if exchange.GetSourceNameForCurrency(currency) != config.EXCHANGE_BINANCE {
+		return nil
+	}
+	if status, ok := instrument.Status(currency); ok && status != config.SYMBOL_STATUS_TRADING {
+		return fmt.Errorf("%s is not trading: %s", instrument.Symbol(currency), status)
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package dto
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type OHLCRequestDto struct {
@@ -24,18 +26,27 @@ type IndicatorRequestDto struct {
 	SortOrder string
 }
 
+// DataDto holds prices and volumes as exact decimals, as they are quoted by the exchange and stored in NUMERIC columns
 type DataDto struct {
 	Id         *int
 	Symbol     string
 	Timeframe  string
 	Timestamp  time.Time
-	Open       float64
-	Close      float64
-	High       float64
-	Low        float64
-	Volume     float64
-	Trend      float64
+	Open       decimal.Decimal
+	Close      decimal.Decimal
+	High       decimal.Decimal
+	Low        decimal.Decimal
+	Volume     decimal.Decimal
+	Trend      decimal.Decimal
 	IsComplete bool
+
+	QuoteVolume         decimal.Decimal
+	TradeCount          int64
+	TakerBuyBaseVolume  decimal.Decimal
+	TakerBuyQuoteVolume decimal.Decimal
+	Vwap                decimal.Decimal
+
+	MedianPrice decimal.Decimal // median close of the venues of a composite candle
 }
 
 type IndicatorDto struct {
@@ -62,4 +73,322 @@ type BinanceTickerResponse struct {
 	LowPrice  string `json:"lowPrice"`
 	LastPrice string `json:"lastPrice"`
 	Volume    string `json:"volume"`
+
+	PriceChange        string `json:"priceChange"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	WeightedAvgPrice   string `json:"weightedAvgPrice"`
+	QuoteVolume        string `json:"quoteVolume"`
+	OpenTime           int64  `json:"openTime"`
+	CloseTime          int64  `json:"closeTime"`
+	Count              int64  `json:"count"`
+}
+
+type BinanceFundingRate struct {
+	Symbol      string `json:"symbol"`
+	FundingTime int64  `json:"fundingTime"`
+	FundingRate string `json:"fundingRate"`
+	MarkPrice   string `json:"markPrice"`
+}
+
+type BinanceOpenInterest struct {
+	Symbol       string `json:"symbol"`
+	OpenInterest string `json:"openInterest"`
+	Time         int64  `json:"time"`
+}
+
+type BinanceServerTime struct {
+	ServerTime int64 `json:"serverTime"`
+}
+
+type BinanceExchangeInfo struct {
+	Symbols []BinanceSymbolInfo `json:"symbols"`
+}
+
+type BinanceSymbolInfo struct {
+	Symbol     string                `json:"symbol"`
+	Status     string                `json:"status"`
+	BaseAsset  string                `json:"baseAsset"`
+	QuoteAsset string                `json:"quoteAsset"`
+	Filters    []BinanceSymbolFilter `json:"filters"`
+}
+
+// BinanceSymbolFilter holds the fields of the PRICE_FILTER, LOT_SIZE and NOTIONAL filters, the other filters are ignored
+type BinanceSymbolFilter struct {
+	FilterType  string `json:"filterType"`
+	TickSize    string `json:"tickSize"`
+	StepSize    string `json:"stepSize"`
+	MinQty      string `json:"minQty"`
+	MinNotional string `json:"minNotional"`
+}
+
+type BinanceDepthResponse struct {
+	LastUpdateId int64      `json:"lastUpdateId"`
+	Bids         [][]string `json:"bids"`
+	Asks         [][]string `json:"asks"`
+}
+
+type BinanceAggTrade struct {
+	Id           int64  `json:"a"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	FirstTradeId int64  `json:"f"`
+	LastTradeId  int64  `json:"l"`
+	Time         int64  `json:"T"`
+	IsBuyerMaker bool   `json:"m"`
+	IsBestMatch  bool   `json:"M"` // declared so "M" is not decoded into IsBuyerMaker case-insensitively
+}
+
+type BinanceKlineEvent struct {
+	EventType string             `json:"e"`
+	EventTime int64              `json:"E"`
+	Symbol    string             `json:"s"`
+	Kline     BinanceStreamKline `json:"k"`
+}
+
+type BinanceStreamKline struct {
+	OpenTime  int64  `json:"t"`
+	CloseTime int64  `json:"T"`
+	Interval  string `json:"i"`
+	Open      string `json:"o"`
+	Close     string `json:"c"`
+	High      string `json:"h"`
+	Low       string `json:"l"`
+	Volume    string `json:"v"`
+	IsClosed  bool   `json:"x"`
+
+	QuoteVolume         string `json:"q"`
+	TradeCount          int64  `json:"n"`
+	TakerBuyBaseVolume  string `json:"V"`
+	TakerBuyQuoteVolume string `json:"Q"`
+}
+
+type PriceLevelDto struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// OrderBookDto holds bids ordered from the best (highest) price and asks from the best (lowest) price
+type OrderBookDto struct {
+	Symbol    string
+	Timestamp time.Time
+	Bids      []PriceLevelDto
+	Asks      []PriceLevelDto
+}
+
+// DepthSnapshotDto summarizes an order book, depths are the quote volume within the given percent of the mid price
+type DepthSnapshotDto struct {
+	Id         *int
+	Symbol     string
+	Timestamp  time.Time
+	BestBid    decimal.Decimal
+	BestAsk    decimal.Decimal
+	MidPrice   decimal.Decimal
+	Spread     decimal.Decimal
+	BidDepth05 decimal.Decimal
+	AskDepth05 decimal.Decimal
+	BidDepth1  decimal.Decimal
+	AskDepth1  decimal.Decimal
+	BidDepth2  decimal.Decimal
+	AskDepth2  decimal.Decimal
+}
+
+// QuarantineDto is a fetched candle that failed the data quality checks and was not stored
+type QuarantineDto struct {
+	Id        int64
+	Currency  string
+	Timeframe string
+	Timestamp time.Time
+	Reason    string
+	Record    DataDto
+	CreatedAt time.Time
+}
+
+type QuarantineRequestDto struct {
+	Currency  string
+	Timeframe string // all timeframes when empty
+	Limit     int
+}
+
+type DepthRequestDto struct {
+	Currency  string
+	StartTime *time.Time
+	EndTime   *time.Time
+	Limit     int32
+	SortOrder string
+}
+
+// TickerDto holds the rolling 24h stats of a currency at the moment of the snapshot
+type TickerDto struct {
+	Id                 *int
+	Symbol             string
+	Timestamp          time.Time
+	LastPrice          decimal.Decimal
+	OpenPrice          decimal.Decimal
+	HighPrice          decimal.Decimal
+	LowPrice           decimal.Decimal
+	PriceChange        decimal.Decimal
+	PriceChangePercent decimal.Decimal
+	WeightedAvgPrice   decimal.Decimal
+	Volume             decimal.Decimal
+	QuoteVolume        decimal.Decimal
+	TradeCount         int64
+}
+
+// SymbolDto is a binance spot symbol as listed by exchangeInfo
+type SymbolDto struct {
+	Symbol      string
+	BaseAsset   string
+	QuoteAsset  string
+	Status      string // TRADING, HALT, BREAK...
+	TickSize    decimal.Decimal
+	StepSize    decimal.Decimal // lot size
+	MinQty      decimal.Decimal
+	MinNotional decimal.Decimal
+	QuoteVolume decimal.Decimal // rolling 24h quote volume
+	Enabled     bool            // tracked by discovery
+	UpdatedAt   time.Time
+}
+
+// CurrencyDto is an instrument of the runtime currency registry
+type CurrencyDto struct {
+	Key        string // instrument key, e.g. "eth" or "eth_btc"
+	BaseAsset  string
+	QuoteAsset string
+	Status     string // active, paused
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type FundingRateDto struct {
+	Id          *int
+	Symbol      string
+	Timestamp   time.Time
+	FundingRate decimal.Decimal
+	MarkPrice   decimal.Decimal
+}
+
+// OpenInterestDto is the number of open perpetual contracts (in base asset) at a moment
+type OpenInterestDto struct {
+	Id           *int
+	Symbol       string
+	Timestamp    time.Time
+	OpenInterest decimal.Decimal
+}
+
+type MarkPriceDto struct {
+	Id         *int
+	Symbol     string
+	Timestamp  time.Time
+	Open       decimal.Decimal
+	High       decimal.Decimal
+	Low        decimal.Decimal
+	Close      decimal.Decimal
+	IsComplete bool
+}
+
+// BasisDto compares the 1h spot close with the perpetual mark price close of the same hour
+type BasisDto struct {
+	Timestamp time.Time
+	SpotPrice decimal.Decimal
+	MarkPrice decimal.Decimal
+	Basis     decimal.Decimal // mark price - spot price
+	BasisRate decimal.Decimal // basis relative to the spot price
+}
+
+type FuturesRequestDto struct {
+	Currency  string
+	StartTime *time.Time
+	EndTime   *time.Time
+	Limit     int32
+	SortField string
+	SortOrder string
+}
+
+// AggTradeDto is a binance aggregate trade, IsBuyerMaker=false means the buyer was the taker
+type AggTradeDto struct {
+	Id           int64
+	Price        decimal.Decimal
+	Quantity     decimal.Decimal
+	TradeCount   int64
+	Timestamp    time.Time
+	IsBuyerMaker bool
+}
+
+type ReconciliationDto struct {
+	Timestamp   time.Time
+	TradeCandle *DataDto
+	Kline       *DataDto
+	Mismatches  []string // fields that differ, empty when the candles match
+}
+
+type ReconciliationReportDto struct {
+	Currency        string
+	StartTime       time.Time
+	EndTime         time.Time
+	MatchedHours    int
+	MismatchedHours int
+	Rows            []ReconciliationDto
+}
+
+type BackfillCheckpointDto struct {
+	Currency      string
+	Timeframe     string
+	StartTime     time.Time
+	LastTimestamp *time.Time
+	IsComplete    bool
+	UpdatedAt     time.Time
+}
+
+type GapDto struct {
+	Currency      string
+	Timeframe     string
+	StartTime     time.Time
+	EndTime       time.Time
+	Attempts      int
+	DetectedAt    time.Time
+	LastAttemptAt time.Time
+}
+
+type GapReportDto struct {
+	Currency     string
+	Timeframe    string
+	MissingHours int
+	FilledHours  int
+	Unfilled     []GapDto
+}
+
+type RetentionReportDto struct {
+	Currency           string
+	Timeframe          string
+	Cutoff             time.Time
+	ArchivedRecords    int64
+	ArchivedIndicators int64
+}
+
+type ExportRequestDto struct {
+	Currency   string
+	Timeframe  string
+	StartTime  *time.Time
+	EndTime    *time.Time
+	Format     string
+	Columns    []string
+	TimeFormat string
+}
+
+// ExportRowDto is a candle with the indicator computed for it, Indicator is nil when there is none yet
+type ExportRowDto struct {
+	Data      DataDto
+	Indicator *IndicatorDto
+}
+
+type ImportReportDto struct {
+	Currency    string
+	Files       int      // dumps imported
+	Records     int      // rows loaded from the dumps
+	Overlapping int      // rows that were already stored
+	Conflicting int      // stored rows whose prices or volume differed, they are replaced by the dump
+	Quarantined int      // rows rejected by the data quality checks
+	Skipped     []string // dumps left out with the reason, e.g. a checksum mismatch
+	From        *time.Time
+	To          *time.Time
 }
@@ -0,0 +1,195 @@
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/parquet-go/parquet-go"
+)
+
+const monthLayout = "2006-01"
+
+// Archive keeps the candles and indicators deleted by the retention policies as zstd compressed parquet files,
+// one file per month: <dir>/<key>/<timeframe>/data_2024-01.parquet and indicator_2024-01.parquet
+type Archive struct {
+	dir string
+}
+
+func NewArchive(dir string) *Archive {
+	return &Archive{dir: dir}
+}
+
+// WriteCandles adds the records to the files of their months, a record already archived is replaced
+func (archive *Archive) WriteCandles(currency string, timeframe string, records []dto.DataDto) error {
+	rows := make([]candleRow, len(records))
+	for i, record := range records {
+		rows[i] = newCandleRow(record)
+	}
+	return writeMonthly(archive.path(currency, timeframe, "data"), rows, func(row candleRow) time.Time { return row.Timestamp })
+}
+
+// ReadCandles returns up to limit (all when negative) archived records between from and to (inclusive, unbounded when nil)
+// ordered by timestamp, newest first when descending. Months are decoded one at a time until limit records are read.
+func (archive *Archive) ReadCandles(currency string, timeframe string, from *time.Time, to *time.Time, limit int, descending bool) ([]dto.DataDto, error) {
+	rows, err := readMonthly(archive.path(currency, timeframe, "data"), from, to, limit, descending, func(row candleRow) time.Time { return row.Timestamp })
+	if err != nil {
+		return nil, err
+	}
+	records := make([]dto.DataDto, len(rows))
+	for i, row := range rows {
+		records[i] = row.toDto()
+	}
+	return records, nil
+}
+
+// WriteIndicators adds the records to the files of their months, a record already archived is replaced
+func (archive *Archive) WriteIndicators(currency string, timeframe string, records []dto.IndicatorDto) error {
+	rows := make([]indicatorRow, len(records))
+	for i, record := range records {
+		rows[i] = newIndicatorRow(record)
+	}
+	return writeMonthly(archive.path(currency, timeframe, "indicator"), rows, func(row indicatorRow) time.Time { return row.Timestamp })
+}
+
+// ReadIndicators returns the archived records between from and to (inclusive, unbounded when nil) ordered by timestamp
+func (archive *Archive) ReadIndicators(currency string, timeframe string, from *time.Time, to *time.Time) ([]dto.IndicatorDto, error) {
+	rows, err := readMonthly(archive.path(currency, timeframe, "indicator"), from, to, -1, false, func(row indicatorRow) time.Time { return row.Timestamp })
+	if err != nil {
+		return nil, err
+	}
+	records := make([]dto.IndicatorDto, len(rows))
+	for i, row := range rows {
+		records[i] = row.toDto()
+	}
+	return records, nil
+}
+
+// path returns the file of a month, e.g. archive/btc/1h/data_2024-01.parquet
+func (archive *Archive) path(currency string, timeframe string, kind string) func(month string) string {
+	return func(month string) string {
+		return filepath.Join(archive.dir, currency, timeframe, kind+"_"+month+".parquet")
+	}
+}
+
+func writeMonthly[T any](path func(month string) string, rows []T, timestamp func(T) time.Time) error {
+	byMonth := make(map[string][]T)
+	for _, row := range rows {
+		month := timestamp(row).UTC().Format(monthLayout)
+		byMonth[month] = append(byMonth[month], row)
+	}
+
+	for month, monthRows := range byMonth {
+		file := path(month)
+		existing, err := readFile[T](file)
+		if err != nil {
+			return err
+		}
+		merged := mergeByTimestamp(existing, monthRows, timestamp)
+		if err := writeFile(file, merged); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readMonthly[T any](path func(month string) string, from *time.Time, to *time.Time, limit int, descending bool, timestamp func(T) time.Time) ([]T, error) {
+	if limit == 0 {
+		return nil, nil
+	}
+	files, err := filepath.Glob(path("*"))
+	if err != nil {
+		return nil, fmt.Errorf("🗄️ error listing archive: %v", err)
+	}
+	slices.Sort(files)
+	if descending {
+		slices.Reverse(files)
+	}
+
+	var rows []T
+	for _, file := range files {
+		month, err := time.Parse(monthLayout, strings.TrimSuffix(filepath.Base(file)[strings.LastIndex(filepath.Base(file), "_")+1:], ".parquet"))
+		if err != nil {
+			continue
+		}
+		if (from != nil && !month.AddDate(0, 1, 0).After(*from)) || (to != nil && month.After(*to)) {
+			continue
+		}
+		fileRows, err := readFile[T](file)
+		if err != nil {
+			return nil, err
+		}
+		if descending {
+			slices.Reverse(fileRows)
+		}
+		for _, row := range fileRows {
+			moment := timestamp(row)
+			if (from == nil || !moment.Before(*from)) && (to == nil || !moment.After(*to)) {
+				rows = append(rows, row)
+			}
+			if limit >= 0 && len(rows) >= limit {
+				return rows, nil
+			}
+		}
+	}
+	return rows, nil
+}
+
+// readFile returns no rows when the file does not exist
+func readFile[T any](file string) ([]T, error) {
+	rows, err := parquet.ReadFile[T](file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("🗄️ error reading %s: %v", file, err)
+	}
+	return rows, nil
+}
+
+// writeFile replaces the file through a temporary one so a failed write never loses archived rows
+func writeFile[T any](file string, rows []T) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("🗄️ error creating archive directory: %v", err)
+	}
+	temporary, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("🗄️ error creating %s: %v", file, err)
+	}
+	defer os.Remove(temporary.Name())
+
+	writer := parquet.NewGenericWriter[T](temporary, parquet.Compression(&parquet.Zstd))
+	if _, err := writer.Write(rows); err != nil {
+		temporary.Close()
+		return fmt.Errorf("🗄️ error writing %s: %v", file, err)
+	}
+	if err := writer.Close(); err != nil {
+		temporary.Close()
+		return fmt.Errorf("🗄️ error writing %s: %v", file, err)
+	}
+	if err := temporary.Close(); err != nil {
+		return fmt.Errorf("🗄️ error writing %s: %v", file, err)
+	}
+	if err := os.Rename(temporary.Name(), file); err != nil {
+		return fmt.Errorf("🗄️ error replacing %s: %v", file, err)
+	}
+	return nil
+}
+
+// mergeByTimestamp returns the rows ordered by timestamp, added rows replace the existing ones of the same timestamp
+func mergeByTimestamp[T any](existing []T, added []T, timestamp func(T) time.Time) []T {
+	byTimestamp := make(map[int64]T, len(existing)+len(added))
+	for _, row := range slices.Concat(existing, added) {
+		byTimestamp[timestamp(row).UnixMilli()] = row
+	}
+	merged := make([]T, 0, len(byTimestamp))
+	for _, row := range byTimestamp {
+		merged = append(merged, row)
+	}
+	slices.SortFunc(merged, func(a, b T) int { return timestamp(a).Compare(timestamp(b)) })
+	return merged
+}
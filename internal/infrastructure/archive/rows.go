@@ -0,0 +1,122 @@
+package archive
+
+import (
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+// candleRow is the parquet layout of a candle, prices and volumes are kept as exact decimal strings
+type candleRow struct {
+	Timestamp           time.Time `parquet:"timestamp,timestamp(millisecond)"`
+	Symbol              string    `parquet:"symbol,dict"`
+	Timeframe           string    `parquet:"timeframe,dict"`
+	Open                string    `parquet:"open"`
+	High                string    `parquet:"high"`
+	Low                 string    `parquet:"low"`
+	Close               string    `parquet:"close"`
+	Volume              string    `parquet:"volume"`
+	Trend               string    `parquet:"trend"`
+	IsComplete          bool      `parquet:"is_complete"`
+	QuoteVolume         string    `parquet:"quote_volume"`
+	TradeCount          int64     `parquet:"trade_count"`
+	TakerBuyBaseVolume  string    `parquet:"taker_buy_base_volume"`
+	TakerBuyQuoteVolume string    `parquet:"taker_buy_quote_volume"`
+	Vwap                string    `parquet:"vwap"`
+	MedianPrice         string    `parquet:"median_price"`
+}
+
+func newCandleRow(record dto.DataDto) candleRow {
+	return candleRow{
+		Timestamp:           record.Timestamp.UTC(),
+		Symbol:              record.Symbol,
+		Timeframe:           record.Timeframe,
+		Open:                record.Open.String(),
+		High:                record.High.String(),
+		Low:                 record.Low.String(),
+		Close:               record.Close.String(),
+		Volume:              record.Volume.String(),
+		Trend:               record.Trend.String(),
+		IsComplete:          record.IsComplete,
+		QuoteVolume:         record.QuoteVolume.String(),
+		TradeCount:          record.TradeCount,
+		TakerBuyBaseVolume:  record.TakerBuyBaseVolume.String(),
+		TakerBuyQuoteVolume: record.TakerBuyQuoteVolume.String(),
+		Vwap:                record.Vwap.String(),
+		MedianPrice:         record.MedianPrice.String(),
+	}
+}
+
+func (row candleRow) toDto() dto.DataDto {
+	return dto.DataDto{
+		Symbol:              row.Symbol,
+		Timeframe:           row.Timeframe,
+		Timestamp:           row.Timestamp.UTC(),
+		Open:                utils.ParseDecimal(row.Open),
+		High:                utils.ParseDecimal(row.High),
+		Low:                 utils.ParseDecimal(row.Low),
+		Close:               utils.ParseDecimal(row.Close),
+		Volume:              utils.ParseDecimal(row.Volume),
+		Trend:               utils.ParseDecimal(row.Trend),
+		IsComplete:          row.IsComplete,
+		QuoteVolume:         utils.ParseDecimal(row.QuoteVolume),
+		TradeCount:          row.TradeCount,
+		TakerBuyBaseVolume:  utils.ParseDecimal(row.TakerBuyBaseVolume),
+		TakerBuyQuoteVolume: utils.ParseDecimal(row.TakerBuyQuoteVolume),
+		Vwap:                utils.ParseDecimal(row.Vwap),
+		MedianPrice:         utils.ParseDecimal(row.MedianPrice),
+	}
+}
+
+type indicatorRow struct {
+	Timestamp      time.Time `parquet:"timestamp,timestamp(millisecond)"`
+	DataTimestamp  time.Time `parquet:"data_timestamp,timestamp(millisecond)"`
+	Timeframe      string    `parquet:"timeframe,dict"`
+	SMA            float64   `parquet:"sma"`
+	EMA            float64   `parquet:"ema"`
+	TR             float64   `parquet:"tr"`
+	StdDev         float64   `parquet:"std_dev"`
+	LowerBollinger float64   `parquet:"lower_bollinger"`
+	UpperBollinger float64   `parquet:"upper_bollinger"`
+	RSI            float64   `parquet:"rsi"`
+	Volatility     float64   `parquet:"volatility"`
+	MACD           float64   `parquet:"macd"`
+	MACDSignal     float64   `parquet:"macd_signal"`
+}
+
+func newIndicatorRow(record dto.IndicatorDto) indicatorRow {
+	return indicatorRow{
+		Timestamp:      record.Timestamp.UTC(),
+		DataTimestamp:  record.DataTimestamp.UTC(),
+		Timeframe:      record.Timeframe,
+		SMA:            record.SMA,
+		EMA:            record.EMA,
+		TR:             record.TR,
+		StdDev:         record.StdDev,
+		LowerBollinger: record.LowerBollinger,
+		UpperBollinger: record.UpperBollinger,
+		RSI:            record.RSI,
+		Volatility:     record.Volatility,
+		MACD:           record.MACD,
+		MACDSignal:     record.MACDSignal,
+	}
+}
+
+func (row indicatorRow) toDto() dto.IndicatorDto {
+	return dto.IndicatorDto{
+		Timeframe:      row.Timeframe,
+		Timestamp:      row.Timestamp.UTC(),
+		DataTimestamp:  row.DataTimestamp.UTC(),
+		SMA:            row.SMA,
+		EMA:            row.EMA,
+		TR:             row.TR,
+		StdDev:         row.StdDev,
+		LowerBollinger: row.LowerBollinger,
+		UpperBollinger: row.UpperBollinger,
+		RSI:            row.RSI,
+		Volatility:     row.Volatility,
+		MACD:           row.MACD,
+		MACDSignal:     row.MACDSignal,
+	}
+}
@@ -5,20 +5,70 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/chyngyz-sydykov/marketpulse/config"
 	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+	"github.com/shopspring/decimal"
 )
 
-// FetchMarketData retrieves data from Binance API
+// MaxKlineLimit is the largest number of klines binance returns per request
+const MaxKlineLimit = 1000
+
+// FetchTicker returns the rolling 24h stats of the currency as a candle
 func FetchTicker(currency string) (*dto.DataDto, error) {
+	ticker, err := fetchTicker(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.DataDto{
+		Symbol:      ticker.Symbol,
+		Timestamp:   tickerTimestamp(ticker),
+		Timeframe:   config.ONE_HOUR,
+		Open:        utils.ParseDecimal(ticker.OpenPrice),
+		High:        utils.ParseDecimal(ticker.HighPrice),
+		Low:         utils.ParseDecimal(ticker.LowPrice),
+		Close:       utils.ParseDecimal(ticker.LastPrice),
+		Volume:      utils.ParseDecimal(ticker.Volume),
+		QuoteVolume: utils.ParseDecimal(ticker.QuoteVolume),
+		TradeCount:  ticker.Count,
+		Vwap:        utils.ParseDecimal(ticker.WeightedAvgPrice),
+	}, nil
+}
 
+// FetchTickerSnapshot retrieves the rolling 24h stats of the currency
+func FetchTickerSnapshot(currency string) (*dto.TickerDto, error) {
+	binanceTickerData, err := fetchTicker(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TickerDto{
+		Symbol:             binanceTickerData.Symbol,
+		Timestamp:          tickerTimestamp(binanceTickerData),
+		LastPrice:          utils.ParseDecimal(binanceTickerData.LastPrice),
+		OpenPrice:          utils.ParseDecimal(binanceTickerData.OpenPrice),
+		HighPrice:          utils.ParseDecimal(binanceTickerData.HighPrice),
+		LowPrice:           utils.ParseDecimal(binanceTickerData.LowPrice),
+		PriceChange:        utils.ParseDecimal(binanceTickerData.PriceChange),
+		PriceChangePercent: utils.ParseDecimal(binanceTickerData.PriceChangePercent),
+		WeightedAvgPrice:   utils.ParseDecimal(binanceTickerData.WeightedAvgPrice),
+		Volume:             utils.ParseDecimal(binanceTickerData.Volume),
+		QuoteVolume:        utils.ParseDecimal(binanceTickerData.QuoteVolume),
+		TradeCount:         binanceTickerData.Count,
+	}, nil
+}
+
+func fetchTicker(currency string) (*dto.BinanceTickerResponse, error) {
 	cfg := config.LoadConfig()
 	client := GetHTTPClient()
-	req, err := http.NewRequest("GET", cfg.BinanceBaseAPIUrl+"ticker/24hr?symbol="+strings.ToUpper(currency)+"USDT", nil)
+	req, err := http.NewRequest("GET", cfg.BinanceBaseAPIUrl+"ticker/24hr?symbol="+instrument.Symbol(currency), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -38,30 +88,78 @@ func FetchTicker(currency string) (*dto.DataDto, error) {
 	}
 
 	var binanceTickerData dto.BinanceTickerResponse
-
-	fmt.Println("body:", string(body))
 	if err := json.Unmarshal(body, &binanceTickerData); err != nil {
 		return nil, err
 	}
+	return &binanceTickerData, nil
+}
 
-	return &dto.DataDto{
-		Symbol:    binanceTickerData.Symbol,
-		Timestamp: time.Now(),
-		Timeframe: config.ONE_HOUR,
-		Open:      parseFloat(binanceTickerData.OpenPrice),
-		High:      parseFloat(binanceTickerData.HighPrice),
-		Low:       parseFloat(binanceTickerData.LowPrice),
-		Close:     parseFloat(binanceTickerData.LastPrice),
-		Volume:    parseFloat(binanceTickerData.Volume),
-	}, nil
+// tickerTimestamp is the close time of the rolling window, the time of the call when binance does not send it
+func tickerTimestamp(ticker *dto.BinanceTickerResponse) time.Time {
+	if ticker.CloseTime > 0 {
+		return time.UnixMilli(ticker.CloseTime).UTC()
+	}
+	return clock.Now()
 }
 
 // FetchMarketData retrieves data from Binance API
 func FetchKline(currency string, interval string, limit int) ([]*dto.DataDto, error) {
+	query := url.Values{}
+	query.Set("interval", interval)
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("symbol", instrument.Symbol(currency))
+
+	binanceKlineData, err := fetchKlines(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(binanceKlineData) == 0 {
+		return nil, fmt.Errorf("unexpected response format")
+	}
 
+	records := make([]*dto.DataDto, 0, len(binanceKlineData))
+	for _, element := range binanceKlineData {
+		record, err := mapKlineToDto(currency, interval, element, clock.Now())
+		if err != nil {
+			return nil, err
+		}
+		record.IsComplete = true
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// FetchKlineRange retrieves up to limit klines opened between startTime and endTime.
+// A kline that has not closed yet is returned with IsComplete=false, an empty slice means there is no more data in the range.
+func FetchKlineRange(currency string, interval string, startTime time.Time, endTime time.Time, limit int) ([]*dto.DataDto, error) {
+	query := url.Values{}
+	query.Set("interval", interval)
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("symbol", instrument.Symbol(currency))
+	query.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	query.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+
+	binanceKlineData, err := fetchKlines(query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := clock.Now()
+	records := make([]*dto.DataDto, 0, len(binanceKlineData))
+	for _, element := range binanceKlineData {
+		record, err := mapKlineToDto(currency, interval, element, now)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func fetchKlines(query url.Values) ([][]interface{}, error) {
 	cfg := config.LoadConfig()
 	client := GetHTTPClient()
-	req, err := http.NewRequest("GET", cfg.BinanceBaseAPIUrl+"klines?interval="+interval+"&limit="+strconv.Itoa(limit)+"&symbol="+strings.ToUpper(currency)+"USDT", nil)
+	req, err := http.NewRequest("GET", cfg.BinanceBaseAPIUrl+"klines?"+query.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -85,32 +183,48 @@ func FetchKline(currency string, interval string, limit int) ([]*dto.DataDto, er
 		return nil, err
 	}
 
-	if len(binanceKlineData) == 0 || len(binanceKlineData[0]) < 12 {
-		return nil, fmt.Errorf("unexpected response format")
-	}
+	return binanceKlineData, nil
+}
 
-	records := make([]*dto.DataDto, 0, len(binanceKlineData))
+// mapKlineToDto maps a kline row, the kline is complete when it closed before now
+func mapKlineToDto(currency string, interval string, element []interface{}, now time.Time) (*dto.DataDto, error) {
+	if len(element) < 12 {
+		return nil, fmt.Errorf("unexpected response format: kline has %d fields", len(element))
+	}
+	openTime, openOk := element[0].(float64)
+	closeTime, closeOk := element[6].(float64)
+	tradeCount, tradeCountOk := element[8].(float64)
+	if !openOk || !closeOk || !tradeCountOk {
+		return nil, fmt.Errorf("unexpected response format: open time is %T, close time is %T, trade count is %T", element[0], element[6], element[8])
+	}
+	values, err := utils.ParseDecimalFields(element, 1, 2, 3, 4, 5, 7, 9, 10)
+	if err != nil {
+		return nil, err
+	}
 
-	for i := 0; i < len(binanceKlineData); i++ {
-		element := binanceKlineData[i]
-		records = append(records, &dto.DataDto{
-			Symbol:     strings.ToUpper(currency) + "USDT",
-			Timestamp:  time.Unix(int64(element[0].(float64))/1000, 0).Truncate(time.Hour), // Kline open time with precision to hour
-			Timeframe:  config.ONE_HOUR,
-			Open:       parseFloat(element[1].(string)), // Open price
-			High:       parseFloat(element[2].(string)), // High price
-			Low:        parseFloat(element[3].(string)), // Low price
-			Close:      parseFloat(element[4].(string)), // Close price
-			Volume:     parseFloat(element[5].(string)), // Volume
-			IsComplete: true,
-		})
+	record := &dto.DataDto{
+		Symbol:    instrument.Symbol(currency),
+		Timestamp: time.UnixMilli(int64(openTime)).UTC(), // Kline open time
+		Timeframe: interval,
+		Open:      values[0], // Open price
+		High:      values[1], // High price
+		Low:       values[2], // Low price
+		Close:     values[3], // Close price
+		Volume:    values[4], // Volume
+
+		QuoteVolume:         values[5],                                    // Quote asset volume
+		TradeCount:          int64(tradeCount),                            // Number of trades
+		TakerBuyBaseVolume:  values[6],                                    // Taker buy base asset volume
+		TakerBuyQuoteVolume: values[7],                                    // Taker buy quote asset volume
+		IsComplete:          time.UnixMilli(int64(closeTime)).Before(now), // Kline close time
 	}
-	return records, nil
+	record.Vwap = vwap(record)
+	return record, nil
 }
-func parseFloat(value string) float64 {
-	parsed, err := strconv.ParseFloat(value, 64)
-	if err != nil {
-		return 0.0
+
+func vwap(record *dto.DataDto) decimal.Decimal {
+	if record.Volume.IsZero() {
+		return decimal.Zero
 	}
-	return parsed
+	return record.QuoteVolume.Div(record.Volume)
 }
@@ -0,0 +1,73 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+// FetchDepth returns the order book snapshot of the currency with up to limit levels per side
+func FetchDepth(currency string, limit int) (*dto.OrderBookDto, error) {
+	cfg := config.LoadConfig()
+	client := GetHTTPClient()
+	query := url.Values{}
+	query.Set("symbol", instrument.Symbol(currency))
+	query.Set("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequest("GET", cfg.BinanceBaseAPIUrl+"depth?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("binance API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var depth dto.BinanceDepthResponse
+	if err := json.Unmarshal(body, &depth); err != nil {
+		return nil, err
+	}
+	if len(depth.Bids) == 0 || len(depth.Asks) == 0 {
+		return nil, fmt.Errorf("empty order book for %s", currency)
+	}
+
+	return &dto.OrderBookDto{
+		Symbol:    instrument.Symbol(currency),
+		Timestamp: time.Now().UTC(),
+		Bids:      mapPriceLevels(depth.Bids),
+		Asks:      mapPriceLevels(depth.Asks),
+	}, nil
+}
+
+func mapPriceLevels(levels [][]string) []dto.PriceLevelDto {
+	mapped := make([]dto.PriceLevelDto, 0, len(levels))
+	for _, level := range levels {
+		if len(level) < 2 {
+			continue
+		}
+		mapped = append(mapped, dto.PriceLevelDto{
+			Price:    utils.ParseDecimal(level[0]),
+			Quantity: utils.ParseDecimal(level[1]),
+		})
+	}
+	return mapped
+}
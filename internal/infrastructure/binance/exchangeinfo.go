@@ -0,0 +1,82 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+	"github.com/shopspring/decimal"
+)
+
+// FetchExchangeInfo returns every spot symbol listed by binance with its status and trading filters
+func FetchExchangeInfo() ([]*dto.SymbolDto, error) {
+	var exchangeInfo dto.BinanceExchangeInfo
+	if err := fetchSpot("exchangeInfo", &exchangeInfo); err != nil {
+		return nil, err
+	}
+
+	records := make([]*dto.SymbolDto, 0, len(exchangeInfo.Symbols))
+	for _, symbol := range exchangeInfo.Symbols {
+		record := &dto.SymbolDto{
+			Symbol:     symbol.Symbol,
+			BaseAsset:  symbol.BaseAsset,
+			QuoteAsset: symbol.QuoteAsset,
+			Status:     symbol.Status,
+		}
+		for _, filter := range symbol.Filters {
+			switch filter.FilterType {
+			case "PRICE_FILTER":
+				record.TickSize = utils.ParseDecimal(filter.TickSize)
+			case "LOT_SIZE":
+				record.StepSize = utils.ParseDecimal(filter.StepSize)
+				record.MinQty = utils.ParseDecimal(filter.MinQty)
+			case "NOTIONAL", "MIN_NOTIONAL":
+				record.MinNotional = utils.ParseDecimal(filter.MinNotional)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// FetchQuoteVolumes returns the rolling 24h quote volume of every spot symbol, it costs 80 request weight
+func FetchQuoteVolumes() (map[string]decimal.Decimal, error) {
+	var tickers []dto.BinanceTickerResponse
+	if err := fetchSpot("ticker/24hr", &tickers); err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[string]decimal.Decimal, len(tickers))
+	for _, ticker := range tickers {
+		volumes[ticker.Symbol] = utils.ParseDecimal(ticker.QuoteVolume)
+	}
+	return volumes, nil
+}
+
+func fetchSpot(endpoint string, result any) error {
+	cfg := config.LoadConfig()
+	client := GetHTTPClient()
+	req, err := http.NewRequest("GET", cfg.BinanceBaseAPIUrl+endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("binance API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,307 @@
+package binance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/fakebinance"
+)
+
+// binance returns 500 klines when no limit is given
+const defaultKlineLimit = 500
+
+// query parameters that select a slice of the recorded klines instead of a fixture file
+var klineSliceParams = []string{"limit", "startTime", "endTime"}
+
+var recordMu sync.Mutex
+
+// mockServer generates the responses of mock mode in process, it needs neither fixtures nor network
+var mockServer = sync.OnceValue(func() *fakebinance.Server {
+	return fakebinance.NewServer(fakebinance.Options{})
+})
+
+// FixtureTransport records binance responses to fixture files keyed by endpoint and query
+// and replays them offline. Recorded klines of a symbol and interval are merged into one file,
+// so replay can serve any limit/startTime/endTime slice of them. Mock mode serves generated spot data instead.
+type FixtureTransport struct {
+	Mode          string // live, record, replay or mock
+	Dir           string
+	RealTransport http.RoundTripper
+}
+
+// CheckFixtures fails replay mode upfront when BINANCE_FIXTURES_DIR does not exist, otherwise every request would get a 404.
+// The fixtures under tests/fixtures/binance are served by the test servers, they are not in the recorded layout.
+func CheckFixtures() error {
+	cfg := config.LoadConfig()
+	if cfg.BinanceMode != config.BINANCE_MODE_REPLAY {
+		return nil
+	}
+	info, err := os.Stat(cfg.BinanceFixturesDir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("BINANCE_MODE=replay needs fixtures recorded with BINANCE_MODE=record, BINANCE_FIXTURES_DIR %q is not a directory", cfg.BinanceFixturesDir)
+	}
+	return nil
+}
+
+func (transport *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch transport.Mode {
+	case config.BINANCE_MODE_REPLAY:
+		return transport.replay(req)
+	case config.BINANCE_MODE_RECORD:
+		return transport.record(req)
+	case config.BINANCE_MODE_MOCK:
+		recorder := httptest.NewRecorder()
+		mockServer().ServeHTTP(recorder, req)
+		return recorder.Result(), nil
+	default:
+		return transport.RealTransport.RoundTrip(req)
+	}
+}
+
+func (transport *FixtureTransport) record(req *http.Request) (*http.Response, error) {
+	resp, err := transport.RealTransport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	if isKlineRequest(req) {
+		err = transport.recordKlines(req, body)
+	} else {
+		err = writeFixture(transport.fixturePath(req), body)
+	}
+	if err != nil {
+		log.Printf("%sError: recording %s: %s %s\n", config.COLOR_RED, req.URL.Path, err, config.COLOR_RED)
+	}
+	return resp, nil
+}
+
+// recordKlines merges the received rows into the recorded ones, ordered by open time
+func (transport *FixtureTransport) recordKlines(req *http.Request, body []byte) error {
+	var rows []json.RawMessage
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return err
+	}
+
+	path := transport.fixturePath(req)
+	recorded, err := readKlineRows(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	byOpenTime := make(map[int64]json.RawMessage, len(recorded)+len(rows))
+	for _, row := range append(recorded, rows...) {
+		openTime, err := klineOpenTime(row)
+		if err != nil {
+			return err
+		}
+		byOpenTime[openTime] = row
+	}
+	merged := make([]json.RawMessage, 0, len(byOpenTime))
+	for _, row := range byOpenTime {
+		merged = append(merged, row)
+	}
+	sortKlineRows(merged)
+
+	return writeFixture(path, encodeKlineRows(merged, "\n"))
+}
+
+func (transport *FixtureTransport) replay(req *http.Request) (*http.Response, error) {
+	path := transport.fixturePath(req)
+	if !isKlineRequest(req) {
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return fixtureNotFound(req, path), nil
+		}
+		return fixtureResponse(http.StatusOK, body), nil
+	}
+
+	rows, err := readKlineRows(path)
+	if err != nil {
+		return fixtureNotFound(req, path), nil
+	}
+	rows, err = sliceKlineRows(rows, req.URL.Query())
+	if err != nil {
+		return fixtureResponse(http.StatusBadRequest, []byte(fmt.Sprintf(`{"code":-1100,"msg":%q}`, err.Error()))), nil
+	}
+	return fixtureResponse(http.StatusOK, encodeKlineRows(rows, "")), nil
+}
+
+// sliceKlineRows applies startTime, endTime and limit the way binance does:
+// with a startTime the first rows are returned, otherwise the latest ones
+func sliceKlineRows(rows []json.RawMessage, query url.Values) ([]json.RawMessage, error) {
+	limit := defaultKlineLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit %s", value)
+		}
+		limit = parsed
+	}
+	startTime, err := parseMillis(query.Get("startTime"))
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := parseMillis(query.Get("endTime"))
+	if err != nil {
+		return nil, err
+	}
+
+	var sliced []json.RawMessage
+	for _, row := range rows {
+		openTime, err := klineOpenTime(row)
+		if err != nil {
+			return nil, err
+		}
+		if startTime != nil && openTime < *startTime {
+			continue
+		}
+		if endTime != nil && openTime > *endTime {
+			continue
+		}
+		sliced = append(sliced, row)
+	}
+
+	if len(sliced) <= limit {
+		return sliced, nil
+	}
+	if startTime != nil {
+		return sliced[:limit], nil
+	}
+	return sliced[len(sliced)-limit:], nil
+}
+
+// fixturePath returns <dir>/<endpoint>/<query>.json, slicing parameters of klines are not part of the key
+func (transport *FixtureTransport) fixturePath(req *http.Request) string {
+	endpoint := req.URL.Path
+	if index := strings.LastIndex(endpoint, "/api/v3/"); index >= 0 {
+		endpoint = endpoint[index+len("/api/v3/"):]
+	}
+	endpoint = strings.Trim(endpoint, "/")
+
+	query := req.URL.Query()
+	if isKlineRequest(req) {
+		for _, param := range klineSliceParams {
+			query.Del(param)
+		}
+	}
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"-"+strings.Join(query[key], ","))
+	}
+	name := strings.Join(parts, "_")
+	if name == "" {
+		name = "default"
+	}
+	return filepath.Join(transport.Dir, filepath.FromSlash(endpoint), name+".json")
+}
+
+// isKlineRequest reports whether the response is a list of kline rows, spot and futures klines and mark price klines
+func isKlineRequest(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/klines") || strings.HasSuffix(req.URL.Path, "/markPriceKlines")
+}
+
+func readKlineRows(path string) ([]json.RawMessage, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rows []json.RawMessage
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %v", path, err)
+	}
+	return rows, nil
+}
+
+// encodeKlineRows keeps every row byte-for-byte as binance sent it
+func encodeKlineRows(rows []json.RawMessage, separator string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("[" + separator)
+	for i, row := range rows {
+		if i > 0 {
+			buffer.WriteString("," + separator)
+		}
+		buffer.Write(row)
+	}
+	buffer.WriteString(separator + "]")
+	return buffer.Bytes()
+}
+
+func sortKlineRows(rows []json.RawMessage) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		left, _ := klineOpenTime(rows[i])
+		right, _ := klineOpenTime(rows[j])
+		return left < right
+	})
+}
+
+func klineOpenTime(row json.RawMessage) (int64, error) {
+	var element []json.RawMessage
+	if err := json.Unmarshal(row, &element); err != nil || len(element) == 0 {
+		return 0, fmt.Errorf("invalid kline row %s", string(row))
+	}
+	var openTime int64
+	if err := json.Unmarshal(element[0], &openTime); err != nil {
+		return 0, fmt.Errorf("invalid kline open time %s", string(element[0]))
+	}
+	return openTime, nil
+}
+
+func parseMillis(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %s", value)
+	}
+	return &parsed, nil
+}
+
+func writeFixture(path string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0o644)
+}
+
+func fixtureNotFound(req *http.Request, path string) *http.Response {
+	log.Printf("%sError: no fixture recorded for %s %s\n", config.COLOR_RED, req.URL.String(), config.COLOR_RED)
+	return fixtureResponse(http.StatusNotFound, []byte(fmt.Sprintf(`{"code":-1,"msg":"no fixture recorded at %s"}`, path)))
+}
+
+func fixtureResponse(statusCode int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+	}
+}
@@ -0,0 +1,128 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+// MaxFuturesLimit is the largest number of funding rates or mark price klines binance returns per request
+const MaxFuturesLimit = 1000
+
+// FetchFundingRates returns the funding rates of the perpetual contract settled between startTime and endTime
+func FetchFundingRates(currency string, startTime time.Time, endTime time.Time) ([]*dto.FundingRateDto, error) {
+	query := url.Values{}
+	query.Set("symbol", instrument.Symbol(currency))
+	query.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	query.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+	query.Set("limit", strconv.Itoa(MaxFuturesLimit))
+
+	var fundingRates []dto.BinanceFundingRate
+	if err := fetchFutures("fundingRate", query, &fundingRates); err != nil {
+		return nil, err
+	}
+
+	records := make([]*dto.FundingRateDto, 0, len(fundingRates))
+	for _, fundingRate := range fundingRates {
+		records = append(records, &dto.FundingRateDto{
+			Symbol:      fundingRate.Symbol,
+			Timestamp:   time.UnixMilli(fundingRate.FundingTime).UTC().Truncate(time.Second),
+			FundingRate: utils.ParseDecimal(fundingRate.FundingRate),
+			MarkPrice:   utils.ParseDecimal(fundingRate.MarkPrice),
+		})
+	}
+	return records, nil
+}
+
+// FetchOpenInterest returns the current open interest of the perpetual contract
+func FetchOpenInterest(currency string) (*dto.OpenInterestDto, error) {
+	query := url.Values{}
+	query.Set("symbol", instrument.Symbol(currency))
+
+	var openInterest dto.BinanceOpenInterest
+	if err := fetchFutures("openInterest", query, &openInterest); err != nil {
+		return nil, err
+	}
+
+	return &dto.OpenInterestDto{
+		Symbol:       openInterest.Symbol,
+		Timestamp:    time.UnixMilli(openInterest.Time).UTC(),
+		OpenInterest: utils.ParseDecimal(openInterest.OpenInterest),
+	}, nil
+}
+
+// FetchMarkPriceKlines returns up to limit mark price klines of the perpetual contract opened between startTime and endTime
+func FetchMarkPriceKlines(currency string, interval string, startTime time.Time, endTime time.Time, limit int) ([]*dto.MarkPriceDto, error) {
+	query := url.Values{}
+	query.Set("symbol", instrument.Symbol(currency))
+	query.Set("interval", interval)
+	query.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	query.Set("endTime", strconv.FormatInt(endTime.UnixMilli(), 10))
+	query.Set("limit", strconv.Itoa(limit))
+
+	var klines [][]interface{}
+	if err := fetchFutures("markPriceKlines", query, &klines); err != nil {
+		return nil, err
+	}
+
+	now := clock.Now()
+	records := make([]*dto.MarkPriceDto, 0, len(klines))
+	for _, element := range klines {
+		if len(element) < 7 {
+			return nil, fmt.Errorf("unexpected response format")
+		}
+		openTime, openOk := element[0].(float64)
+		closeTime, closeOk := element[6].(float64)
+		if !openOk || !closeOk {
+			return nil, fmt.Errorf("unexpected response format: open time is %T, close time is %T", element[0], element[6])
+		}
+		prices, err := utils.ParseDecimalFields(element, 1, 2, 3, 4)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &dto.MarkPriceDto{
+			Symbol:     instrument.Symbol(currency),
+			Timestamp:  time.UnixMilli(int64(openTime)).UTC(), // Kline open time
+			Open:       prices[0],
+			High:       prices[1],
+			Low:        prices[2],
+			Close:      prices[3],
+			IsComplete: time.UnixMilli(int64(closeTime)).Before(now), // Kline close time
+		})
+	}
+	return records, nil
+}
+
+func fetchFutures(endpoint string, query url.Values, result any) error {
+	cfg := config.LoadConfig()
+	client := GetFuturesHTTPClient()
+	req, err := http.NewRequest("GET", cfg.BinanceFuturesBaseAPIUrl+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("binance futures API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, result)
+}
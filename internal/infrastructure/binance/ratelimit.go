@@ -0,0 +1,237 @@
+package binance
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+)
+
+// the limiters are shared by every binance client so that all requests spend the same IP weight budget,
+// the spot and the USDⓈ-M futures API count their weight separately
+var limiter *WeightLimiter
+var limiterOnce sync.Once
+var futuresLimiter *WeightLimiter
+var futuresLimiterOnce sync.Once
+
+func getLimiter() *WeightLimiter {
+	limiterOnce.Do(func() {
+		cfg := config.LoadConfig()
+		limiter = newWeightLimiter("binance", cfg.BinanceWeightLimit*cfg.BinanceWeightThreshold/100)
+	})
+	return limiter
+}
+
+func getFuturesLimiter() *WeightLimiter {
+	futuresLimiterOnce.Do(func() {
+		cfg := config.LoadConfig()
+		futuresLimiter = newWeightLimiter("binance_futures", cfg.BinanceFuturesWeightLimit*cfg.BinanceWeightThreshold/100)
+	})
+	return futuresLimiter
+}
+
+// WeightLimiter tracks the request weight used in the current minute and queues requests once the budget is spent.
+// The used weight is taken from the X-MBX-USED-WEIGHT-1M header of every response,
+// a 429 or 418 response blocks all requests until its Retry-After has passed.
+type WeightLimiter struct {
+	mu          sync.Mutex
+	name        string
+	budget      int
+	usedWeight  int
+	window      time.Time
+	bannedUntil time.Time
+
+	usedWeightMetric  *expvar.Int
+	bannedUntilMetric *expvar.String
+}
+
+// newWeightLimiter publishes the used weight and the ban of the limiter as <name>_used_weight_1m and <name>_banned_until
+func newWeightLimiter(name string, budget int) *WeightLimiter {
+	return &WeightLimiter{
+		name:              name,
+		budget:            budget,
+		usedWeightMetric:  expvar.NewInt(name + "_used_weight_1m"),
+		bannedUntilMetric: expvar.NewString(name + "_banned_until"),
+	}
+}
+
+// Wait blocks until the request weight fits into the budget and reserves it
+func (limiter *WeightLimiter) Wait(ctx context.Context, weight int) error {
+	for {
+		delay := limiter.reserve(weight)
+		if delay == 0 {
+			return nil
+		}
+		log.Printf(config.COLOR_YELLOW+"%s weight budget spent, request queued for %s"+config.COLOR_RESET, limiter.name, delay)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (limiter *WeightLimiter) reserve(weight int) time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(limiter.bannedUntil) {
+		return limiter.bannedUntil.Sub(now)
+	}
+
+	limiter.resetWindow(now)
+	if limiter.usedWeight > 0 && limiter.usedWeight+weight > limiter.budget {
+		return limiter.window.Add(time.Minute).Sub(now)
+	}
+
+	limiter.usedWeight += weight
+	limiter.usedWeightMetric.Set(int64(limiter.usedWeight))
+	return 0
+}
+
+// Update syncs the limiter with the weight binance reports and the ban it announces
+func (limiter *WeightLimiter) Update(resp *http.Response) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+	limiter.resetWindow(now)
+	if usedWeight, err := strconv.Atoi(resp.Header.Get("X-MBX-USED-WEIGHT-1M")); err == nil {
+		limiter.usedWeight = usedWeight
+		limiter.usedWeightMetric.Set(int64(usedWeight))
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		if err != nil {
+			retryAfter = 60
+		}
+		limiter.bannedUntil = now.Add(time.Duration(retryAfter) * time.Second)
+		limiter.bannedUntilMetric.Set(limiter.bannedUntil.Format(time.RFC3339))
+		log.Printf(config.COLOR_RED+"%s returned %d, requests are paused until %s"+config.COLOR_RESET, limiter.name, resp.StatusCode, limiter.bannedUntil)
+	}
+}
+
+// UsedWeight returns the weight used in the current minute
+func (limiter *WeightLimiter) UsedWeight() int {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.resetWindow(time.Now())
+	return limiter.usedWeight
+}
+
+// binance resets the used weight every minute
+func (limiter *WeightLimiter) resetWindow(now time.Time) {
+	window := now.Truncate(time.Minute)
+	if window.After(limiter.window) {
+		limiter.window = window
+		limiter.usedWeight = 0
+	}
+}
+
+// RateLimitTransport makes every binance request wait for the shared weight budget and syncs the budget with every response.
+// The resilience transport calls Wait before each attempt so that the queueing is not cut off by the attempt timeout.
+type RateLimitTransport struct {
+	Limiter       *WeightLimiter
+	RealTransport http.RoundTripper
+}
+
+// Wait blocks until the weight of the request fits into the budget
+func (transport *RateLimitTransport) Wait(req *http.Request) error {
+	return transport.Limiter.Wait(req.Context(), requestWeight(req))
+}
+
+func (transport *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := transport.RealTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	transport.Limiter.Update(resp)
+	return resp, nil
+}
+
+// requestWeight returns the weight of a binance endpoint, see https://developers.binance.com/docs/binance-spot-api-docs/rest-api
+func requestWeight(req *http.Request) int {
+	path := req.URL.Path
+	query := req.URL.Query()
+	if strings.Contains(path, "/fapi/") {
+		return futuresRequestWeight(path, query)
+	}
+	switch {
+	case strings.HasSuffix(path, "/klines"), strings.HasSuffix(path, "/uiKlines"):
+		return 2
+	case strings.HasSuffix(path, "/ticker/24hr"):
+		if query.Has("symbol") {
+			return 2
+		}
+		return 80
+	case strings.HasSuffix(path, "/exchangeInfo"):
+		return 20
+	case strings.HasSuffix(path, "/aggTrades"):
+		return 4
+	case strings.HasSuffix(path, "/depth"):
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		switch {
+		case limit > 1000:
+			return 250
+		case limit > 500:
+			return 50
+		case limit > 100:
+			return 25
+		default:
+			return 5
+		}
+	}
+	return 1
+}
+
+// futuresRequestWeight returns the weight of a USDⓈ-M futures endpoint, see https://developers.binance.com/docs/derivatives/usds-margined-futures
+func futuresRequestWeight(path string, query url.Values) int {
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	switch {
+	case strings.HasSuffix(path, "/klines"), strings.HasSuffix(path, "/markPriceKlines"):
+		switch {
+		case limit > 1000:
+			return 10
+		case limit >= 500:
+			return 5
+		case limit >= 100:
+			return 2
+		default:
+			return 1
+		}
+	case strings.HasSuffix(path, "/depth"):
+		switch {
+		case limit > 500:
+			return 20
+		case limit > 100:
+			return 10
+		case limit > 50:
+			return 5
+		default:
+			return 2
+		}
+	case strings.HasSuffix(path, "/exchangeInfo"):
+		return 1
+	}
+	return 1
+}
+
+// GetUsedWeight returns the binance request weight used in the current minute
+func GetUsedWeight() int {
+	return getLimiter().UsedWeight()
+}
+
+// GetFuturesUsedWeight returns the binance futures request weight used in the current minute
+func GetFuturesUsedWeight() int {
+	return getFuturesLimiter().UsedWeight()
+}
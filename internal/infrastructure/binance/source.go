@@ -0,0 +1,41 @@
+package binance
+
+import (
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+)
+
+// BinanceSource exposes the binance REST API as a market data source
+type BinanceSource struct {
+}
+
+func NewBinanceSource() *BinanceSource {
+	return &BinanceSource{}
+}
+
+func (source *BinanceSource) Name() string {
+	return config.EXCHANGE_BINANCE
+}
+
+func (source *BinanceSource) Symbol(currency string) string {
+	return instrument.Symbol(currency)
+}
+
+func (source *BinanceSource) MaxKlineLimit() int {
+	return MaxKlineLimit
+}
+
+func (source *BinanceSource) FetchKline(currency string, interval string, limit int) ([]*dto.DataDto, error) {
+	return FetchKline(currency, interval, limit)
+}
+
+func (source *BinanceSource) FetchKlineRange(currency string, interval string, startTime time.Time, endTime time.Time, limit int) ([]*dto.DataDto, error) {
+	return FetchKlineRange(currency, interval, startTime, endTime, limit)
+}
+
+func (source *BinanceSource) FetchTicker(currency string) (*dto.DataDto, error) {
+	return FetchTicker(currency)
+}
@@ -0,0 +1,172 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	minReconnectDelay = 1 * time.Second
+	maxReconnectDelay = 1 * time.Minute
+	streamReadTimeout = 5 * time.Minute
+)
+
+// KlineHandler receives every kline update of a tracked currency
+type KlineHandler func(currency string, record *dto.DataDto)
+
+// KlineStream consumes <symbol>@kline_<interval> streams from the Binance WebSocket API
+type KlineStream struct {
+	url        string
+	interval   string
+	currencies map[string]string // symbol => currency
+	handler    KlineHandler
+}
+
+type streamRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	Id     int      `json:"id"`
+}
+
+func NewKlineStream(currencies []string, interval string, handler KlineHandler) *KlineStream {
+	cfg := config.LoadConfig()
+	symbols := make(map[string]string, len(currencies))
+	for _, currency := range currencies {
+		symbols[instrument.Symbol(currency)] = currency
+	}
+	return &KlineStream{
+		url:        cfg.BinanceStreamUrl,
+		interval:   interval,
+		currencies: symbols,
+		handler:    handler,
+	}
+}
+
+// Run keeps the stream connected until the context is cancelled, reconnecting and resubscribing when the socket drops
+func (stream *KlineStream) Run(ctx context.Context) {
+	delay := minReconnectDelay
+	for {
+		connected, err := stream.consume(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = minReconnectDelay
+		}
+		log.Printf(config.COLOR_YELLOW+"kline stream disconnected: %v, reconnecting in %s"+config.COLOR_RESET, err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+func (stream *KlineStream) consume(ctx context.Context) (bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, stream.url, nil)
+	if err != nil {
+		return false, fmt.Errorf("error connecting to %s: %w", stream.url, err)
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := stream.subscribe(conn); err != nil {
+		return false, err
+	}
+	log.Printf("kline stream subscribed to %s", strings.Join(stream.streamNames(), ", "))
+
+	conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+	conn.SetPingHandler(func(appData string) error {
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(appData), time.Now().Add(10*time.Second))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+		conn.SetReadDeadline(time.Now().Add(streamReadTimeout))
+		stream.handleMessage(message)
+	}
+}
+
+func (stream *KlineStream) subscribe(conn *websocket.Conn) error {
+	request := streamRequest{
+		Method: "SUBSCRIBE",
+		Params: stream.streamNames(),
+		Id:     1,
+	}
+	if err := conn.WriteJSON(request); err != nil {
+		return fmt.Errorf("error subscribing to kline stream: %w", err)
+	}
+	return nil
+}
+
+func (stream *KlineStream) streamNames() []string {
+	names := make([]string, 0, len(stream.currencies))
+	for symbol := range stream.currencies {
+		names = append(names, strings.ToLower(symbol)+"@kline_"+stream.interval)
+	}
+	return names
+}
+
+func (stream *KlineStream) handleMessage(message []byte) {
+	var event dto.BinanceKlineEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		log.Printf("error decoding kline event: %v", err)
+		return
+	}
+	// subscription acknowledgements and other non kline payloads
+	if event.EventType != "kline" {
+		return
+	}
+
+	currency, ok := stream.currencies[event.Symbol]
+	if !ok {
+		return
+	}
+	stream.handler(currency, mapStreamKlineToDto(event.Symbol, event.Kline))
+}
+
+func mapStreamKlineToDto(symbol string, kline dto.BinanceStreamKline) *dto.DataDto {
+	record := &dto.DataDto{
+		Symbol:     symbol,
+		Timestamp:  time.UnixMilli(kline.OpenTime).UTC(), // Kline open time
+		Timeframe:  kline.Interval,
+		Open:       utils.ParseDecimal(kline.Open),
+		High:       utils.ParseDecimal(kline.High),
+		Low:        utils.ParseDecimal(kline.Low),
+		Close:      utils.ParseDecimal(kline.Close),
+		Volume:     utils.ParseDecimal(kline.Volume),
+		IsComplete: kline.IsClosed,
+
+		QuoteVolume:         utils.ParseDecimal(kline.QuoteVolume),
+		TradeCount:          kline.TradeCount,
+		TakerBuyBaseVolume:  utils.ParseDecimal(kline.TakerBuyBaseVolume),
+		TakerBuyQuoteVolume: utils.ParseDecimal(kline.TakerBuyQuoteVolume),
+	}
+	record.Vwap = vwap(record)
+	return record
+}
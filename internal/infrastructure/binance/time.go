@@ -0,0 +1,16 @@
+package binance
+
+import (
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+)
+
+// FetchServerTime returns the clock of the binance server, it costs 1 request weight
+func FetchServerTime() (time.Time, error) {
+	var serverTime dto.BinanceServerTime
+	if err := fetchSpot("time", &serverTime); err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(serverTime.ServerTime).UTC(), nil
+}
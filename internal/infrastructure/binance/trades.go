@@ -0,0 +1,108 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+// MaxAggTradeLimit is the largest number of aggregate trades binance returns per request
+const MaxAggTradeLimit = 1000
+
+// FetchAggTrades returns every aggregate trade executed in [startTime, endTime), ordered by id.
+// Binance accepts time windows below one hour, longer ranges are requested hour by hour.
+func FetchAggTrades(currency string, startTime time.Time, endTime time.Time) ([]dto.AggTradeDto, error) {
+	var trades []dto.AggTradeDto
+	for windowStart := startTime; windowStart.Before(endTime); windowStart = windowStart.Add(time.Hour) {
+		windowEnd := windowStart.Add(time.Hour)
+		if windowEnd.After(endTime) {
+			windowEnd = endTime
+		}
+		windowTrades, err := fetchAggTradesWindow(currency, windowStart, windowEnd)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, windowTrades...)
+	}
+	return trades, nil
+}
+
+// fetchAggTradesWindow pages by trade id once a window holds more than MaxAggTradeLimit trades
+func fetchAggTradesWindow(currency string, startTime time.Time, endTime time.Time) ([]dto.AggTradeDto, error) {
+	query := url.Values{}
+	query.Set("symbol", instrument.Symbol(currency))
+	query.Set("limit", strconv.Itoa(MaxAggTradeLimit))
+	query.Set("startTime", strconv.FormatInt(startTime.UnixMilli(), 10))
+	query.Set("endTime", strconv.FormatInt(endTime.UnixMilli()-1, 10))
+
+	var trades []dto.AggTradeDto
+	for {
+		page, err := fetchAggTrades(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, trade := range page {
+			if !trade.Timestamp.Before(endTime) {
+				return trades, nil
+			}
+			trades = append(trades, trade)
+		}
+		if len(page) < MaxAggTradeLimit {
+			return trades, nil
+		}
+
+		query.Del("startTime")
+		query.Del("endTime")
+		query.Set("fromId", strconv.FormatInt(page[len(page)-1].Id+1, 10))
+	}
+}
+
+func fetchAggTrades(query url.Values) ([]dto.AggTradeDto, error) {
+	cfg := config.LoadConfig()
+	client := GetHTTPClient()
+	req, err := http.NewRequest("GET", cfg.BinanceBaseAPIUrl+"aggTrades?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("binance API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var binanceTrades []dto.BinanceAggTrade
+	if err := json.Unmarshal(body, &binanceTrades); err != nil {
+		return nil, err
+	}
+
+	trades := make([]dto.AggTradeDto, 0, len(binanceTrades))
+	for _, trade := range binanceTrades {
+		trades = append(trades, dto.AggTradeDto{
+			Id:           trade.Id,
+			Price:        utils.ParseDecimal(trade.Price),
+			Quantity:     utils.ParseDecimal(trade.Quantity),
+			TradeCount:   trade.LastTradeId - trade.FirstTradeId + 1,
+			Timestamp:    time.UnixMilli(trade.Time).UTC(),
+			IsBuyerMaker: trade.IsBuyerMaker,
+		})
+	}
+	return trades, nil
+}
@@ -0,0 +1,146 @@
+package binance
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+// the kline dumps of data.binance.vision are named <SYMBOL>-<interval>-<YYYY-MM>.zip (monthly) or
+// <SYMBOL>-<interval>-<YYYY-MM-DD>.zip (daily), each comes with a <name>.zip.CHECKSUM file holding its sha256
+
+// ParseKlineArchiveName returns the symbol and interval of a kline dump file name, e.g. BTCUSDT-1h-2024-01.zip
+func ParseKlineArchiveName(name string) (symbol string, interval string, ok bool) {
+	parts := strings.Split(strings.TrimSuffix(filepath.Base(name), ".zip"), "-")
+	if !strings.HasSuffix(name, ".zip") || len(parts) < 4 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// VerifyKlineArchive compares the sha256 of a dump with its CHECKSUM file
+func VerifyKlineArchive(path string) error {
+	content, err := os.ReadFile(path + ".CHECKSUM")
+	if err != nil {
+		return fmt.Errorf("checksum file of %s is missing: %v", filepath.Base(path), err)
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return fmt.Errorf("checksum file of %s is empty", filepath.Base(path))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(actual, fields[0]) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", filepath.Base(path), fields[0], actual)
+	}
+	return nil
+}
+
+// ReadKlineArchive maps the CSV rows of a dump to records, a header line is skipped when present
+func ReadKlineArchive(path string, currency string, interval string) ([]*dto.DataDto, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open %s: %v", filepath.Base(path), err)
+	}
+	defer archive.Close()
+
+	var records []*dto.DataDto
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".csv") {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		rows, err := csv.NewReader(content).ReadAll()
+		content.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %v", file.Name, err)
+		}
+
+		for i, row := range rows {
+			if i == 0 && len(row) > 0 && row[0] == "open_time" {
+				continue
+			}
+			record, err := mapArchiveRowToDto(currency, interval, row)
+			if err != nil {
+				return nil, fmt.Errorf("%s line %d: %v", file.Name, i+1, err)
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// mapArchiveRowToDto maps a row laid out like the REST klines: open time, open, high, low, close, volume, close time,
+// quote volume, trade count, taker buy base volume, taker buy quote volume, ignore
+func mapArchiveRowToDto(currency string, interval string, row []string) (*dto.DataDto, error) {
+	if len(row) < 11 {
+		return nil, fmt.Errorf("unexpected number of columns: %d", len(row))
+	}
+	openTime, err := parseArchiveTime(row[0])
+	if err != nil {
+		return nil, err
+	}
+	closeTime, err := parseArchiveTime(row[6])
+	if err != nil {
+		return nil, err
+	}
+	tradeCount, err := strconv.ParseInt(row[8], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trade count %q", row[8])
+	}
+
+	record := &dto.DataDto{
+		Symbol:     instrument.Symbol(currency),
+		Timestamp:  openTime,
+		Timeframe:  interval,
+		Open:       utils.ParseDecimal(row[1]),
+		High:       utils.ParseDecimal(row[2]),
+		Low:        utils.ParseDecimal(row[3]),
+		Close:      utils.ParseDecimal(row[4]),
+		Volume:     utils.ParseDecimal(row[5]),
+		IsComplete: closeTime.Before(clock.Now()),
+
+		QuoteVolume:         utils.ParseDecimal(row[7]),
+		TradeCount:          tradeCount,
+		TakerBuyBaseVolume:  utils.ParseDecimal(row[9]),
+		TakerBuyQuoteVolume: utils.ParseDecimal(row[10]),
+	}
+	record.Vwap = vwap(record)
+	return record, nil
+}
+
+// parseArchiveTime reads the epoch timestamps of the dumps, spot dumps switched from milliseconds to microseconds in 2025
+func parseArchiveTime(value string) (time.Time, error) {
+	epoch, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+	}
+	if epoch > 1e14 {
+		return time.UnixMicro(epoch).UTC(), nil
+	}
+	return time.UnixMilli(epoch).UTC(), nil
+}
@@ -0,0 +1,180 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/resilience"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+// coinbase returns at most 300 candles per request
+const maxKlineLimit = 300
+
+// coinbase only supports fixed granularities (in seconds), there is no 4h candle
+var granularityByTimeframe = map[string]int{
+	"1h": 3600,
+	"1d": 86400,
+}
+
+type CoinbaseSource struct {
+	baseUrl string
+	client  *http.Client
+}
+
+type statsResponse struct {
+	Open   string `json:"open"`
+	High   string `json:"high"`
+	Low    string `json:"low"`
+	Last   string `json:"last"`
+	Volume string `json:"volume"`
+}
+
+func NewCoinbaseSource() *CoinbaseSource {
+	cfg := config.LoadConfig()
+	return &CoinbaseSource{
+		baseUrl: cfg.CoinbaseBaseAPIUrl,
+		client:  &http.Client{Transport: resilience.NewTransport(config.EXCHANGE_COINBASE, http.DefaultTransport)},
+	}
+}
+
+func (source *CoinbaseSource) Name() string {
+	return config.EXCHANGE_COINBASE
+}
+
+func (source *CoinbaseSource) Symbol(currency string) string {
+	return instrument.FromKey(currency).SymbolWithSeparator("-")
+}
+
+func (source *CoinbaseSource) MaxKlineLimit() int {
+	return maxKlineLimit
+}
+
+// FetchKline retrieves the latest klines, the last one may still be forming
+func (source *CoinbaseSource) FetchKline(currency string, interval string, limit int) ([]*dto.DataDto, error) {
+	granularity, ok := granularityByTimeframe[interval]
+	if !ok {
+		return nil, fmt.Errorf("coinbase does not support interval: %s", interval)
+	}
+	limit = min(limit, maxKlineLimit)
+	duration := time.Duration(granularity) * time.Second
+	endTime := time.Now().Truncate(duration)
+	startTime := endTime.Add(-1 * time.Duration(limit-1) * duration)
+
+	records, err := source.FetchKlineRange(currency, interval, startTime, endTime, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("unexpected response format")
+	}
+	return records, nil
+}
+
+func (source *CoinbaseSource) FetchKlineRange(currency string, interval string, startTime time.Time, endTime time.Time, limit int) ([]*dto.DataDto, error) {
+	granularity, ok := granularityByTimeframe[interval]
+	if !ok {
+		return nil, fmt.Errorf("coinbase does not support interval: %s", interval)
+	}
+	limit = min(limit, maxKlineLimit)
+	duration := time.Duration(granularity) * time.Second
+	// coinbase rejects ranges with more candles than the limit
+	if lastAllowed := startTime.Add(time.Duration(limit-1) * duration); endTime.After(lastAllowed) {
+		endTime = lastAllowed
+	}
+
+	query := url.Values{}
+	query.Set("granularity", strconv.Itoa(granularity))
+	query.Set("start", startTime.UTC().Format(time.RFC3339))
+	query.Set("end", endTime.UTC().Format(time.RFC3339))
+
+	// numbers are decoded as json.Number so that prices keep their exact decimal representation
+	var candles [][]json.Number
+	if err := source.get("products/"+source.Symbol(currency)+"/candles", query, &candles); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	records := make([]*dto.DataDto, 0, len(candles))
+	for _, candle := range candles {
+		// [ time, low, high, open, close, volume ]
+		if len(candle) < 6 {
+			return nil, fmt.Errorf("unexpected response format")
+		}
+		seconds, err := candle[0].Int64()
+		if err != nil {
+			return nil, fmt.Errorf("unexpected response format")
+		}
+		timestamp := time.Unix(seconds, 0).UTC()
+		if timestamp.Before(startTime) || timestamp.After(endTime) {
+			continue
+		}
+		records = append(records, &dto.DataDto{
+			Symbol:     source.Symbol(currency),
+			Timestamp:  timestamp,
+			Timeframe:  interval,
+			Low:        utils.ParseDecimal(candle[1].String()),
+			High:       utils.ParseDecimal(candle[2].String()),
+			Open:       utils.ParseDecimal(candle[3].String()),
+			Close:      utils.ParseDecimal(candle[4].String()),
+			Volume:     utils.ParseDecimal(candle[5].String()),
+			IsComplete: !timestamp.Add(duration).After(now),
+		})
+	}
+
+	// coinbase returns the newest candle first
+	slices.SortFunc(records, func(a, b *dto.DataDto) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return records, nil
+}
+
+// FetchTicker retrieves the 24h stats of the product
+func (source *CoinbaseSource) FetchTicker(currency string) (*dto.DataDto, error) {
+	var stats statsResponse
+	if err := source.get("products/"+source.Symbol(currency)+"/stats", nil, &stats); err != nil {
+		return nil, err
+	}
+
+	return &dto.DataDto{
+		Symbol:    source.Symbol(currency),
+		Timestamp: time.Now().UTC(),
+		Timeframe: config.ONE_HOUR,
+		Open:      utils.ParseDecimal(stats.Open),
+		High:      utils.ParseDecimal(stats.High),
+		Low:       utils.ParseDecimal(stats.Low),
+		Close:     utils.ParseDecimal(stats.Last),
+		Volume:    utils.ParseDecimal(stats.Volume),
+	}, nil
+}
+
+func (source *CoinbaseSource) get(path string, query url.Values, target any) error {
+	requestUrl := source.baseUrl + path
+	if len(query) > 0 {
+		requestUrl += "?" + query.Encode()
+	}
+	resp, err := source.client.Get(requestUrl)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("coinbase API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, target)
+}
@@ -10,11 +10,23 @@ import (
 
 var DB *sql.DB
 
+// UpsertBatchSize bounds the rows of a multi-row INSERT, postgres accepts at most 65535 bind parameters per statement
+const UpsertBatchSize = 1000
+
+// Batches splits records into consecutive slices of at most size elements
+func Batches[T any](records []T, size int) [][]T {
+	var batches [][]T
+	for start := 0; start < len(records); start += size {
+		batches = append(batches, records[start:min(start+size, len(records))])
+	}
+	return batches
+}
+
 func ConnectDB() error {
 	cfg := config.LoadConfig()
 
 	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable timezone=UTC",
 		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName,
 	)
 	db, err := sql.Open("postgres", dsn)
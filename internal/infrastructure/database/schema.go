@@ -0,0 +1,150 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+)
+
+// the migrations create the tables of the default currencies, the tables of other instruments are
+// provisioned at startup with the same layout. Every statement is idempotent.
+const dataTableSchema = `
+CREATE TABLE IF NOT EXISTS data_{key} (
+    id BIGSERIAL,
+    symbol TEXT NOT NULL,
+    timeframe TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    open NUMERIC NOT NULL,
+    high NUMERIC NOT NULL,
+    low NUMERIC NOT NULL,
+    close NUMERIC NOT NULL,
+    volume NUMERIC NOT NULL,
+    trend NUMERIC,
+    is_complete BOOLEAN DEFAULT FALSE,
+    quote_volume NUMERIC NOT NULL DEFAULT 0,
+    trade_count BIGINT NOT NULL DEFAULT 0,
+    taker_buy_base_volume NUMERIC NOT NULL DEFAULT 0,
+    taker_buy_quote_volume NUMERIC NOT NULL DEFAULT 0,
+    vwap NUMERIC NOT NULL DEFAULT 0,
+    median_price NUMERIC NOT NULL DEFAULT 0,
+    PRIMARY KEY (id, timeframe)
+) PARTITION BY LIST (timeframe);`
+
+const indicatorTableSchema = `
+CREATE TABLE IF NOT EXISTS indicator_{key} (
+    id BIGSERIAL,
+    timeframe TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    sma NUMERIC NOT NULL,
+    ema NUMERIC NOT NULL,
+    std_dev NUMERIC NOT NULL,
+    lower_bollinger NUMERIC NOT NULL,
+    upper_bollinger NUMERIC NOT NULL,
+    volatility NUMERIC NOT NULL,
+    rsi NUMERIC NOT NULL,
+    macd NUMERIC NOT NULL,
+    macd_signal NUMERIC NOT NULL,
+    data_timestamp TIMESTAMPTZ NOT NULL,
+    tr NUMERIC NULL,
+    PRIMARY KEY (id, timeframe)
+) PARTITION BY LIST (timeframe);`
+
+const partitionSchema = `
+CREATE TABLE IF NOT EXISTS data_{key}_{timeframe} PARTITION OF data_{key} FOR VALUES IN ('{timeframe}');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_{key}_{timeframe}_timestamp ON data_{key}_{timeframe}(timestamp);
+CREATE TABLE IF NOT EXISTS indicator_{key}_{timeframe} PARTITION OF indicator_{key} FOR VALUES IN ('{timeframe}');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_indicator_{key}_{timeframe}_timestamp ON indicator_{key}_{timeframe}(timestamp);
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_indicator_{key}_{timeframe}_data_timestamp') THEN
+        ALTER TABLE indicator_{key}_{timeframe}
+        ADD CONSTRAINT fk_indicator_{key}_{timeframe}_data_timestamp
+        FOREIGN KEY (data_timestamp) REFERENCES data_{key}_{timeframe} (timestamp) ON DELETE CASCADE;
+    END IF;
+END $$;`
+
+const depthTableSchema = `
+CREATE TABLE IF NOT EXISTS depth_{key} (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    best_bid NUMERIC NOT NULL,
+    best_ask NUMERIC NOT NULL,
+    mid_price NUMERIC NOT NULL,
+    spread NUMERIC NOT NULL,
+    bid_depth_05 NUMERIC NOT NULL,
+    ask_depth_05 NUMERIC NOT NULL,
+    bid_depth_1 NUMERIC NOT NULL,
+    ask_depth_1 NUMERIC NOT NULL,
+    bid_depth_2 NUMERIC NOT NULL,
+    ask_depth_2 NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);`
+
+const futuresTableSchema = `
+CREATE TABLE IF NOT EXISTS funding_rate_{key} (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    funding_rate NUMERIC NOT NULL,
+    mark_price NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+CREATE TABLE IF NOT EXISTS open_interest_{key} (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    open_interest NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+CREATE TABLE IF NOT EXISTS mark_price_{key} (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    open NUMERIC NOT NULL,
+    high NUMERIC NOT NULL,
+    low NUMERIC NOT NULL,
+    close NUMERIC NOT NULL,
+    is_complete BOOLEAN DEFAULT FALSE,
+    UNIQUE (timestamp)
+);`
+
+const tickerTableSchema = `
+CREATE TABLE IF NOT EXISTS ticker_{key} (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    last_price NUMERIC NOT NULL,
+    open_price NUMERIC NOT NULL,
+    high_price NUMERIC NOT NULL,
+    low_price NUMERIC NOT NULL,
+    price_change NUMERIC NOT NULL,
+    price_change_percent NUMERIC NOT NULL,
+    weighted_avg_price NUMERIC NOT NULL,
+    volume NUMERIC NOT NULL,
+    quote_volume NUMERIC NOT NULL,
+    trade_count BIGINT NOT NULL,
+    UNIQUE (timestamp)
+);`
+
+// EnsureInstrumentTables creates the data, indicator, depth, futures and ticker tables of an instrument key when they do not exist yet
+func EnsureInstrumentTables(key string) error {
+	statements := []string{dataTableSchema, indicatorTableSchema}
+	for _, timeframe := range slices.Concat(config.DefaultTimeframes, config.CompositeTimeframes) {
+		statements = append(statements, strings.ReplaceAll(partitionSchema, "{timeframe}", timeframe))
+	}
+	statements = append(statements, depthTableSchema, futuresTableSchema, tickerTableSchema)
+
+	for _, statement := range statements {
+		_, err := DB.Exec(strings.ReplaceAll(statement, "{key}", key))
+		if err != nil {
+			return fmt.Errorf("💾 error provisioning tables of %s: %w", key, err)
+		}
+	}
+	if config.LoadConfig().StorageBackend == config.STORAGE_BACKEND_TIMESCALE {
+		return EnsureTimescaleTables(key)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+)
+
+// with STORAGE_BACKEND=timescale the 1h candles are stored in the hypertable ts_data_<key>_1h and the grouped
+// timeframes are continuous aggregates of it, e.g. ts_data_<key>_4h. Indicators are stored in ts_indicator_<key>_<timeframe>.
+const timescaleExtensionSchema = `CREATE EXTENSION IF NOT EXISTS timescaledb;`
+
+const timescaleHypertableSchema = `
+CREATE TABLE IF NOT EXISTS ts_data_{key}_{timeframe} (
+    id BIGSERIAL,
+    symbol TEXT NOT NULL,
+    timeframe TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    open NUMERIC NOT NULL,
+    high NUMERIC NOT NULL,
+    low NUMERIC NOT NULL,
+    close NUMERIC NOT NULL,
+    volume NUMERIC NOT NULL,
+    trend NUMERIC NOT NULL DEFAULT 0,
+    is_complete BOOLEAN NOT NULL DEFAULT FALSE,
+    quote_volume NUMERIC NOT NULL DEFAULT 0,
+    trade_count BIGINT NOT NULL DEFAULT 0,
+    taker_buy_base_volume NUMERIC NOT NULL DEFAULT 0,
+    taker_buy_quote_volume NUMERIC NOT NULL DEFAULT 0,
+    vwap NUMERIC NOT NULL DEFAULT 0,
+    median_price NUMERIC NOT NULL DEFAULT 0,
+    UNIQUE (timestamp)
+);
+SELECT create_hypertable('ts_data_{key}_{timeframe}', 'timestamp', chunk_time_interval => INTERVAL '{chunk_days} days', if_not_exists => TRUE);`
+
+const timescaleCompressionSchema = `
+ALTER TABLE ts_data_{key}_{timeframe} SET (timescaledb.compress, timescaledb.compress_orderby = 'timestamp DESC');
+SELECT add_compression_policy('ts_data_{key}_{timeframe}', INTERVAL '{compress_days} days', if_not_exists => TRUE);`
+
+// a group holds the 1h candles in (group - hours, group] and is labeled with its last hour like the groups of
+// the aggregator, the buckets are shifted by one hour for that. Rows not materialized yet are aggregated at query time.
+const timescaleContinuousAggregateSchema = `
+CREATE MATERIALIZED VIEW IF NOT EXISTS ts_data_{key}_{group} WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT time_bucket(INTERVAL '{hours} hours', timestamp, "offset" => INTERVAL '1 hour') AS bucket,
+    last(symbol, timestamp) AS symbol,
+    first(open, timestamp) AS open,
+    max(high) AS high,
+    min(low) AS low,
+    last(close, timestamp) AS close,
+    sum(volume) AS volume,
+    sum(quote_volume) AS quote_volume,
+    sum(trade_count) AS trade_count,
+    sum(taker_buy_base_volume) AS taker_buy_base_volume,
+    sum(taker_buy_quote_volume) AS taker_buy_quote_volume,
+    last(median_price, timestamp) AS median_price,
+    max(timestamp) AS last_timestamp
+FROM ts_data_{key}_{timeframe}
+GROUP BY bucket
+WITH NO DATA;
+SELECT add_continuous_aggregate_policy('ts_data_{key}_{group}',
+    start_offset => INTERVAL '{refresh_hours} hours', end_offset => INTERVAL '1 hour', schedule_interval => INTERVAL '1 hour', if_not_exists => TRUE);`
+
+const timescaleIndicatorSchema = `
+CREATE TABLE IF NOT EXISTS ts_indicator_{key}_{timeframe} (
+    id BIGSERIAL,
+    timeframe TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    sma NUMERIC NOT NULL,
+    ema NUMERIC NOT NULL,
+    std_dev NUMERIC NOT NULL,
+    lower_bollinger NUMERIC NOT NULL,
+    upper_bollinger NUMERIC NOT NULL,
+    volatility NUMERIC NOT NULL,
+    rsi NUMERIC NOT NULL,
+    macd NUMERIC NOT NULL,
+    macd_signal NUMERIC NOT NULL,
+    data_timestamp TIMESTAMPTZ NOT NULL,
+    tr NUMERIC NULL,
+    UNIQUE (timestamp)
+);`
+
+// EnsureTimescaleTables creates the hypertables, continuous aggregates, compression and refresh policies and
+// the indicator tables of an instrument key when they do not exist yet
+func EnsureTimescaleTables(key string) error {
+	cfg := config.LoadConfig()
+	if err := execStatements(key, timescaleExtensionSchema); err != nil {
+		return err
+	}
+
+	for base, groups := range timescaleGroups() {
+		replacer := strings.NewReplacer("{key}", key, "{timeframe}", base,
+			"{chunk_days}", fmt.Sprint(cfg.TimescaleChunkDays), "{compress_days}", fmt.Sprint(cfg.TimescaleCompressAfterDays))
+		if err := execStatements(key, replacer.Replace(timescaleHypertableSchema), replacer.Replace(timescaleIndicatorSchema)); err != nil {
+			return err
+		}
+
+		// the compression settings cannot be altered again once chunks are compressed
+		compressed, err := isCompressionEnabled("ts_data_" + key + "_" + base)
+		if err != nil {
+			return err
+		}
+		if !compressed {
+			if err := execStatements(key, replacer.Replace(timescaleCompressionSchema)); err != nil {
+				return err
+			}
+		}
+
+		for _, group := range groups {
+			hours := config.HoursByTimeframe[group]
+			aggregate := strings.NewReplacer("{group}", group, "{hours}", fmt.Sprint(hours), "{refresh_hours}", fmt.Sprint(max(hours*2, 7*24)))
+			indicator := strings.NewReplacer("{key}", key, "{timeframe}", group)
+			if err := execStatements(key, aggregate.Replace(replacer.Replace(timescaleContinuousAggregateSchema)), indicator.Replace(timescaleIndicatorSchema)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IsGroupedTimeframe reports whether the timescale candles of the timeframe are a continuous aggregate
+func IsGroupedTimeframe(timeframe string) bool {
+	for _, groups := range timescaleGroups() {
+		for _, group := range groups {
+			if group == timeframe {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TimescaleCandleSource returns a FROM item with the columns of the data tables, the candles of a grouped
+// timeframe are read from its continuous aggregate with the trend, vwap and is_complete of the aggregator
+func TimescaleCandleSource(key string, timeframe string) string {
+	if !IsGroupedTimeframe(timeframe) {
+		return fmt.Sprintf("ts_data_%s_%s", key, timeframe)
+	}
+	hours := config.HoursByTimeframe[timeframe]
+	return fmt.Sprintf(`(SELECT NULL::BIGINT AS id, symbol, '%[3]s'::TEXT AS timeframe, bucket + INTERVAL '%[4]d hours' AS timestamp,
+		open, high, low, close, volume,
+		COALESCE(ROUND((close - open) / NULLIF(open, 0), 4), 0) AS trend,
+		last_timestamp = bucket + INTERVAL '%[4]d hours' AS is_complete,
+		quote_volume, trade_count, taker_buy_base_volume, taker_buy_quote_volume,
+		CASE WHEN volume > 0 THEN quote_volume / volume ELSE 0 END AS vwap, median_price
+		FROM ts_data_%[1]s_%[2]s) AS ts_data_%[1]s_%[2]s`, key, timeframe, timeframe, hours-1)
+}
+
+// RefreshTimescaleGroups materializes the buckets of a grouped timeframe between from and to, nil means unbounded
+func RefreshTimescaleGroups(key string, timeframe string, from *time.Time, to *time.Time) error {
+	statement := fmt.Sprintf("CALL refresh_continuous_aggregate('ts_data_%s_%s', %s, %s)", key, timeframe, timestampLiteral(from), timestampLiteral(to))
+	if _, err := DB.Exec(statement); err != nil {
+		return fmt.Errorf("💾 error refreshing ts_data_%s_%s: %v", key, timeframe, err)
+	}
+	return nil
+}
+
+// CandleTable returns the table the 1h candles of the storage backend are read from by the other repositories
+func CandleTable(key string, timeframe string) string {
+	if config.LoadConfig().StorageBackend == config.STORAGE_BACKEND_TIMESCALE {
+		return TimescaleCandleSource(key, timeframe)
+	}
+	return fmt.Sprintf("data_%s_%s", key, timeframe)
+}
+
+// IndicatorTable returns the indicator table of the storage backend
+func IndicatorTable(key string, timeframe string) string {
+	if config.LoadConfig().StorageBackend == config.STORAGE_BACKEND_TIMESCALE {
+		return fmt.Sprintf("ts_indicator_%s_%s", key, timeframe)
+	}
+	return fmt.Sprintf("indicator_%s_%s", key, timeframe)
+}
+
+// timescaleGroups returns the grouped timeframes by the 1h timeframe they are aggregated from
+func timescaleGroups() map[string][]string {
+	groups := make(map[string][]string)
+	for _, timeframe := range config.DefaultTimeframes {
+		if timeframe != config.ONE_HOUR {
+			groups[config.ONE_HOUR] = append(groups[config.ONE_HOUR], timeframe)
+		}
+	}
+	composite := config.ONE_HOUR + config.COMPOSITE_SUFFIX
+	for _, timeframe := range config.CompositeTimeframes {
+		if timeframe != composite {
+			groups[composite] = append(groups[composite], timeframe)
+		}
+	}
+	return groups
+}
+
+func isCompressionEnabled(table string) (bool, error) {
+	var enabled bool
+	err := DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM timescaledb_information.hypertables WHERE hypertable_name = $1 AND compression_enabled)`, table).Scan(&enabled)
+	if err != nil {
+		return false, fmt.Errorf("💾 error checking compression of %s: %v", table, err)
+	}
+	return enabled, nil
+}
+
+func execStatements(key string, statements ...string) error {
+	for _, statement := range statements {
+		if _, err := DB.Exec(statement); err != nil {
+			return fmt.Errorf("💾 error provisioning timescale tables of %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func timestampLiteral(value *time.Time) string {
+	if value == nil {
+		return "NULL"
+	}
+	return fmt.Sprintf("'%s'::TIMESTAMPTZ", value.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,52 @@
+package exchange
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/coinbase"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/kraken"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/okx"
+)
+
+// MarketDataSource is an exchange REST API that candles and tickers are ingested from
+type MarketDataSource interface {
+	Name() string
+	// Symbol maps an instrument key to the exchange symbol of its pair
+	Symbol(currency string) string
+	// MaxKlineLimit is the largest number of klines returned by a single request
+	MaxKlineLimit() int
+	FetchKline(currency string, interval string, limit int) ([]*dto.DataDto, error)
+	FetchKlineRange(currency string, interval string, startTime time.Time, endTime time.Time, limit int) ([]*dto.DataDto, error)
+	FetchTicker(currency string) (*dto.DataDto, error)
+}
+
+func NewMarketDataSource(name string) (MarketDataSource, error) {
+	switch name {
+	case config.EXCHANGE_BINANCE:
+		return binance.NewBinanceSource(), nil
+	case config.EXCHANGE_COINBASE:
+		return coinbase.NewCoinbaseSource(), nil
+	case config.EXCHANGE_KRAKEN:
+		return kraken.NewKrakenSource(), nil
+	case config.EXCHANGE_OKX:
+		return okx.NewOkxSource(), nil
+	}
+	return nil, fmt.Errorf("unknown market data source: %s", name)
+}
+
+// GetSourceForCurrency returns the source configured for the currency in MARKET_DATA_SOURCES, binance by default
+func GetSourceForCurrency(currency string) (MarketDataSource, error) {
+	return NewMarketDataSource(GetSourceNameForCurrency(currency))
+}
+
+func GetSourceNameForCurrency(currency string) string {
+	cfg := config.LoadConfig()
+	if name, ok := cfg.MarketDataSources[currency]; ok {
+		return name
+	}
+	return config.EXCHANGE_BINANCE
+}
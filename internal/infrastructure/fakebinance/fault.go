@@ -0,0 +1,95 @@
+package fakebinance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FaultKind string
+
+const (
+	FaultRateLimit   FaultKind = "429"       // answered with 429 and a Retry-After header
+	FaultServerError FaultKind = "5xx"       // answered with a 5xx status
+	FaultSlow        FaultKind = "slow"      // answered after a delay
+	FaultTruncated   FaultKind = "truncated" // the body is cut in half, the websocket drops after a truncated event
+)
+
+// Fault replaces the regular response of the next Times requests to an endpoint
+type Fault struct {
+	Kind       FaultKind
+	Endpoint   string        // klines, ticker/24hr, exchangeInfo, time or ws, every endpoint when empty
+	Times      int           // requests the fault applies to, 1 when zero
+	After      int           // requests to the endpoint answered regularly before the fault applies
+	Status     int           // status of a server error, 503 when zero
+	RetryAfter int           // seconds announced by a rate limit, 1 when zero
+	Delay      time.Duration // delay of a slow response
+}
+
+func (fault Fault) matches(endpoint string) bool {
+	return fault.Endpoint == "" || fault.Endpoint == endpoint
+}
+
+// ParseFaults reads a comma separated fault script of endpoint:kind[:times[:parameter]] entries,
+// the parameter is the status of a 5xx, the Retry-After seconds of a 429 and the delay of a slow response.
+// "*" matches every endpoint, ex: klines:429:1:2,*:5xx:3:502,ticker/24hr:slow:1:2s,exchangeInfo:truncated
+func ParseFaults(script string) ([]Fault, error) {
+	var faults []Fault
+	for _, entry := range strings.Split(script, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		fault, err := parseFault(entry)
+		if err != nil {
+			return nil, err
+		}
+		faults = append(faults, fault)
+	}
+	return faults, nil
+}
+
+func parseFault(entry string) (Fault, error) {
+	parts := strings.Split(entry, ":")
+	if len(parts) < 2 || len(parts) > 4 {
+		return Fault{}, fmt.Errorf("invalid fault %q, expected endpoint:kind[:times[:parameter]]", entry)
+	}
+
+	fault := Fault{Kind: FaultKind(parts[1]), Times: 1}
+	if parts[0] != "*" {
+		fault.Endpoint = parts[0]
+	}
+	if len(parts) > 2 {
+		times, err := strconv.Atoi(parts[2])
+		if err != nil || times < 1 {
+			return Fault{}, fmt.Errorf("invalid times in fault %q", entry)
+		}
+		fault.Times = times
+	}
+
+	parameter := ""
+	if len(parts) > 3 {
+		parameter = parts[3]
+	}
+	var err error
+	switch fault.Kind {
+	case FaultRateLimit:
+		if parameter != "" {
+			fault.RetryAfter, err = strconv.Atoi(parameter)
+		}
+	case FaultServerError:
+		if parameter != "" {
+			fault.Status, err = strconv.Atoi(parameter)
+		}
+	case FaultSlow:
+		fault.Delay, err = time.ParseDuration(parameter)
+	case FaultTruncated:
+	default:
+		return Fault{}, fmt.Errorf("unknown fault kind %q", fault.Kind)
+	}
+	if err != nil {
+		return Fault{}, fmt.Errorf("invalid parameter in fault %q: %w", entry, err)
+	}
+	return fault, nil
+}
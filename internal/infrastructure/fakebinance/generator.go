@@ -0,0 +1,85 @@
+package fakebinance
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+)
+
+// Candle is a generated kline, prices are rounded to the 0.01 tick of the fake symbols
+type Candle struct {
+	OpenTime            time.Time
+	CloseTime           time.Time
+	Open                float64
+	High                float64
+	Low                 float64
+	Close               float64
+	Volume              float64
+	QuoteVolume         float64
+	TradeCount          int64
+	TakerBuyBaseVolume  float64
+	TakerBuyQuoteVolume float64
+}
+
+// PriceGenerator derives candles from a seed without keeping state:
+// the same seed, symbol and open time always give the same candle, so ranges overlap consistently between requests
+// and a candle opens at the close of the previous one whatever the interval is.
+type PriceGenerator struct {
+	seed uint64
+}
+
+func NewPriceGenerator(seed int64) *PriceGenerator {
+	return &PriceGenerator{seed: uint64(seed)}
+}
+
+func (generator *PriceGenerator) Candle(symbol string, interval time.Duration, openTime time.Time) Candle {
+	openTime = openTime.UTC()
+	closeTime := openTime.Add(interval)
+	open := generator.Price(symbol, openTime)
+	close := generator.Price(symbol, closeTime)
+
+	// the wicks reach out up to 0.2% beyond the body
+	random := generator.random(symbol, "wick", openTime)
+	high := round(math.Max(open, close)*(1+random.Float64()*0.002), 2)
+	low := round(math.Min(open, close)*(1-random.Float64()*0.002), 2)
+
+	// base volume grows with the interval, about 50 per hour
+	volume := round((0.5+random.Float64())*50*interval.Hours(), 8)
+	averagePrice := (open + close + high + low) / 4
+	takerShare := 0.4 + random.Float64()*0.2
+	return Candle{
+		OpenTime:            openTime,
+		CloseTime:           closeTime.Add(-time.Millisecond),
+		Open:                open,
+		High:                high,
+		Low:                 low,
+		Close:               close,
+		Volume:              volume,
+		QuoteVolume:         round(volume*averagePrice, 8),
+		TradeCount:          int64(volume*10) + 1,
+		TakerBuyBaseVolume:  round(volume*takerShare, 8),
+		TakerBuyQuoteVolume: round(volume*takerShare*averagePrice, 8),
+	}
+}
+
+// Price returns the price of the symbol at a moment, a weekly and a daily wave with noise that changes every minute
+func (generator *PriceGenerator) Price(symbol string, moment time.Time) float64 {
+	base := 10 + generator.random(symbol, "base", time.Time{}).Float64()*990
+	hours := float64(moment.Unix()) / 3600
+	trend := 1 + 0.05*math.Sin(2*math.Pi*hours/168) + 0.01*math.Sin(2*math.Pi*hours/24)
+	noise := 1 + (generator.random(symbol, "noise", moment.Truncate(time.Minute)).Float64()-0.5)*0.002
+	return round(base*trend*noise, 2)
+}
+
+func (generator *PriceGenerator) random(symbol string, purpose string, moment time.Time) *rand.Rand {
+	hash := fnv.New64a()
+	hash.Write([]byte(symbol + "/" + purpose + "/" + strconv.FormatInt(moment.Unix(), 10)))
+	return rand.New(rand.NewPCG(generator.seed, hash.Sum64()))
+}
+
+func round(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
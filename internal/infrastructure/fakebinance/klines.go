@@ -0,0 +1,208 @@
+package fakebinance
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+)
+
+func (server *Server) klines(query url.Values) ([]byte, int) {
+	symbol := query.Get("symbol")
+	if symbol == "" {
+		return errorBody(-1102, "Mandatory parameter 'symbol' was not sent, was empty/null, or malformed."), http.StatusBadRequest
+	}
+	interval, ok := intervals[query.Get("interval")]
+	if !ok {
+		return errorBody(-1120, "Invalid interval."), http.StatusBadRequest
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 500
+	}
+	limit = min(limit, 1000)
+	startTime := parseMillis(query.Get("startTime"))
+	endTime := parseMillis(query.Get("endTime"))
+
+	if rows, ok := server.fixtureKlines(startTime, endTime, limit); ok {
+		return encode(rows), http.StatusOK
+	}
+
+	now := server.Now()
+	last := now.Truncate(interval)
+	if endTime != nil && endTime.Before(last) {
+		last = endTime.Truncate(interval)
+	}
+	first := last.Add(-time.Duration(limit-1) * interval)
+	if startTime != nil {
+		first = startTime.Truncate(interval)
+		if first.Before(*startTime) {
+			first = first.Add(interval)
+		}
+	}
+
+	rows := make([][]any, 0, limit)
+	for openTime := first; !openTime.After(last) && len(rows) < limit; openTime = openTime.Add(interval) {
+		if server.isMissing(openTime, interval) {
+			continue
+		}
+		rows = append(rows, klineRow(server.candle(symbol, interval, openTime, now)))
+	}
+	return encode(rows), http.StatusOK
+}
+
+// candle generates the kline, the one still forming is cut at the current price
+func (server *Server) candle(symbol string, interval time.Duration, openTime time.Time, now time.Time) Candle {
+	candle := server.generator.Candle(symbol, interval, openTime)
+	if !candle.CloseTime.After(now) {
+		return candle
+	}
+
+	elapsed := float64(now.Sub(openTime)) / float64(interval)
+	candle.Close = server.generator.Price(symbol, now)
+	candle.High = math.Max(candle.High, candle.Close)
+	candle.Low = math.Min(candle.Low, candle.Close)
+	candle.Volume = round(candle.Volume*elapsed, 8)
+	candle.QuoteVolume = round(candle.QuoteVolume*elapsed, 8)
+	candle.TradeCount = int64(float64(candle.TradeCount)*elapsed) + 1
+	candle.TakerBuyBaseVolume = round(candle.TakerBuyBaseVolume*elapsed, 8)
+	candle.TakerBuyQuoteVolume = round(candle.TakerBuyQuoteVolume*elapsed, 8)
+	return candle
+}
+
+// fixtureKlines serves the rows of klines.json opened within the range, ok is false without a fixture
+func (server *Server) fixtureKlines(startTime *time.Time, endTime *time.Time, limit int) ([]json.RawMessage, bool) {
+	if server.options.FixturesDir == "" {
+		return nil, false
+	}
+	fixture, err := os.ReadFile(filepath.Join(server.options.FixturesDir, "klines.json"))
+	if err != nil {
+		return nil, false
+	}
+	var rows []json.RawMessage
+	if err := json.Unmarshal(fixture, &rows); err != nil {
+		return nil, false
+	}
+
+	selected := make([]json.RawMessage, 0, limit)
+	for _, row := range rows {
+		var fields []any
+		if err := json.Unmarshal(row, &fields); err != nil || len(fields) == 0 {
+			continue
+		}
+		openMillis, _ := fields[0].(float64)
+		openTime := time.UnixMilli(int64(openMillis)).UTC()
+		if (startTime != nil && openTime.Before(*startTime)) || (endTime != nil && openTime.After(*endTime)) {
+			continue
+		}
+		if server.isMissing(openTime, time.Hour) {
+			continue
+		}
+		selected = append(selected, row)
+	}
+	// without a start binance returns the latest klines
+	if startTime == nil && len(selected) > limit {
+		return selected[len(selected)-limit:], true
+	}
+	return selected[:min(limit, len(selected))], true
+}
+
+func (server *Server) ticker(symbol string) dto.BinanceTickerResponse {
+	now := server.Now()
+	openTime := now.Add(-24 * time.Hour)
+	open := server.generator.Price(symbol, openTime)
+	last := server.generator.Price(symbol, now)
+
+	high, low := math.Max(open, last), math.Min(open, last)
+	var volume, quoteVolume float64
+	var count int64
+	for hour := openTime.Truncate(time.Hour); hour.Before(now); hour = hour.Add(time.Hour) {
+		candle := server.candle(symbol, time.Hour, hour, now)
+		high = math.Max(high, candle.High)
+		low = math.Min(low, candle.Low)
+		volume += candle.Volume
+		quoteVolume += candle.QuoteVolume
+		count += candle.TradeCount
+	}
+
+	return dto.BinanceTickerResponse{
+		Symbol:             symbol,
+		OpenPrice:          formatPrice(open),
+		HighPrice:          formatPrice(high),
+		LowPrice:           formatPrice(low),
+		LastPrice:          formatPrice(last),
+		Volume:             formatPrice(volume),
+		PriceChange:        formatPrice(round(last-open, 2)),
+		PriceChangePercent: strconv.FormatFloat(round((last-open)/open*100, 3), 'f', 3, 64),
+		WeightedAvgPrice:   formatPrice(round(quoteVolume/volume, 2)),
+		QuoteVolume:        formatPrice(round(quoteVolume, 8)),
+		OpenTime:           openTime.UnixMilli(),
+		CloseTime:          now.UnixMilli(),
+		Count:              count,
+	}
+}
+
+func (server *Server) exchangeInfo() any {
+	symbols := make([]dto.BinanceSymbolInfo, 0, len(server.options.Symbols))
+	for _, symbol := range server.options.Symbols {
+		baseAsset, quoteAsset := splitSymbol(symbol)
+		symbols = append(symbols, dto.BinanceSymbolInfo{
+			Symbol:     symbol,
+			Status:     "TRADING",
+			BaseAsset:  baseAsset,
+			QuoteAsset: quoteAsset,
+			Filters: []dto.BinanceSymbolFilter{
+				{FilterType: "PRICE_FILTER", TickSize: "0.01000000"},
+				{FilterType: "LOT_SIZE", StepSize: "0.00001000", MinQty: "0.00001000"},
+				{FilterType: "NOTIONAL", MinNotional: "5.00000000"},
+			},
+		})
+	}
+	return map[string]any{
+		"timezone":   "UTC",
+		"serverTime": server.Now().UnixMilli(),
+		"symbols":    symbols,
+	}
+}
+
+func klineRow(candle Candle) []any {
+	return []any{
+		candle.OpenTime.UnixMilli(),
+		formatPrice(candle.Open),
+		formatPrice(candle.High),
+		formatPrice(candle.Low),
+		formatPrice(candle.Close),
+		formatPrice(candle.Volume),
+		candle.CloseTime.UnixMilli(),
+		formatPrice(candle.QuoteVolume),
+		candle.TradeCount,
+		formatPrice(candle.TakerBuyBaseVolume),
+		formatPrice(candle.TakerBuyQuoteVolume),
+		"0",
+	}
+}
+
+func splitSymbol(symbol string) (string, string) {
+	for _, quote := range quoteAssets {
+		if base, ok := strings.CutSuffix(symbol, quote); ok && base != "" {
+			return base, quote
+		}
+	}
+	return symbol, ""
+}
+
+func parseMillis(value string) *time.Time {
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	moment := time.UnixMilli(millis).UTC()
+	return &moment
+}
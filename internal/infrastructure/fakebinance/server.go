@@ -0,0 +1,225 @@
+package fakebinance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// intervals the fake server generates klines for
+var intervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// quote assets the base asset of a symbol is split from
+var quoteAssets = []string{"USDT", "FDUSD", "USDC", "BTC", "ETH", "BNB"}
+
+type Options struct {
+	Seed           int64
+	FixturesDir    string        // fixture files served instead of generated data when they exist, ex: klines.json, ticker.json, tickers.json
+	Symbols        []string      // symbols of exchangeInfo and ticker/24hr, BTCUSDT and ETHUSDT when empty
+	Faults         []Fault       // scripted faults, consumed in order
+	MissingHours   []time.Time   // hours left out of klines and the stream
+	ClockOffset    time.Duration // added to the time the server reports
+	StreamInterval time.Duration // between kline stream events, 1 second when zero
+}
+
+// Server is a stand-in for the binance spot REST API and kline stream, the REST endpoints are served under /api/v3/ and the stream under /ws
+type Server struct {
+	options    Options
+	generator  *PriceGenerator
+	mu         sync.Mutex
+	faults     []Fault
+	missing    map[time.Time]bool
+	requests   map[string]int
+	httpServer *httptest.Server
+}
+
+func NewServer(options Options) *Server {
+	if len(options.Symbols) == 0 {
+		options.Symbols = []string{"BTCUSDT", "ETHUSDT"}
+	}
+	if options.StreamInterval == 0 {
+		options.StreamInterval = time.Second
+	}
+	server := &Server{
+		options:   options,
+		generator: NewPriceGenerator(options.Seed),
+		missing:   make(map[time.Time]bool),
+		requests:  make(map[string]int),
+	}
+	server.Inject(options.Faults...)
+	server.DropHours(options.MissingHours...)
+	return server
+}
+
+// Start serves on a random local port and returns the base url, BINANCE_BASE_API_URL is the url followed by /api/v3/
+func (server *Server) Start() string {
+	server.httpServer = httptest.NewServer(server)
+	return server.httpServer.URL
+}
+
+func (server *Server) Close() {
+	if server.httpServer != nil {
+		server.httpServer.Close()
+	}
+}
+
+// Inject appends faults to the script
+func (server *Server) Inject(faults ...Fault) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, fault := range faults {
+		if fault.Times == 0 {
+			fault.Times = 1
+		}
+		server.faults = append(server.faults, fault)
+	}
+}
+
+// DropHours leaves the hours out of klines and the stream, the candles of longer intervals that contain them are still served
+func (server *Server) DropHours(hours ...time.Time) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for _, hour := range hours {
+		server.missing[hour.UTC().Truncate(time.Hour)] = true
+	}
+}
+
+// Requests returns how many requests the endpoint received, faulty ones included
+func (server *Server) Requests(endpoint string) int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return server.requests[endpoint]
+}
+
+func (server *Server) Now() time.Time {
+	return time.Now().UTC().Add(server.options.ClockOffset)
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"), "api/v3/")
+	fault, faulty := server.takeFault(endpoint)
+
+	if faulty {
+		switch fault.Kind {
+		case FaultRateLimit:
+			w.Header().Set("Retry-After", strconv.Itoa(max(fault.RetryAfter, 1)))
+			writeError(w, http.StatusTooManyRequests, -1003, "Too many requests; current limit is exceeded.")
+			return
+		case FaultServerError:
+			status := fault.Status
+			if status == 0 {
+				status = http.StatusServiceUnavailable
+			}
+			writeError(w, status, -1001, "Internal error; unable to process your request. Please try again.")
+			return
+		case FaultSlow:
+			time.Sleep(fault.Delay)
+		}
+	}
+
+	if endpoint == "ws" {
+		server.serveStream(w, r, faulty && fault.Kind == FaultTruncated)
+		return
+	}
+
+	body, status := server.respond(endpoint, r)
+	if faulty && fault.Kind == FaultTruncated {
+		body = body[:len(body)/2]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+func (server *Server) takeFault(endpoint string) (Fault, bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.requests[endpoint]++
+	for i := range server.faults {
+		if server.faults[i].Times > 0 && server.faults[i].matches(endpoint) {
+			if server.faults[i].After > 0 {
+				server.faults[i].After--
+				return Fault{}, false
+			}
+			server.faults[i].Times--
+			return server.faults[i], true
+		}
+	}
+	return Fault{}, false
+}
+
+func (server *Server) respond(endpoint string, r *http.Request) ([]byte, int) {
+	query := r.URL.Query()
+	switch endpoint {
+	case "klines":
+		return server.klines(query)
+	case "ticker/24hr":
+		if query.Has("symbol") {
+			return server.fixtureOr("ticker.json", func() any { return server.ticker(query.Get("symbol")) })
+		}
+		return server.fixtureOr("tickers.json", func() any {
+			tickers := make([]any, 0, len(server.options.Symbols))
+			for _, symbol := range server.options.Symbols {
+				tickers = append(tickers, server.ticker(symbol))
+			}
+			return tickers
+		})
+	case "exchangeInfo":
+		return server.fixtureOr("exchangeInfo.json", server.exchangeInfo)
+	case "time":
+		return encode(map[string]int64{"serverTime": server.Now().UnixMilli()}), http.StatusOK
+	}
+	return errorBody(-1, "Unknown endpoint "+endpoint), http.StatusNotFound
+}
+
+// fixtureOr serves the fixture file when it exists and the generated response otherwise
+func (server *Server) fixtureOr(name string, generate func() any) ([]byte, int) {
+	if server.options.FixturesDir != "" {
+		if fixture, err := os.ReadFile(filepath.Join(server.options.FixturesDir, name)); err == nil {
+			return fixture, http.StatusOK
+		}
+	}
+	return encode(generate()), http.StatusOK
+}
+
+func (server *Server) isMissing(openTime time.Time, interval time.Duration) bool {
+	if interval > time.Hour {
+		return false
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return server.missing[openTime.Truncate(time.Hour)]
+}
+
+func encode(value any) []byte {
+	body, _ := json.Marshal(value)
+	return body
+}
+
+func errorBody(code int, message string) []byte {
+	return encode(map[string]any{"code": code, "msg": message})
+}
+
+func writeError(w http.ResponseWriter, status int, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(errorBody(code, message))
+}
+
+func formatPrice(value float64) string {
+	return strconv.FormatFloat(value, 'f', 8, 64)
+}
@@ -0,0 +1,121 @@
+package fakebinance
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{}
+
+// serveStream answers a SUBSCRIBE to <symbol>@kline_<interval> streams with the forming candle every StreamInterval
+// and the closed one once its interval is over. A truncated fault sends half of the first event and drops the connection.
+func (server *Server) serveStream(w http.ResponseWriter, r *http.Request, truncated bool) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var request struct {
+		Method string   `json:"method"`
+		Params []string `json:"params"`
+		Id     int64    `json:"id"`
+	}
+	if err := conn.ReadJSON(&request); err != nil || request.Method != "SUBSCRIBE" {
+		return
+	}
+	if err := conn.WriteJSON(map[string]any{"result": nil, "id": request.Id}); err != nil {
+		return
+	}
+
+	// the client closing the socket ends the stream
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	lastOpen := make(map[string]time.Time)
+	ticker := time.NewTicker(server.options.StreamInterval)
+	defer ticker.Stop()
+	for {
+		now := server.Now()
+		for _, stream := range request.Params {
+			symbol, intervalName, ok := parseStream(stream)
+			if !ok {
+				continue
+			}
+			interval := intervals[intervalName]
+			openTime := now.Truncate(interval)
+
+			var events [][]byte
+			if previous, ok := lastOpen[stream]; ok && previous.Before(openTime) && !server.isMissing(previous, interval) {
+				events = append(events, klineEvent(symbol, intervalName, server.generator.Candle(symbol, interval, previous), true, now))
+			}
+			lastOpen[stream] = openTime
+			if !server.isMissing(openTime, interval) {
+				events = append(events, klineEvent(symbol, intervalName, server.candle(symbol, interval, openTime, now), false, now))
+			}
+
+			for _, event := range events {
+				if truncated {
+					conn.WriteMessage(websocket.TextMessage, event[:len(event)/2])
+					return
+				}
+				if err := conn.WriteMessage(websocket.TextMessage, event); err != nil {
+					return
+				}
+			}
+		}
+
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseStream splits btcusdt@kline_1h into BTCUSDT and 1h
+func parseStream(stream string) (string, string, bool) {
+	symbol, interval, ok := strings.Cut(stream, "@kline_")
+	if !ok {
+		return "", "", false
+	}
+	if _, ok := intervals[interval]; !ok {
+		return "", "", false
+	}
+	return strings.ToUpper(symbol), interval, true
+}
+
+func klineEvent(symbol string, interval string, candle Candle, isClosed bool, now time.Time) []byte {
+	return encode(dto.BinanceKlineEvent{
+		EventType: "kline",
+		EventTime: now.UnixMilli(),
+		Symbol:    symbol,
+		Kline: dto.BinanceStreamKline{
+			OpenTime:  candle.OpenTime.UnixMilli(),
+			CloseTime: candle.CloseTime.UnixMilli(),
+			Interval:  interval,
+			Open:      formatPrice(candle.Open),
+			Close:     formatPrice(candle.Close),
+			High:      formatPrice(candle.High),
+			Low:       formatPrice(candle.Low),
+			Volume:    formatPrice(candle.Volume),
+			IsClosed:  isClosed,
+
+			QuoteVolume:         formatPrice(candle.QuoteVolume),
+			TradeCount:          candle.TradeCount,
+			TakerBuyBaseVolume:  formatPrice(candle.TakerBuyBaseVolume),
+			TakerBuyQuoteVolume: formatPrice(candle.TakerBuyQuoteVolume),
+		},
+	})
+}
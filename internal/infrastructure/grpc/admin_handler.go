@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+	"log"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/backfill"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/currency"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/gap"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type AdminHandler struct {
+	BackfillService *backfill.BackfillService
+	GapService      *gap.GapService
+	CurrencyService *currency.CurrencyService
+}
+
+func NewAdminHandler(backfillService *backfill.BackfillService, gapService *gap.GapService, currencyService *currency.CurrencyService) *AdminHandler {
+	return &AdminHandler{
+		BackfillService: backfillService,
+		GapService:      gapService,
+		CurrencyService: currencyService,
+	}
+}
+
+// Backfill starts a backfill in the background and returns the checkpoint it starts from
+func (handler *AdminHandler) Backfill(ctx context.Context, request *pb.BackfillRequest) (*pb.BackfillResponse, error) {
+	if request.StartTime == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_time is required")
+	}
+
+	checkpoint, err := handler.BackfillService.StartBackfill(instrumentKey(request.Currency, ""), request.StartTime.AsTime())
+	if err != nil {
+		log.Printf("Error starting backfill: %v", err)
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return handler.mapCheckpointToResponse(checkpoint), nil
+}
+
+// ScanGaps scans and repairs missing 1h records of one currency, or of all currencies when none is given
+func (handler *AdminHandler) ScanGaps(ctx context.Context, request *pb.GapScanRequest) (*pb.GapScanResponse, error) {
+	currencies := instrument.TrackedKeys()
+	if request.Currency != "" {
+		currencies = []string{instrumentKey(request.Currency, "")}
+	}
+
+	response := &pb.GapScanResponse{}
+	for _, currency := range currencies {
+		report, err := handler.GapService.ScanAndRepair(currency)
+		if err != nil {
+			log.Printf("Error scanning gaps: %v", err)
+			return nil, status.Error(codes.InvalidArgument, "resource value(s) is invalid")
+		}
+		response.Reports = append(response.Reports, handler.mapGapReportToResponse(report))
+	}
+	return response, nil
+}
+
+func (handler *AdminHandler) GetCurrencies(ctx context.Context, request *pb.CurrenciesRequest) (*pb.CurrenciesResponse, error) {
+	records, err := handler.CurrencyService.GetCurrencies()
+	if err != nil {
+		log.Printf("Error fetching currencies: %v", err)
+		return nil, status.Error(codes.Internal, "failed to fetch currencies")
+	}
+
+	response := &pb.CurrenciesResponse{}
+	for _, record := range records {
+		response.Currencies = append(response.Currencies, handler.mapCurrencyToResponse(record))
+	}
+	return response, nil
+}
+
+// AddCurrency provisions the tables of the instrument and starts ingesting it, a paused currency is resumed
+func (handler *AdminHandler) AddCurrency(ctx context.Context, request *pb.AddCurrencyRequest) (*pb.Currency, error) {
+	record, err := handler.CurrencyService.AddCurrency(instrumentKey(request.Currency, request.Quote))
+	if err != nil {
+		log.Printf("Error adding currency: %v", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return handler.mapCurrencyToResponse(record), nil
+}
+
+// PauseCurrency stops ingesting the currency, its stored data is still served
+func (handler *AdminHandler) PauseCurrency(ctx context.Context, request *pb.CurrencyRequest) (*pb.Currency, error) {
+	record, err := handler.CurrencyService.PauseCurrency(instrumentKey(request.Currency, request.Quote))
+	if err != nil {
+		log.Printf("Error pausing currency: %v", err)
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return handler.mapCurrencyToResponse(record), nil
+}
+
+// RemoveCurrency unregisters the currency, its tables and data are kept
+func (handler *AdminHandler) RemoveCurrency(ctx context.Context, request *pb.CurrencyRequest) (*pb.RemoveCurrencyResponse, error) {
+	key := instrumentKey(request.Currency, request.Quote)
+	if err := handler.CurrencyService.RemoveCurrency(key); err != nil {
+		log.Printf("Error removing currency: %v", err)
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &pb.RemoveCurrencyResponse{Currency: key}, nil
+}
+
+func (handler *AdminHandler) mapCurrencyToResponse(record *dto.CurrencyDto) *pb.Currency {
+	return &pb.Currency{
+		Currency:  record.Key,
+		Base:      record.BaseAsset,
+		Quote:     record.QuoteAsset,
+		Status:    record.Status,
+		CreatedAt: timestamppb.New(record.CreatedAt),
+		UpdatedAt: timestamppb.New(record.UpdatedAt),
+	}
+}
+
+func (handler *AdminHandler) mapGapReportToResponse(report *dto.GapReportDto) *pb.GapReport {
+	gaps := make([]*pb.Gap, len(report.Unfilled))
+	for i, gap := range report.Unfilled {
+		gaps[i] = &pb.Gap{
+			StartTime: timestamppb.New(gap.StartTime),
+			EndTime:   timestamppb.New(gap.EndTime),
+		}
+	}
+	return &pb.GapReport{
+		Currency:     report.Currency,
+		Timeframe:    report.Timeframe,
+		MissingHours: int32(report.MissingHours),
+		FilledHours:  int32(report.FilledHours),
+		UnfilledGaps: gaps,
+	}
+}
+
+func (handler *AdminHandler) mapCheckpointToResponse(checkpoint *dto.BackfillCheckpointDto) *pb.BackfillResponse {
+	response := &pb.BackfillResponse{
+		Currency:   checkpoint.Currency,
+		Timeframe:  checkpoint.Timeframe,
+		StartTime:  timestamppb.New(checkpoint.StartTime),
+		IsComplete: checkpoint.IsComplete,
+	}
+	if checkpoint.LastTimestamp != nil {
+		response.LastTimestamp = timestamppb.New(*checkpoint.LastTimestamp)
+	}
+	return response
+}
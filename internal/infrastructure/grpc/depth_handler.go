@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	"log"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/orderbook"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type DepthHandler struct {
+	OrderBookService *orderbook.OrderBookService
+}
+
+func NewDepthHandler(orderBookService *orderbook.OrderBookService) *DepthHandler {
+	return &DepthHandler{
+		OrderBookService: orderBookService,
+	}
+}
+
+func (handler *DepthHandler) GetDepth(ctx context.Context, request *pb.DepthRequest) (*pb.DepthResponse, error) {
+	depthRequestDto := handler.mapRequestToDTO(request)
+	records, err := handler.OrderBookService.GetRecordsByRequest(depthRequestDto)
+	if err != nil {
+		log.Printf("Error fetching data: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "resource value(s) is invalid")
+	}
+
+	return handler.mapDtoToResponse(records), nil
+}
+
+func (handler *DepthHandler) mapRequestToDTO(req *pb.DepthRequest) dto.DepthRequestDto {
+	dto := dto.DepthRequestDto{
+		Currency: instrumentKey(req.Currency, ""),
+	}
+
+	if req.StartTime != nil {
+		startTime := req.StartTime.AsTime()
+		dto.StartTime = &startTime
+	}
+
+	if req.EndTime != nil {
+		endTime := req.EndTime.AsTime()
+		dto.EndTime = &endTime
+	}
+
+	if req.Limit > 0 {
+		dto.Limit = req.Limit
+	} else {
+		dto.Limit = int32(config.DEFAULT_LIMIT)
+	}
+
+	if req.SortOrder == "ASC" || req.SortOrder == "DESC" {
+		dto.SortOrder = req.SortOrder
+	} else {
+		dto.SortOrder = config.DEFAULT_DATA_REQUEST_SORT_ORDER
+	}
+
+	return dto
+}
+
+func (handler *DepthHandler) mapDtoToResponse(records []dto.DepthSnapshotDto) *pb.DepthResponse {
+	snapshots := make([]*pb.DepthSnapshot, len(records))
+	for i, record := range records {
+		snapshots[i] = &pb.DepthSnapshot{
+			Timestamp:  timestamppb.New(record.Timestamp),
+			Currency:   record.Symbol,
+			BestBid:    record.BestBid.InexactFloat64(),
+			BestAsk:    record.BestAsk.InexactFloat64(),
+			MidPrice:   record.MidPrice.InexactFloat64(),
+			Spread:     record.Spread.InexactFloat64(),
+			BidDepth05: record.BidDepth05.InexactFloat64(),
+			AskDepth05: record.AskDepth05.InexactFloat64(),
+			BidDepth1:  record.BidDepth1.InexactFloat64(),
+			AskDepth1:  record.AskDepth1.InexactFloat64(),
+			BidDepth2:  record.BidDepth2.InexactFloat64(),
+			AskDepth2:  record.AskDepth2.InexactFloat64(),
+		}
+	}
+	return &pb.DepthResponse{
+		Records: snapshots,
+	}
+}
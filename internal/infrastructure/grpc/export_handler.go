@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"bufio"
+	"log"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/export"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// exportChunkSize is the size of the ExportChunk messages the encoded export is split into
+const exportChunkSize = 64 * 1024
+
+type ExportHandler struct {
+	ExportService *export.ExportService
+}
+
+func NewExportHandler(exportService *export.ExportService) *ExportHandler {
+	return &ExportHandler{
+		ExportService: exportService,
+	}
+}
+
+// ExportData streams the encoded export in chunks as it is produced, the client concatenates the chunks into the file
+func (handler *ExportHandler) ExportData(request *pb.ExportRequest, stream pb.MarketPulse_ExportDataServer) error {
+	output := bufio.NewWriterSize(chunkWriter{stream: stream}, exportChunkSize)
+	count, err := handler.ExportService.Export(stream.Context(), mapExportRequestToDTO(request), output)
+	if err != nil {
+		log.Printf("Error exporting data: %v", err)
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := output.Flush(); err != nil {
+		return err
+	}
+	log.Printf("✅ exported %d rows of %s %s", count, request.Currency, request.Timeframe)
+	return nil
+}
+
+func mapExportRequestToDTO(request *pb.ExportRequest) dto.ExportRequestDto {
+	exportRequestDto := dto.ExportRequestDto{
+		Currency:   instrumentKey(request.Currency, request.Quote),
+		Timeframe:  sourceTimeframe(request.Source, request.Timeframe),
+		Format:     request.Format,
+		Columns:    request.Columns,
+		TimeFormat: request.TimeFormat,
+	}
+	if request.StartTime != nil {
+		startTime := request.StartTime.AsTime()
+		exportRequestDto.StartTime = &startTime
+	}
+	if request.EndTime != nil {
+		endTime := request.EndTime.AsTime()
+		exportRequestDto.EndTime = &endTime
+	}
+	return exportRequestDto
+}
+
+// chunkWriter sends every write as one ExportChunk
+type chunkWriter struct {
+	stream pb.MarketPulse_ExportDataServer
+}
+
+func (writer chunkWriter) Write(data []byte) (int, error) {
+	if err := writer.stream.Send(&pb.ExportChunk{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
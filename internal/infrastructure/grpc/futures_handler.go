@@ -0,0 +1,140 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"slices"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/futures"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type FuturesHandler struct {
+	FuturesService *futures.FuturesService
+}
+
+func NewFuturesHandler(futuresService *futures.FuturesService) *FuturesHandler {
+	return &FuturesHandler{
+		FuturesService: futuresService,
+	}
+}
+
+func (handler *FuturesHandler) GetFundingRates(ctx context.Context, request *pb.FuturesRequest) (*pb.FundingRateResponse, error) {
+	records, err := handler.FuturesService.GetFundingRates(handler.mapRequestToDTO(request, "funding_rate"))
+	if err != nil {
+		log.Printf("Error fetching data: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "resource value(s) is invalid")
+	}
+
+	response := &pb.FundingRateResponse{Records: make([]*pb.FundingRate, len(records))}
+	for i, record := range records {
+		response.Records[i] = &pb.FundingRate{
+			Timestamp:   timestamppb.New(record.Timestamp),
+			Currency:    record.Symbol,
+			FundingRate: record.FundingRate.InexactFloat64(),
+			MarkPrice:   record.MarkPrice.InexactFloat64(),
+		}
+	}
+	return response, nil
+}
+
+func (handler *FuturesHandler) GetOpenInterest(ctx context.Context, request *pb.FuturesRequest) (*pb.OpenInterestResponse, error) {
+	records, err := handler.FuturesService.GetOpenInterest(handler.mapRequestToDTO(request, "open_interest"))
+	if err != nil {
+		log.Printf("Error fetching data: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "resource value(s) is invalid")
+	}
+
+	response := &pb.OpenInterestResponse{Records: make([]*pb.OpenInterest, len(records))}
+	for i, record := range records {
+		response.Records[i] = &pb.OpenInterest{
+			Timestamp:    timestamppb.New(record.Timestamp),
+			Currency:     record.Symbol,
+			OpenInterest: record.OpenInterest.InexactFloat64(),
+		}
+	}
+	return response, nil
+}
+
+func (handler *FuturesHandler) GetMarkPriceKlines(ctx context.Context, request *pb.FuturesRequest) (*pb.MarkPriceResponse, error) {
+	records, err := handler.FuturesService.GetMarkPrices(handler.mapRequestToDTO(request, "close"))
+	if err != nil {
+		log.Printf("Error fetching data: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "resource value(s) is invalid")
+	}
+
+	response := &pb.MarkPriceResponse{Records: make([]*pb.MarkPriceKline, len(records))}
+	for i, record := range records {
+		response.Records[i] = &pb.MarkPriceKline{
+			Timestamp:  timestamppb.New(record.Timestamp),
+			Currency:   record.Symbol,
+			Open:       record.Open.InexactFloat64(),
+			High:       record.High.InexactFloat64(),
+			Low:        record.Low.InexactFloat64(),
+			Close:      record.Close.InexactFloat64(),
+			IsComplete: record.IsComplete,
+		}
+	}
+	return response, nil
+}
+
+func (handler *FuturesHandler) GetBasis(ctx context.Context, request *pb.FuturesRequest) (*pb.BasisResponse, error) {
+	records, err := handler.FuturesService.GetBasis(handler.mapRequestToDTO(request, "basis"))
+	if err != nil {
+		log.Printf("Error fetching data: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "resource value(s) is invalid")
+	}
+
+	response := &pb.BasisResponse{Records: make([]*pb.Basis, len(records))}
+	for i, record := range records {
+		response.Records[i] = &pb.Basis{
+			Timestamp: timestamppb.New(record.Timestamp),
+			SpotPrice: record.SpotPrice.InexactFloat64(),
+			MarkPrice: record.MarkPrice.InexactFloat64(),
+			Basis:     record.Basis.InexactFloat64(),
+			BasisRate: record.BasisRate.InexactFloat64(),
+		}
+	}
+	return response, nil
+}
+
+// mapRequestToDTO applies the defaults of GetOHLC, the records can be sorted by timestamp or by the given value field
+func (handler *FuturesHandler) mapRequestToDTO(req *pb.FuturesRequest, valueField string) dto.FuturesRequestDto {
+	dto := dto.FuturesRequestDto{
+		Currency: instrumentKey(req.Currency, req.Quote),
+	}
+
+	if req.StartTime != nil {
+		startTime := req.StartTime.AsTime()
+		dto.StartTime = &startTime
+	}
+
+	if req.EndTime != nil {
+		endTime := req.EndTime.AsTime()
+		dto.EndTime = &endTime
+	}
+
+	if req.Limit > 0 {
+		dto.Limit = req.Limit
+	} else {
+		dto.Limit = int32(config.DEFAULT_LIMIT)
+	}
+
+	if req.SortField != "" && slices.Contains([]string{"timestamp", valueField}, req.SortField) {
+		dto.SortField = req.SortField
+	} else {
+		dto.SortField = config.DEFAULT_DATA_REQUEST_SORT_FIELD
+	}
+	if req.SortOrder != "" && (req.SortOrder == "ASC" || req.SortOrder == "DESC") {
+		dto.SortOrder = req.SortOrder
+	} else {
+		dto.SortOrder = config.DEFAULT_DATA_REQUEST_SORT_ORDER
+	}
+
+	return dto
+}
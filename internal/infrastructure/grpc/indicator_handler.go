@@ -37,8 +37,8 @@ func (handler *IndicatorHandler) GetIndicators(ctx context.Context, request *pb.
 }
 func (handler *IndicatorHandler) mapRequestToDTO(req *pb.IndicatorRequest) dto.IndicatorRequestDto {
 	dto := dto.IndicatorRequestDto{
-		Currency:  req.Currency,
-		Timeframe: req.Timeframe,
+		Currency:  instrumentKey(req.Currency, req.Quote),
+		Timeframe: sourceTimeframe(req.Source, req.Timeframe),
 	}
 
 	if req.StartTime != nil {
@@ -0,0 +1,16 @@
+package grpc
+
+import "github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+
+// instrumentKey turns the currency ("btc", "eth/btc") and optional quote of a request into an instrument key,
+// an invalid value is passed on unchanged for the validator to reject
+func instrumentKey(currency string, quote string) string {
+	parsed, err := instrument.Parse(currency)
+	if err != nil {
+		return currency
+	}
+	if quote != "" {
+		parsed = instrument.New(parsed.Base, quote)
+	}
+	return parsed.Key()
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/chyngyz-sydykov/marketpulse/config"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
 	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
 	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -38,8 +39,8 @@ func (handler *MarketDataHandler) GetOHLC(ctx context.Context, request *pb.OHLCR
 
 func (handler *MarketDataHandler) mapRequestToDTO(req *pb.OHLCRequest) dto.OHLCRequestDto {
 	dto := dto.OHLCRequestDto{
-		Currency:  req.Currency,
-		Timeframe: req.Timeframe,
+		Currency:  instrumentKey(req.Currency, req.Quote),
+		Timeframe: sourceTimeframe(req.Source, req.Timeframe),
 	}
 
 	// Handle optional timestamp fields
@@ -60,7 +61,7 @@ func (handler *MarketDataHandler) mapRequestToDTO(req *pb.OHLCRequest) dto.OHLCR
 		dto.Limit = int32(config.DEFAULT_LIMIT)
 	}
 
-	if req.SortField != "" && slices.Contains([]string{"timestamp", "volume"}, req.SortField) {
+	if req.SortField != "" && slices.Contains([]string{"timestamp", "volume", "quote_volume", "trade_count"}, req.SortField) {
 		dto.SortField = req.SortField
 	} else {
 		dto.SortField = config.DEFAULT_DATA_REQUEST_SORT_FIELD
@@ -76,20 +77,56 @@ func (handler *MarketDataHandler) mapRequestToDTO(req *pb.OHLCRequest) dto.OHLCR
 func (handler *MarketDataHandler) mapDtoToResponse(records []dto.DataDto) *pb.OHLCResponse {
 	ohlcRecords := make([]*pb.OHLCData, len(records))
 	for i, record := range records {
-		ohlcRecords[i] = &pb.OHLCData{
-			Timestamp:  timestamppb.New(record.Timestamp),
-			Timeframe:  record.Timeframe,
-			Currency:   record.Symbol,
-			Open:       record.Open,
-			High:       record.High,
-			Low:        record.Low,
-			Close:      record.Close,
-			Volume:     record.Volume,
-			Trend:      record.Trend,
-			IsComplete: record.IsComplete,
-		}
+		ohlcRecords[i] = mapDataToOHLC(record)
 	}
 	return &pb.OHLCResponse{
 		Records: ohlcRecords,
 	}
 }
+
+func mapDataToOHLC(record dto.DataDto) *pb.OHLCData {
+	return &pb.OHLCData{
+		Timestamp:  timestamppb.New(record.Timestamp),
+		Timeframe:  record.Timeframe,
+		Currency:   record.Symbol,
+		Open:       record.Open.InexactFloat64(),
+		High:       record.High.InexactFloat64(),
+		Low:        record.Low.InexactFloat64(),
+		Close:      record.Close.InexactFloat64(),
+		Volume:     record.Volume.InexactFloat64(),
+		Trend:      record.Trend.InexactFloat64(),
+		IsComplete: record.IsComplete,
+
+		QuoteVolume:         record.QuoteVolume.InexactFloat64(),
+		TradeCount:          record.TradeCount,
+		TakerBuyBaseVolume:  record.TakerBuyBaseVolume.InexactFloat64(),
+		TakerBuyQuoteVolume: record.TakerBuyQuoteVolume.InexactFloat64(),
+		Vwap:                record.Vwap.InexactFloat64(),
+		MedianPrice:         record.MedianPrice.InexactFloat64(),
+
+		// exact values for clients that cannot afford the rounding of a double
+		OpenDecimal:                record.Open.String(),
+		HighDecimal:                record.High.String(),
+		LowDecimal:                 record.Low.String(),
+		CloseDecimal:               record.Close.String(),
+		VolumeDecimal:              record.Volume.String(),
+		TrendDecimal:               record.Trend.String(),
+		QuoteVolumeDecimal:         record.QuoteVolume.String(),
+		TakerBuyBaseVolumeDecimal:  record.TakerBuyBaseVolume.String(),
+		TakerBuyQuoteVolumeDecimal: record.TakerBuyQuoteVolume.String(),
+		VwapDecimal:                record.Vwap.String(),
+		MedianPriceDecimal:         record.MedianPrice.String(),
+	}
+}
+
+// sourceTimeframe maps the source selector of a request to the partition it reads from,
+// "composite" reads the candles consolidated across exchanges and an unknown source fails validation
+func sourceTimeframe(source string, timeframe string) string {
+	switch source {
+	case "":
+		return timeframe
+	case config.SOURCE_COMPOSITE:
+		return utils.GetCompositeTimeframe(timeframe)
+	}
+	return ""
+}
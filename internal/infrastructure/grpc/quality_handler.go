@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+	"log"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type QualityHandler struct {
+	MarketService *marketdata.MarketDataService
+}
+
+func NewQualityHandler(marketService *marketdata.MarketDataService) *QualityHandler {
+	return &QualityHandler{
+		MarketService: marketService,
+	}
+}
+
+// GetQuarantine lists the fetched candles that failed the data quality checks, the latest first
+func (handler *QualityHandler) GetQuarantine(ctx context.Context, request *pb.QuarantineRequest) (*pb.QuarantineResponse, error) {
+	records, err := handler.MarketService.GetQuarantined(dto.QuarantineRequestDto{
+		Currency:  instrumentKey(request.Currency, request.Quote),
+		Timeframe: request.Timeframe,
+		Limit:     int(request.Limit),
+	})
+	if err != nil {
+		log.Printf("Error fetching quarantine: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "resource value(s) is invalid")
+	}
+
+	response := &pb.QuarantineResponse{Candles: make([]*pb.QuarantinedCandle, len(records))}
+	for i, record := range records {
+		response.Candles[i] = &pb.QuarantinedCandle{
+			Id:        record.Id,
+			Currency:  record.Currency,
+			Reason:    record.Reason,
+			CreatedAt: timestamppb.New(record.CreatedAt),
+			Data:      mapDataToOHLC(record.Record),
+		}
+	}
+	return response, nil
+}
+
+// ReleaseQuarantine stores a quarantined candle as it is
+func (handler *QualityHandler) ReleaseQuarantine(ctx context.Context, request *pb.ReleaseQuarantineRequest) (*pb.ReleaseQuarantineResponse, error) {
+	record, err := handler.MarketService.ReleaseQuarantined(request.Id)
+	if err != nil {
+		log.Printf("Error releasing quarantined candle: %v", err)
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &pb.ReleaseQuarantineResponse{Data: mapDataToOHLC(*record)}, nil
+}
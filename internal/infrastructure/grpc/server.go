@@ -6,8 +6,16 @@ import (
 	"net"
 
 	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/backfill"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/currency"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/export"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/futures"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/gap"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/orderbook"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/ticker"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/trade"
 	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
 
 	"google.golang.org/grpc"
@@ -20,17 +28,38 @@ type GrpcServer struct {
 	IndicatorService  *indicator.IndicatorService
 	MarketDataHandler *MarketDataHandler
 	IndicatorHandler  *IndicatorHandler
+	AdminHandler      *AdminHandler
+	DepthHandler      *DepthHandler
+	TradeHandler      *TradeHandler
+	FuturesHandler    *FuturesHandler
+	TickerHandler     *TickerHandler
+	QualityHandler    *QualityHandler
+	ExportHandler     *ExportHandler
 }
 
-func NewGrpcService(MarketService *marketdata.MarketDataService, IndicatorService *indicator.IndicatorService) *GrpcServer {
+func NewGrpcService(MarketService *marketdata.MarketDataService, IndicatorService *indicator.IndicatorService, BackfillService *backfill.BackfillService, GapService *gap.GapService, OrderBookService *orderbook.OrderBookService, TradeService *trade.TradeService, FuturesService *futures.FuturesService, TickerService *ticker.TickerService, CurrencyService *currency.CurrencyService, ExportService *export.ExportService) *GrpcServer {
 	MarketDataHandler := NewMarketDataHandler(*MarketService)
 	IndicatorHandler := NewIndicatorHandler(*IndicatorService)
+	AdminHandler := NewAdminHandler(BackfillService, GapService, CurrencyService)
+	DepthHandler := NewDepthHandler(OrderBookService)
+	TradeHandler := NewTradeHandler(TradeService)
+	FuturesHandler := NewFuturesHandler(FuturesService)
+	TickerHandler := NewTickerHandler(TickerService)
+	QualityHandler := NewQualityHandler(MarketService)
+	ExportHandler := NewExportHandler(ExportService)
 
 	return &GrpcServer{
 		MarketService:     MarketService,
 		IndicatorService:  IndicatorService,
 		MarketDataHandler: MarketDataHandler,
 		IndicatorHandler:  IndicatorHandler,
+		AdminHandler:      AdminHandler,
+		DepthHandler:      DepthHandler,
+		TradeHandler:      TradeHandler,
+		FuturesHandler:    FuturesHandler,
+		TickerHandler:     TickerHandler,
+		QualityHandler:    QualityHandler,
+		ExportHandler:     ExportHandler,
 	}
 }
 func (server *GrpcServer) GetOHLC(ctx context.Context, request *pb.OHLCRequest) (*pb.OHLCResponse, error) {
@@ -41,6 +70,74 @@ func (server *GrpcServer) GetIndicators(ctx context.Context, request *pb.Indicat
 	return server.IndicatorHandler.GetIndicators(ctx, request)
 }
 
+func (server *GrpcServer) GetDepth(ctx context.Context, request *pb.DepthRequest) (*pb.DepthResponse, error) {
+	return server.DepthHandler.GetDepth(ctx, request)
+}
+
+func (server *GrpcServer) ReconcileTrades(ctx context.Context, request *pb.ReconcileRequest) (*pb.ReconcileResponse, error) {
+	return server.TradeHandler.ReconcileTrades(ctx, request)
+}
+
+func (server *GrpcServer) GetTicker(ctx context.Context, request *pb.TickerRequest) (*pb.Ticker, error) {
+	return server.TickerHandler.GetTicker(ctx, request)
+}
+
+func (server *GrpcServer) GetTickers(ctx context.Context, request *pb.TickersRequest) (*pb.TickersResponse, error) {
+	return server.TickerHandler.GetTickers(ctx, request)
+}
+
+func (server *GrpcServer) GetFundingRates(ctx context.Context, request *pb.FuturesRequest) (*pb.FundingRateResponse, error) {
+	return server.FuturesHandler.GetFundingRates(ctx, request)
+}
+
+func (server *GrpcServer) GetOpenInterest(ctx context.Context, request *pb.FuturesRequest) (*pb.OpenInterestResponse, error) {
+	return server.FuturesHandler.GetOpenInterest(ctx, request)
+}
+
+func (server *GrpcServer) GetMarkPriceKlines(ctx context.Context, request *pb.FuturesRequest) (*pb.MarkPriceResponse, error) {
+	return server.FuturesHandler.GetMarkPriceKlines(ctx, request)
+}
+
+func (server *GrpcServer) GetBasis(ctx context.Context, request *pb.FuturesRequest) (*pb.BasisResponse, error) {
+	return server.FuturesHandler.GetBasis(ctx, request)
+}
+
+func (server *GrpcServer) GetQuarantine(ctx context.Context, request *pb.QuarantineRequest) (*pb.QuarantineResponse, error) {
+	return server.QualityHandler.GetQuarantine(ctx, request)
+}
+
+func (server *GrpcServer) ReleaseQuarantine(ctx context.Context, request *pb.ReleaseQuarantineRequest) (*pb.ReleaseQuarantineResponse, error) {
+	return server.QualityHandler.ReleaseQuarantine(ctx, request)
+}
+
+func (server *GrpcServer) Backfill(ctx context.Context, request *pb.BackfillRequest) (*pb.BackfillResponse, error) {
+	return server.AdminHandler.Backfill(ctx, request)
+}
+
+func (server *GrpcServer) ScanGaps(ctx context.Context, request *pb.GapScanRequest) (*pb.GapScanResponse, error) {
+	return server.AdminHandler.ScanGaps(ctx, request)
+}
+
+func (server *GrpcServer) GetCurrencies(ctx context.Context, request *pb.CurrenciesRequest) (*pb.CurrenciesResponse, error) {
+	return server.AdminHandler.GetCurrencies(ctx, request)
+}
+
+func (server *GrpcServer) AddCurrency(ctx context.Context, request *pb.AddCurrencyRequest) (*pb.Currency, error) {
+	return server.AdminHandler.AddCurrency(ctx, request)
+}
+
+func (server *GrpcServer) PauseCurrency(ctx context.Context, request *pb.CurrencyRequest) (*pb.Currency, error) {
+	return server.AdminHandler.PauseCurrency(ctx, request)
+}
+
+func (server *GrpcServer) RemoveCurrency(ctx context.Context, request *pb.CurrencyRequest) (*pb.RemoveCurrencyResponse, error) {
+	return server.AdminHandler.RemoveCurrency(ctx, request)
+}
+
+func (server *GrpcServer) ExportData(request *pb.ExportRequest, stream pb.MarketPulse_ExportDataServer) error {
+	return server.ExportHandler.ExportData(request, stream)
+}
+
 func StartGRPCServer(GrpcServer *GrpcServer) {
 	cfg := config.LoadConfig()
 	grpcServer := grpc.NewServer()
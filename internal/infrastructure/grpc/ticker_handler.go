@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"log"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/ticker"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type TickerHandler struct {
+	TickerService *ticker.TickerService
+}
+
+func NewTickerHandler(tickerService *ticker.TickerService) *TickerHandler {
+	return &TickerHandler{
+		TickerService: tickerService,
+	}
+}
+
+// GetTicker returns the latest 24h stats of one currency
+func (handler *TickerHandler) GetTicker(ctx context.Context, request *pb.TickerRequest) (*pb.Ticker, error) {
+	record, err := handler.TickerService.GetLatest(instrumentKey(request.Currency, request.Quote))
+	if err != nil {
+		log.Printf("Error fetching ticker: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "resource value(s) is invalid")
+	}
+	if record == nil {
+		return nil, status.Error(codes.NotFound, "no ticker snapshot yet")
+	}
+	return mapTickerToResponse(record), nil
+}
+
+// GetTickers returns the latest 24h stats of every tracked currency
+func (handler *TickerHandler) GetTickers(ctx context.Context, request *pb.TickersRequest) (*pb.TickersResponse, error) {
+	records, err := handler.TickerService.GetLatestForAll()
+	if err != nil {
+		log.Printf("Error fetching tickers: %v", err)
+		return nil, status.Error(codes.Internal, "tickers are not available")
+	}
+
+	response := &pb.TickersResponse{Tickers: make([]*pb.Ticker, len(records))}
+	for i, record := range records {
+		response.Tickers[i] = mapTickerToResponse(record)
+	}
+	return response, nil
+}
+
+func mapTickerToResponse(record *dto.TickerDto) *pb.Ticker {
+	return &pb.Ticker{
+		Timestamp:          timestamppb.New(record.Timestamp),
+		Currency:           record.Symbol,
+		LastPrice:          record.LastPrice.InexactFloat64(),
+		OpenPrice:          record.OpenPrice.InexactFloat64(),
+		HighPrice:          record.HighPrice.InexactFloat64(),
+		LowPrice:           record.LowPrice.InexactFloat64(),
+		PriceChange:        record.PriceChange.InexactFloat64(),
+		PriceChangePercent: record.PriceChangePercent.InexactFloat64(),
+		WeightedAvgPrice:   record.WeightedAvgPrice.InexactFloat64(),
+		Volume:             record.Volume.InexactFloat64(),
+		QuoteVolume:        record.QuoteVolume.InexactFloat64(),
+		TradeCount:         record.TradeCount,
+	}
+}
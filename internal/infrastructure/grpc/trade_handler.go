@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"log"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/trade"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type TradeHandler struct {
+	TradeService *trade.TradeService
+}
+
+func NewTradeHandler(tradeService *trade.TradeService) *TradeHandler {
+	return &TradeHandler{
+		TradeService: tradeService,
+	}
+}
+
+// ReconcileTrades compares 1h candles built from aggregate trades with binance klines
+func (handler *TradeHandler) ReconcileTrades(ctx context.Context, request *pb.ReconcileRequest) (*pb.ReconcileResponse, error) {
+	if request.StartTime == nil || request.EndTime == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_time and end_time are required")
+	}
+
+	report, err := handler.TradeService.Reconcile(instrumentKey(request.Currency, ""), request.StartTime.AsTime(), request.EndTime.AsTime())
+	if err != nil {
+		log.Printf("Error reconciling trades: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "resource value(s) is invalid")
+	}
+	return handler.mapReportToResponse(report), nil
+}
+
+func (handler *TradeHandler) mapReportToResponse(report *dto.ReconciliationReportDto) *pb.ReconcileResponse {
+	rows := make([]*pb.ReconcileRow, len(report.Rows))
+	for i, row := range report.Rows {
+		rows[i] = &pb.ReconcileRow{
+			Timestamp:  timestamppb.New(row.Timestamp),
+			Mismatches: row.Mismatches,
+		}
+		if row.TradeCandle != nil {
+			rows[i].TradeCandle = mapDataToOHLC(*row.TradeCandle)
+		}
+		if row.Kline != nil {
+			rows[i].Kline = mapDataToOHLC(*row.Kline)
+		}
+	}
+	return &pb.ReconcileResponse{
+		Currency:        report.Currency,
+		StartTime:       timestamppb.New(report.StartTime),
+		EndTime:         timestamppb.New(report.EndTime),
+		MatchedHours:    int32(report.MatchedHours),
+		MismatchedHours: int32(report.MismatchedHours),
+		Rows:            rows,
+	}
+}
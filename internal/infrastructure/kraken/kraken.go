@@ -0,0 +1,237 @@
+package kraken
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/resilience"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+// kraken only keeps the latest 720 candles of every interval
+const maxKlineLimit = 720
+
+// interval in minutes
+var intervalByTimeframe = map[string]int{
+	"1h": 60,
+	"4h": 240,
+	"1d": 1440,
+}
+
+// kraken names some assets differently
+var assetAliases = map[string]string{
+	"BTC":  "XBT",
+	"DOGE": "XDG",
+}
+
+type KrakenSource struct {
+	baseUrl string
+	client  *http.Client
+}
+
+type response struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+type tickerResponse struct {
+	Open   string   `json:"o"`
+	High   []string `json:"h"` // [ today, last 24 hours ]
+	Low    []string `json:"l"`
+	Close  []string `json:"c"` // [ price, lot volume ]
+	Volume []string `json:"v"`
+}
+
+func NewKrakenSource() *KrakenSource {
+	cfg := config.LoadConfig()
+	return &KrakenSource{
+		baseUrl: cfg.KrakenBaseAPIUrl,
+		client:  &http.Client{Transport: resilience.NewTransport(config.EXCHANGE_KRAKEN, http.DefaultTransport)},
+	}
+}
+
+func (source *KrakenSource) Name() string {
+	return config.EXCHANGE_KRAKEN
+}
+
+func (source *KrakenSource) Symbol(currency string) string {
+	pair := instrument.FromKey(currency)
+	return krakenAsset(pair.Base) + krakenAsset(pair.Quote)
+}
+
+func krakenAsset(asset string) string {
+	asset = strings.ToUpper(asset)
+	if alias, ok := assetAliases[asset]; ok {
+		return alias
+	}
+	return asset
+}
+
+func (source *KrakenSource) MaxKlineLimit() int {
+	return maxKlineLimit
+}
+
+// FetchKline retrieves the latest klines, the last one is still forming
+func (source *KrakenSource) FetchKline(currency string, interval string, limit int) ([]*dto.DataDto, error) {
+	records, err := source.fetchOHLC(currency, interval, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("unexpected response format")
+	}
+	if len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records, nil
+}
+
+// FetchKlineRange retrieves klines between startTime and endTime that are still kept by kraken
+func (source *KrakenSource) FetchKlineRange(currency string, interval string, startTime time.Time, endTime time.Time, limit int) ([]*dto.DataDto, error) {
+	since := startTime.Add(-1 * time.Second)
+	records, err := source.fetchOHLC(currency, interval, &since)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*dto.DataDto, 0, len(records))
+	for _, record := range records {
+		if record.Timestamp.Before(startTime) || record.Timestamp.After(endTime) {
+			continue
+		}
+		filtered = append(filtered, record)
+		if len(filtered) == limit {
+			break
+		}
+	}
+	return filtered, nil
+}
+
+// FetchTicker retrieves the 24h stats of the pair
+func (source *KrakenSource) FetchTicker(currency string) (*dto.DataDto, error) {
+	query := url.Values{}
+	query.Set("pair", source.Symbol(currency))
+
+	result, err := source.get("Ticker", query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, payload := range result {
+		var ticker tickerResponse
+		if err := json.Unmarshal(payload, &ticker); err != nil {
+			return nil, err
+		}
+		if len(ticker.High) < 2 || len(ticker.Low) < 2 || len(ticker.Close) < 1 || len(ticker.Volume) < 2 {
+			return nil, fmt.Errorf("unexpected response format")
+		}
+		return &dto.DataDto{
+			Symbol:    source.Symbol(currency),
+			Timestamp: time.Now().UTC(),
+			Timeframe: config.ONE_HOUR,
+			Open:      utils.ParseDecimal(ticker.Open),
+			High:      utils.ParseDecimal(ticker.High[1]),
+			Low:       utils.ParseDecimal(ticker.Low[1]),
+			Close:     utils.ParseDecimal(ticker.Close[0]),
+			Volume:    utils.ParseDecimal(ticker.Volume[1]),
+		}, nil
+	}
+	return nil, fmt.Errorf("unexpected response format")
+}
+
+func (source *KrakenSource) fetchOHLC(currency string, interval string, since *time.Time) ([]*dto.DataDto, error) {
+	minutes, ok := intervalByTimeframe[interval]
+	if !ok {
+		return nil, fmt.Errorf("kraken does not support interval: %s", interval)
+	}
+
+	query := url.Values{}
+	query.Set("pair", source.Symbol(currency))
+	query.Set("interval", strconv.Itoa(minutes))
+	if since != nil {
+		query.Set("since", strconv.FormatInt(since.Unix(), 10))
+	}
+
+	result, err := source.get("OHLC", query)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(minutes) * time.Minute
+	now := time.Now()
+	for key, payload := range result {
+		// the result holds the candles under the pair name next to the "last" cursor
+		if key == "last" {
+			continue
+		}
+		var candles [][]interface{}
+		if err := json.Unmarshal(payload, &candles); err != nil {
+			return nil, err
+		}
+
+		records := make([]*dto.DataDto, 0, len(candles))
+		for _, candle := range candles {
+			// [ time, open, high, low, close, vwap, volume, count ]
+			if len(candle) < 8 {
+				return nil, fmt.Errorf("unexpected response format")
+			}
+			openTime, ok := candle[0].(float64)
+			if !ok {
+				return nil, fmt.Errorf("unexpected response format: time is %T", candle[0])
+			}
+			values, err := utils.ParseDecimalFields(candle, 1, 2, 3, 4, 6)
+			if err != nil {
+				return nil, err
+			}
+			timestamp := time.Unix(int64(openTime), 0).UTC()
+			records = append(records, &dto.DataDto{
+				Symbol:     source.Symbol(currency),
+				Timestamp:  timestamp,
+				Timeframe:  interval,
+				Open:       values[0],
+				High:       values[1],
+				Low:        values[2],
+				Close:      values[3],
+				Volume:     values[4],
+				IsComplete: !timestamp.Add(duration).After(now),
+			})
+		}
+		return records, nil
+	}
+	return nil, fmt.Errorf("unexpected response format")
+}
+
+func (source *KrakenSource) get(path string, query url.Values) (map[string]json.RawMessage, error) {
+	resp, err := source.client.Get(source.baseUrl + path + "?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kraken API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var krakenResponse response
+	if err := json.Unmarshal(body, &krakenResponse); err != nil {
+		return nil, err
+	}
+	if len(krakenResponse.Error) > 0 {
+		return nil, fmt.Errorf("kraken API returned error: %s", strings.Join(krakenResponse.Error, ", "))
+	}
+	return krakenResponse.Result, nil
+}
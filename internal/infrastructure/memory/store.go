@@ -0,0 +1,315 @@
+package memory
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+)
+
+// DB is the in-memory counterpart of database.DB used by STORAGE_BACKEND=memory,
+// it holds the data_<key>_<timeframe> and indicator_<key>_<timeframe> tables, the data quarantine, the data gaps, the backfill checkpoints and the depth_<currency> tables
+var DB = NewStore()
+
+// Store keeps one table per currency and timeframe, the rows of a table are unique by timestamp like the postgres tables.
+// The ordered rows of a table are cached until the table is written again.
+type Store struct {
+	mu               sync.RWMutex
+	candles          map[string]map[time.Time]dto.DataDto
+	indicators       map[string]map[time.Time]dto.IndicatorDto
+	sortedCandles    map[string][]dto.DataDto
+	sortedIndicators map[string][]dto.IndicatorDto
+	quarantine       map[int64]dto.QuarantineDto
+	gaps             map[string][]dto.GapDto
+	checkpoints      map[string]dto.BackfillCheckpointDto
+	depth            map[string]map[time.Time]dto.DepthSnapshotDto
+	lastId           int64
+}
+
+func NewStore() *Store {
+	return &Store{
+		candles:          make(map[string]map[time.Time]dto.DataDto),
+		indicators:       make(map[string]map[time.Time]dto.IndicatorDto),
+		sortedCandles:    make(map[string][]dto.DataDto),
+		sortedIndicators: make(map[string][]dto.IndicatorDto),
+		quarantine:       make(map[int64]dto.QuarantineDto),
+		gaps:             make(map[string][]dto.GapDto),
+		checkpoints:      make(map[string]dto.BackfillCheckpointDto),
+		depth:            make(map[string]map[time.Time]dto.DepthSnapshotDto),
+	}
+}
+
+// Reset drops every table
+func (store *Store) Reset() {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.candles = make(map[string]map[time.Time]dto.DataDto)
+	store.indicators = make(map[string]map[time.Time]dto.IndicatorDto)
+	store.sortedCandles = make(map[string][]dto.DataDto)
+	store.sortedIndicators = make(map[string][]dto.IndicatorDto)
+	store.quarantine = make(map[int64]dto.QuarantineDto)
+	store.gaps = make(map[string][]dto.GapDto)
+	store.checkpoints = make(map[string]dto.BackfillCheckpointDto)
+	store.depth = make(map[string]map[time.Time]dto.DepthSnapshotDto)
+	store.lastId = 0
+}
+
+// Candles returns a copy of the table ordered by timestamp
+func (store *Store) Candles(currency string, timeframe string) []dto.DataDto {
+	return slices.Clone(store.orderedCandles(tableName(currency, timeframe)))
+}
+
+// CandlesBefore returns up to limit rows preceding timestamp, the latest first
+func (store *Store) CandlesBefore(currency string, timeframe string, timestamp time.Time, limit int) []dto.DataDto {
+	candles := store.orderedCandles(tableName(currency, timeframe))
+	end, _ := slices.BinarySearchFunc(candles, timestamp, func(record dto.DataDto, target time.Time) int { return record.Timestamp.Compare(target) })
+	records := make([]dto.DataDto, 0, min(limit, end))
+	for i := end - 1; i >= 0 && len(records) < limit; i-- {
+		records = append(records, candles[i])
+	}
+	return records
+}
+
+func (store *Store) orderedCandles(name string) []dto.DataDto {
+	store.mu.RLock()
+	sorted, ok := store.sortedCandles[name]
+	store.mu.RUnlock()
+	if ok {
+		return sorted
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if sorted, ok = store.sortedCandles[name]; !ok {
+		sorted = sortedByTimestamp(store.candles[name], func(record dto.DataDto) time.Time { return record.Timestamp })
+		store.sortedCandles[name] = sorted
+	}
+	return sorted
+}
+
+// UpsertCandles inserts the records or replaces the rows with the same timestamp, the id of a replaced row is kept
+func (store *Store) UpsertCandles(currency string, timeframe string, records []*dto.DataDto) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	name := tableName(currency, timeframe)
+	if store.candles[name] == nil {
+		store.candles[name] = make(map[time.Time]dto.DataDto)
+	}
+	table := store.candles[name]
+	delete(store.sortedCandles, name)
+	for _, record := range records {
+		timestamp := record.Timestamp.UTC()
+		row := *record
+		row.Timestamp = timestamp
+		if existing, ok := table[timestamp]; ok {
+			row.Id = existing.Id
+		} else {
+			row.Id = store.nextId()
+		}
+		table[timestamp] = row
+	}
+}
+
+// DeleteCandles removes the rows matching the condition and returns how many were removed
+func (store *Store) DeleteCandles(currency string, timeframe string, condition func(record dto.DataDto) bool) int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	name := tableName(currency, timeframe)
+	table := store.candles[name]
+	delete(store.sortedCandles, name)
+	deleted := 0
+	for timestamp, record := range table {
+		if condition(record) {
+			delete(table, timestamp)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// Indicators returns a copy of the table ordered by timestamp
+func (store *Store) Indicators(currency string, timeframe string) []dto.IndicatorDto {
+	return slices.Clone(store.orderedIndicators(tableName(currency, timeframe)))
+}
+
+// LastIndicator returns the latest row of the table
+func (store *Store) LastIndicator(currency string, timeframe string) (dto.IndicatorDto, bool) {
+	indicators := store.orderedIndicators(tableName(currency, timeframe))
+	if len(indicators) == 0 {
+		return dto.IndicatorDto{}, false
+	}
+	return indicators[len(indicators)-1], true
+}
+
+func (store *Store) orderedIndicators(name string) []dto.IndicatorDto {
+	store.mu.RLock()
+	sorted, ok := store.sortedIndicators[name]
+	store.mu.RUnlock()
+	if ok {
+		return sorted
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if sorted, ok = store.sortedIndicators[name]; !ok {
+		sorted = sortedByTimestamp(store.indicators[name], func(record dto.IndicatorDto) time.Time { return record.Timestamp })
+		store.sortedIndicators[name] = sorted
+	}
+	return sorted
+}
+
+// UpsertIndicators inserts the records or replaces the rows with the same timestamp, the id of a replaced row is kept
+func (store *Store) UpsertIndicators(currency string, timeframe string, records []*dto.IndicatorDto) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	name := tableName(currency, timeframe)
+	if store.indicators[name] == nil {
+		store.indicators[name] = make(map[time.Time]dto.IndicatorDto)
+	}
+	table := store.indicators[name]
+	delete(store.sortedIndicators, name)
+	for _, record := range records {
+		timestamp := record.Timestamp.UTC()
+		row := *record
+		row.Timestamp = timestamp
+		row.DataTimestamp = record.DataTimestamp.UTC()
+		if existing, ok := table[timestamp]; ok {
+			row.Id = existing.Id
+		} else {
+			row.Id = store.nextId()
+		}
+		table[timestamp] = row
+	}
+}
+
+// DeleteIndicators removes the rows matching the condition and returns how many were removed
+func (store *Store) DeleteIndicators(currency string, timeframe string, condition func(record dto.IndicatorDto) bool) int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	name := tableName(currency, timeframe)
+	table := store.indicators[name]
+	delete(store.sortedIndicators, name)
+	deleted := 0
+	for timestamp, record := range table {
+		if condition(record) {
+			delete(table, timestamp)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// Quarantine keeps the latest rejected version of a candle, unique by currency, timeframe and timestamp
+func (store *Store) Quarantine(record dto.QuarantineDto) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	record.Timestamp = record.Timestamp.UTC()
+	for id, existing := range store.quarantine {
+		if existing.Currency == record.Currency && existing.Timeframe == record.Timeframe && existing.Timestamp.Equal(record.Timestamp) {
+			record.Id = id
+			store.quarantine[id] = record
+			return
+		}
+	}
+	store.lastId++
+	record.Id = store.lastId
+	store.quarantine[record.Id] = record
+}
+
+// Quarantined returns a copy of the quarantined candles ordered by timestamp
+func (store *Store) Quarantined() []dto.QuarantineDto {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return sortedByTimestamp(store.quarantine, func(record dto.QuarantineDto) time.Time { return record.Timestamp })
+}
+
+func (store *Store) QuarantinedById(id int64) (dto.QuarantineDto, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	record, ok := store.quarantine[id]
+	return record, ok
+}
+
+func (store *Store) DeleteQuarantined(id int64) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.quarantine, id)
+}
+
+// Gaps returns a copy of the gaps recorded for the currency and timeframe
+func (store *Store) Gaps(currency string, timeframe string) []dto.GapDto {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return slices.Clone(store.gaps[tableName(currency, timeframe)])
+}
+
+// ReplaceGaps replaces the gaps recorded for the currency and timeframe, ordered by start time
+func (store *Store) ReplaceGaps(currency string, timeframe string, gaps []dto.GapDto) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	gaps = slices.Clone(gaps)
+	slices.SortFunc(gaps, func(a, b dto.GapDto) int { return a.StartTime.Compare(b.StartTime) })
+	store.gaps[tableName(currency, timeframe)] = gaps
+}
+
+// Checkpoint returns the backfill checkpoint of the currency and timeframe
+func (store *Store) Checkpoint(currency string, timeframe string) (dto.BackfillCheckpointDto, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	checkpoint, ok := store.checkpoints[tableName(currency, timeframe)]
+	return checkpoint, ok
+}
+
+// SaveCheckpoint inserts the backfill checkpoint or replaces the one of the same currency and timeframe
+func (store *Store) SaveCheckpoint(checkpoint dto.BackfillCheckpointDto) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.checkpoints[tableName(checkpoint.Currency, checkpoint.Timeframe)] = checkpoint
+}
+
+// DepthSnapshots returns a copy of the depth table of the currency ordered by timestamp
+func (store *Store) DepthSnapshots(currency string) []dto.DepthSnapshotDto {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	return sortedByTimestamp(store.depth[currency], func(record dto.DepthSnapshotDto) time.Time { return record.Timestamp })
+}
+
+// UpsertDepthSnapshot inserts the snapshot or replaces the one of the same timestamp, keeping its id
+func (store *Store) UpsertDepthSnapshot(currency string, snapshot dto.DepthSnapshotDto) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	table, ok := store.depth[currency]
+	if !ok {
+		table = make(map[time.Time]dto.DepthSnapshotDto)
+		store.depth[currency] = table
+	}
+	timestamp := snapshot.Timestamp.UTC()
+	if existing, ok := table[timestamp]; ok {
+		snapshot.Id = existing.Id
+	} else {
+		snapshot.Id = store.nextId()
+	}
+	snapshot.Timestamp = timestamp
+	table[timestamp] = snapshot
+}
+
+// nextId mimics BIGSERIAL, the caller holds the lock
+func (store *Store) nextId() *int {
+	store.lastId++
+	id := int(store.lastId)
+	return &id
+}
+
+func tableName(currency string, timeframe string) string {
+	return currency + "_" + timeframe
+}
+
+func sortedByTimestamp[K comparable, V any](table map[K]V, timestamp func(V) time.Time) []V {
+	records := make([]V, 0, len(table))
+	for _, record := range table {
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b V) int { return timestamp(a).Compare(timestamp(b)) })
+	return records
+}
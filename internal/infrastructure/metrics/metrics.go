@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"expvar"
+	"log"
+	"net/http"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+)
+
+// StartMetricsServer exposes the expvar metrics (ex: binance_used_weight_1m) on :APPLICATION_PORT/debug/vars
+func StartMetricsServer() {
+	cfg := config.LoadConfig()
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	log.Printf("Metrics server is running on port %s", cfg.AppPort)
+	if err := http.ListenAndServe(":"+cfg.AppPort, mux); err != nil {
+		log.Printf("Failed to serve metrics: %v", err)
+	}
+}
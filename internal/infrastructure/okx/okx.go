@@ -0,0 +1,198 @@
+package okx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/resilience"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+)
+
+// okx returns at most 100 candles per history request
+const maxKlineLimit = 100
+
+var barByTimeframe = map[string]string{
+	"1h": "1H",
+	"4h": "4H",
+	"1d": "1Dutc", // "1D" is aligned to Hong Kong time
+}
+
+type OkxSource struct {
+	baseUrl string
+	client  *http.Client
+}
+
+type response struct {
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data json.RawMessage `json:"data"`
+}
+
+type tickerResponse struct {
+	Last    string `json:"last"`
+	Open24h string `json:"open24h"`
+	High24h string `json:"high24h"`
+	Low24h  string `json:"low24h"`
+	Vol24h  string `json:"vol24h"`
+}
+
+func NewOkxSource() *OkxSource {
+	cfg := config.LoadConfig()
+	return &OkxSource{
+		baseUrl: cfg.OkxBaseAPIUrl,
+		client:  &http.Client{Transport: resilience.NewTransport(config.EXCHANGE_OKX, http.DefaultTransport)},
+	}
+}
+
+func (source *OkxSource) Name() string {
+	return config.EXCHANGE_OKX
+}
+
+func (source *OkxSource) Symbol(currency string) string {
+	return instrument.FromKey(currency).SymbolWithSeparator("-")
+}
+
+func (source *OkxSource) MaxKlineLimit() int {
+	return maxKlineLimit
+}
+
+// FetchKline retrieves the latest klines, the last one may still be forming
+func (source *OkxSource) FetchKline(currency string, interval string, limit int) ([]*dto.DataDto, error) {
+	bar, ok := barByTimeframe[interval]
+	if !ok {
+		return nil, fmt.Errorf("okx does not support interval: %s", interval)
+	}
+
+	query := url.Values{}
+	query.Set("instId", source.Symbol(currency))
+	query.Set("bar", bar)
+	query.Set("limit", strconv.Itoa(min(limit, maxKlineLimit)))
+
+	records, err := source.fetchCandles("market/candles", currency, interval, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("unexpected response format")
+	}
+	return records, nil
+}
+
+func (source *OkxSource) FetchKlineRange(currency string, interval string, startTime time.Time, endTime time.Time, limit int) ([]*dto.DataDto, error) {
+	bar, ok := barByTimeframe[interval]
+	if !ok {
+		return nil, fmt.Errorf("okx does not support interval: %s", interval)
+	}
+	limit = min(limit, maxKlineLimit)
+	duration := time.Duration(config.HoursByTimeframe[interval]) * time.Hour
+	// okx pages backwards from "after", so the page has to end where "limit" candles after startTime end
+	if lastAllowed := startTime.Add(time.Duration(limit-1) * duration); endTime.After(lastAllowed) {
+		endTime = lastAllowed
+	}
+
+	// "before" and "after" are exclusive
+	query := url.Values{}
+	query.Set("instId", source.Symbol(currency))
+	query.Set("bar", bar)
+	query.Set("before", strconv.FormatInt(startTime.UnixMilli()-1, 10))
+	query.Set("after", strconv.FormatInt(endTime.UnixMilli()+1, 10))
+	query.Set("limit", strconv.Itoa(limit))
+
+	return source.fetchCandles("market/history-candles", currency, interval, query)
+}
+
+// FetchTicker retrieves the 24h stats of the instrument
+func (source *OkxSource) FetchTicker(currency string) (*dto.DataDto, error) {
+	query := url.Values{}
+	query.Set("instId", source.Symbol(currency))
+
+	var tickers []tickerResponse
+	if err := source.get("market/ticker", query, &tickers); err != nil {
+		return nil, err
+	}
+	if len(tickers) == 0 {
+		return nil, fmt.Errorf("unexpected response format")
+	}
+
+	return &dto.DataDto{
+		Symbol:    source.Symbol(currency),
+		Timestamp: time.Now().UTC(),
+		Timeframe: config.ONE_HOUR,
+		Open:      utils.ParseDecimal(tickers[0].Open24h),
+		High:      utils.ParseDecimal(tickers[0].High24h),
+		Low:       utils.ParseDecimal(tickers[0].Low24h),
+		Close:     utils.ParseDecimal(tickers[0].Last),
+		Volume:    utils.ParseDecimal(tickers[0].Vol24h),
+	}, nil
+}
+
+func (source *OkxSource) fetchCandles(path string, currency string, interval string, query url.Values) ([]*dto.DataDto, error) {
+	var candles [][]string
+	if err := source.get(path, query, &candles); err != nil {
+		return nil, err
+	}
+
+	records := make([]*dto.DataDto, 0, len(candles))
+	for _, candle := range candles {
+		// [ ts, open, high, low, close, vol, volCcy, volCcyQuote, confirm ]
+		if len(candle) < 9 {
+			return nil, fmt.Errorf("unexpected response format")
+		}
+		openTime, err := strconv.ParseInt(candle[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected response format")
+		}
+		records = append(records, &dto.DataDto{
+			Symbol:     source.Symbol(currency),
+			Timestamp:  time.UnixMilli(openTime).UTC(),
+			Timeframe:  interval,
+			Open:       utils.ParseDecimal(candle[1]),
+			High:       utils.ParseDecimal(candle[2]),
+			Low:        utils.ParseDecimal(candle[3]),
+			Close:      utils.ParseDecimal(candle[4]),
+			Volume:     utils.ParseDecimal(candle[5]),
+			IsComplete: candle[8] == "1",
+		})
+	}
+
+	// okx returns the newest candle first
+	slices.SortFunc(records, func(a, b *dto.DataDto) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return records, nil
+}
+
+func (source *OkxSource) get(path string, query url.Values, target any) error {
+	resp, err := source.client.Get(source.baseUrl + path + "?" + query.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("okx API returned status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var okxResponse response
+	if err := json.Unmarshal(body, &okxResponse); err != nil {
+		return err
+	}
+	if okxResponse.Code != "0" {
+		return fmt.Errorf("okx API returned error %s: %s", okxResponse.Code, okxResponse.Msg)
+	}
+	return json.Unmarshal(okxResponse.Data, target)
+}
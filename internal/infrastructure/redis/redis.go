@@ -24,6 +24,8 @@ type Event struct {
 type RedisServiceInterface interface {
 	PublishEvent(ctx context.Context, eventName, source string) error
 	SubscribeToEvent(ctx context.Context, eventName string, handler func(event Event))
+	Set(ctx context.Context, key string, value string, expiration time.Duration) error
+	Get(ctx context.Context, key string) (string, error)
 }
 
 type RedisService struct {
@@ -72,6 +74,27 @@ func (r *RedisService) SubscribeToEvent(ctx context.Context, eventName string, h
 	}
 }
 
+// Set stores a value under the key, an expiration of 0 keeps it until it is overwritten
+func (r *RedisService) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
+	err := r.client.Set(ctx, key, value, expiration).Err()
+	if err != nil {
+		return fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get returns the value stored under the key, or an empty string when the key does not exist
+func (r *RedisService) Get(ctx context.Context, key string) (string, error) {
+	value, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	return value, nil
+}
+
 func ConnectRedis() error {
 	cfg := config.LoadConfig()
 	dsn := fmt.Sprintf("%s:%s", cfg.RedisHost, cfg.RedisPort)
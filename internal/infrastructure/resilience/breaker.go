@@ -0,0 +1,138 @@
+package resilience
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half-open"
+)
+
+// StateChangeListener is notified when a breaker opens or closes
+type StateChangeListener func(name string, state State)
+
+var (
+	breakers      = make(map[string]*CircuitBreaker)
+	breakersMu    sync.Mutex
+	stateListener StateChangeListener
+)
+
+// CircuitBreaker stops calls to an exchange after FailureThreshold consecutive failures.
+// After OpenTimeout one trial call is let through, its result closes or reopens the breaker.
+type CircuitBreaker struct {
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// GetCircuitBreaker returns the breaker shared by every client of the exchange
+func GetCircuitBreaker(name string) *CircuitBreaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	if breaker, ok := breakers[name]; ok {
+		return breaker
+	}
+	cfg := config.LoadConfig()
+	breaker := &CircuitBreaker{
+		name:             name,
+		failureThreshold: cfg.CircuitBreakerThreshold,
+		openTimeout:      time.Duration(cfg.CircuitBreakerOpenSeconds) * time.Second,
+		state:            StateClosed,
+	}
+	breakers[name] = breaker
+	return breaker
+}
+
+// SetStateChangeListener registers the listener notified by every breaker
+func SetStateChangeListener(listener StateChangeListener) {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	stateListener = listener
+}
+
+// Allow reports whether a call may go through
+func (breaker *CircuitBreaker) Allow() error {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	switch breaker.state {
+	case StateOpen:
+		if time.Since(breaker.openedAt) < breaker.openTimeout {
+			return ErrCircuitOpen
+		}
+		breaker.state = StateHalfOpen
+		breaker.trial = true
+		return nil
+	case StateHalfOpen:
+		// only the trial call is let through
+		if breaker.trial {
+			return ErrCircuitOpen
+		}
+		breaker.trial = true
+	}
+	return nil
+}
+
+func (breaker *CircuitBreaker) Success() {
+	breaker.mu.Lock()
+	changed := breaker.state != StateClosed
+	breaker.state = StateClosed
+	breaker.failures = 0
+	breaker.trial = false
+	breaker.mu.Unlock()
+
+	if changed {
+		breaker.notify(StateClosed)
+	}
+}
+
+func (breaker *CircuitBreaker) Failure() {
+	breaker.mu.Lock()
+	breaker.failures++
+	opened := breaker.state == StateHalfOpen || (breaker.state == StateClosed && breaker.failures >= breaker.failureThreshold)
+	if opened {
+		breaker.state = StateOpen
+		breaker.openedAt = time.Now()
+		breaker.trial = false
+	}
+	breaker.mu.Unlock()
+
+	if opened {
+		breaker.notify(StateOpen)
+	}
+}
+
+func (breaker *CircuitBreaker) State() State {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+	return breaker.state
+}
+
+func (breaker *CircuitBreaker) notify(state State) {
+	log.Printf(config.COLOR_YELLOW+"%s circuit breaker %s"+config.COLOR_RESET, breaker.name, state)
+
+	breakersMu.Lock()
+	listener := stateListener
+	breakersMu.Unlock()
+	if listener != nil {
+		listener(breaker.name, state)
+	}
+}
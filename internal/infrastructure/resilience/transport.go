@@ -0,0 +1,139 @@
+package resilience
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+)
+
+// Limiter holds an attempt back until the exchange accepts it, e.g. while a weight budget is spent or a ban is running
+type Limiter interface {
+	Wait(req *http.Request) error
+}
+
+// Transport retries failed exchange calls with exponential backoff and jitter,
+// bounds every attempt with a timeout and short-circuits calls while the exchange is down.
+// The wait of the limiter is not part of the attempt: it is neither bounded by the timeout nor counted against the breaker.
+type Transport struct {
+	RealTransport http.RoundTripper
+	Limiter       Limiter
+	Breaker       *CircuitBreaker
+	Retries       int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	Timeout       time.Duration
+}
+
+// NewTransport wraps the transport with the retry policy from the configuration and the breaker of the exchange
+func NewTransport(name string, realTransport http.RoundTripper) *Transport {
+	cfg := config.LoadConfig()
+	return &Transport{
+		RealTransport: realTransport,
+		Breaker:       GetCircuitBreaker(name),
+		Retries:       cfg.HttpRetries,
+		BaseDelay:     time.Duration(cfg.HttpRetryBaseDelay) * time.Millisecond,
+		MaxDelay:      time.Duration(cfg.HttpRetryMaxDelay) * time.Millisecond,
+		Timeout:       time.Duration(cfg.HttpRequestTimeout) * time.Second,
+	}
+}
+
+func (transport *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var resp *http.Response
+	var err error
+
+	for attempt := 0; ; attempt++ {
+		if transport.Limiter != nil {
+			if err := transport.Limiter.Wait(req); err != nil {
+				return nil, err
+			}
+		}
+		if err := transport.Breaker.Allow(); err != nil {
+			return nil, err
+		}
+
+		resp, err = transport.attempt(req)
+		if isFailure(resp, err) {
+			transport.Breaker.Failure()
+		} else {
+			transport.Breaker.Success()
+		}
+
+		if attempt >= transport.Retries || !isRetryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		delay := transport.backoff(attempt)
+		log.Printf("retrying %s in %s (attempt %d of %d): %s", req.URL.Path, delay, attempt+1, transport.Retries, describe(resp, err))
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (transport *Transport) attempt(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), transport.Timeout)
+	attemptReq := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	resp, err := transport.RealTransport.RoundTrip(attemptReq)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the timeout has to cover reading the body as well
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff returns the exponential delay of the attempt with "equal jitter": half fixed, half random
+func (transport *Transport) backoff(attempt int) time.Duration {
+	delay := min(transport.BaseDelay<<attempt, transport.MaxDelay)
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// isFailure reports whether the exchange is failing, client errors do not count against the breaker
+func isFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// isRetryable excludes 418: binance bans the IP and retrying only extends the ban
+func isRetryable(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+func describe(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (body *cancelOnClose) Close() error {
+	err := body.ReadCloser.Close()
+	body.cancel()
+	return err
+}
@@ -0,0 +1,67 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	mu     sync.RWMutex
+	offset time.Duration // exchange server time minus local time
+)
+
+// Now returns the current time in UTC corrected by the offset to the exchange server clock
+func Now() time.Time {
+	mu.RLock()
+	defer mu.RUnlock()
+	return time.Now().UTC().Add(offset)
+}
+
+func Offset() time.Duration {
+	mu.RLock()
+	defer mu.RUnlock()
+	return offset
+}
+
+func SetOffset(value time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	offset = value
+}
+
+// Sync measures the offset to the server time returned by serverTime and applies it.
+// The server is assumed to read its clock halfway through the request.
+func Sync(serverTime func() (time.Time, error)) (time.Duration, error) {
+	sentAt := time.Now()
+	server, err := serverTime()
+	if err != nil {
+		return 0, err
+	}
+	receivedAt := time.Now()
+
+	value := server.Sub(sentAt.Add(receivedAt.Sub(sentAt) / 2))
+	SetOffset(value)
+	return value, nil
+}
+
+// NextBoundary returns the first time after now that is delay past a multiple of interval in UTC,
+// ex: an hour with a 5 second delay gives the next hh:00:05
+func NextBoundary(now time.Time, interval time.Duration, delay time.Duration) time.Time {
+	next := now.UTC().Truncate(interval).Add(delay)
+	if !next.After(now) {
+		next = next.Add(interval)
+	}
+	return next
+}
+
+// Schedule calls callback delay after every boundary of interval measured on the synced clock,
+// the boundary the call belongs to is passed to it. Calls run one after another in a goroutine.
+func Schedule(interval time.Duration, delay time.Duration, callback func(boundary time.Time)) {
+	go func() {
+		for {
+			next := NextBoundary(Now(), interval, delay)
+			time.Sleep(next.Sub(Now()))
+			callback(next.Add(-delay))
+		}
+	}()
+}
@@ -0,0 +1,118 @@
+package instrument
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+)
+
+// DefaultQuote is the quote asset of a bare currency, "btc" is btc/usdt
+const DefaultQuote = "usdt"
+
+var assetPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// Instrument is a trading pair. The currency strings passed between services, repositories and
+// table names are instrument keys: "btc" for btc/usdt and "<base>_<quote>" for other quotes, e.g. "eth_btc".
+type Instrument struct {
+	Base  string
+	Quote string
+}
+
+func New(base string, quote string) Instrument {
+	quote = strings.ToLower(strings.TrimSpace(quote))
+	if quote == "" {
+		quote = DefaultQuote
+	}
+	return Instrument{
+		Base:  strings.ToLower(strings.TrimSpace(base)),
+		Quote: quote,
+	}
+}
+
+// Parse accepts a bare currency ("btc") or a pair written as "eth/btc", "eth-btc" or "eth_btc"
+func Parse(value string) (Instrument, error) {
+	base, quote, isPair := strings.Cut(strings.NewReplacer("/", "_", "-", "_").Replace(value), "_")
+	instrument := New(base, quote)
+	if isPair && strings.TrimSpace(quote) == "" {
+		return Instrument{}, fmt.Errorf("invalid instrument: %s", value)
+	}
+	if !assetPattern.MatchString(instrument.Base) || !assetPattern.MatchString(instrument.Quote) {
+		return Instrument{}, fmt.Errorf("invalid instrument: %s", value)
+	}
+	return instrument, nil
+}
+
+// FromKey returns the instrument of an already validated key
+func FromKey(key string) Instrument {
+	base, quote, _ := strings.Cut(key, "_")
+	return New(base, quote)
+}
+
+// Key is used as the currency of services and in table names, e.g. data_<key>_1h
+func (instrument Instrument) Key() string {
+	if instrument.Quote == DefaultQuote {
+		return instrument.Base
+	}
+	return instrument.Base + "_" + instrument.Quote
+}
+
+// Symbol is the exchange symbol without separator, e.g. ETHBTC on binance
+func (instrument Instrument) Symbol() string {
+	return instrument.SymbolWithSeparator("")
+}
+
+// SymbolWithSeparator returns the exchange symbol as e.g. ETH-BTC on coinbase and okx
+func (instrument Instrument) SymbolWithSeparator(separator string) string {
+	return strings.ToUpper(instrument.Base) + separator + strings.ToUpper(instrument.Quote)
+}
+
+func (instrument Instrument) String() string {
+	return instrument.SymbolWithSeparator("/")
+}
+
+// TrackedKeys returns the active currencies of the registry (the default USDT pairs until it is loaded) followed by
+// the pairs listed in INSTRUMENTS and the ones enabled by discovery, paused currencies are left out
+func TrackedKeys() []string {
+	keys, ok := registeredKeys(config.CURRENCY_STATUS_ACTIVE)
+	if !ok {
+		keys = slices.Clone(config.DefaultCurrencies)
+	}
+	for _, value := range config.LoadConfig().Instruments {
+		instrument, err := Parse(value)
+		if err != nil {
+			log.Printf("%sError: %s %s\n", config.COLOR_RED, err, config.COLOR_RED)
+			continue
+		}
+		if !slices.Contains(keys, instrument.Key()) {
+			keys = append(keys, instrument.Key())
+		}
+	}
+	for _, key := range enabledKeys() {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return slices.DeleteFunc(keys, isPaused)
+}
+
+// IsKnown reports whether the key is tracked or registered, paused currencies keep serving their stored data
+func IsKnown(key string) bool {
+	if _, ok := CurrencyStatus(key); ok {
+		return true
+	}
+	return slices.Contains(TrackedKeys(), key)
+}
+
+func isPaused(key string) bool {
+	status, ok := CurrencyStatus(key)
+	return ok && status == config.CURRENCY_STATUS_PAUSED
+}
+
+// Symbol returns the exchange symbol of an instrument key, e.g. "eth_btc" => ETHBTC
+func Symbol(key string) string {
+	return FromKey(key).Symbol()
+}
@@ -0,0 +1,105 @@
+package instrument
+
+import (
+	"slices"
+	"sync"
+)
+
+// the registry holds what is learned at runtime: keys enabled by discovery, the binance status
+// of every tracked symbol and the currencies table with the status of each currency
+var registry = struct {
+	mu         sync.RWMutex
+	enabled    []string
+	statuses   map[string]string
+	currencies map[string]string
+	loaded     bool
+}{statuses: make(map[string]string), currencies: make(map[string]string)}
+
+// Enable adds keys to the tracked instruments, e.g. symbols enabled by discovery
+func Enable(keys ...string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	for _, key := range keys {
+		if !slices.Contains(registry.enabled, key) {
+			registry.enabled = append(registry.enabled, key)
+		}
+	}
+}
+
+// Disable removes keys enabled by discovery
+func Disable(keys ...string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.enabled = slices.DeleteFunc(registry.enabled, func(key string) bool {
+		return slices.Contains(keys, key)
+	})
+}
+
+func enabledKeys() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return slices.Clone(registry.enabled)
+}
+
+// SetCurrencies replaces the registered currencies (status by instrument key) read from the currencies table,
+// nil falls back to the default currencies
+func SetCurrencies(currencies map[string]string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.loaded = currencies != nil
+	if currencies == nil {
+		currencies = make(map[string]string)
+	}
+	registry.currencies = currencies
+}
+
+// Register adds or updates a currency of the registry
+func Register(key string, status string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.currencies[key] = status
+	registry.loaded = true
+}
+
+// Unregister removes a currency from the registry
+func Unregister(key string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.currencies, key)
+}
+
+// CurrencyStatus returns the registry status (active, paused) of the key, false when it is not registered
+func CurrencyStatus(key string) (string, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	status, ok := registry.currencies[key]
+	return status, ok
+}
+
+// registeredKeys returns the sorted keys of the registry with the given status, ok is false until the registry is loaded
+func registeredKeys(status string) (keys []string, ok bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for key, value := range registry.currencies {
+		if value == status {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys, registry.loaded
+}
+
+// SetStatuses replaces the binance statuses (TRADING, HALT, BREAK...) by instrument key
+func SetStatuses(statuses map[string]string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.statuses = statuses
+}
+
+// Status returns the binance status of the key, false when the symbol has not been synced
+func Status(key string) (string, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	status, ok := registry.statuses[key]
+	return status, ok
+}
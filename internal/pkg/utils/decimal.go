@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ParseDecimal parses an exchange price or volume string exactly, an invalid value yields 0 which the data quality checks reject
+func ParseDecimal(value string) decimal.Decimal {
+	parsed, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero
+	}
+	return parsed
+}
+
+// ParseDecimalFields parses the string fields at the given indexes of a kline decoded as []interface{},
+// a field that is not a string fails the whole kline
+func ParseDecimalFields(fields []interface{}, indexes ...int) ([]decimal.Decimal, error) {
+	values := make([]decimal.Decimal, len(indexes))
+	for i, index := range indexes {
+		value, ok := fields[index].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected response format: field %d is %T", index, fields[index])
+		}
+		values[i] = ParseDecimal(value)
+	}
+	return values, nil
+}
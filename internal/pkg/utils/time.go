@@ -1,7 +1,13 @@
 package utils
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
 )
 
 func GetNextTimeframe(timeframe string) string {
@@ -21,3 +27,69 @@ func GetPreviousTimeframe(timeframe string) string {
 	}
 	return ""
 }
+
+// GetGroupBoundaries returns the range (after, until] of 1h timestamps that make up every group of the timeframe touched by [from, to].
+// A group is labeled with its last hour, ex: the 4h group 04:00 holds 01:00, 02:00, 03:00 and 04:00.
+func GetGroupBoundaries(timeframe string, from time.Time, to time.Time) (time.Time, time.Time) {
+	groupDuration := time.Duration(config.HoursByTimeframe[timeframe]) * time.Hour
+	after := from.Add(-1 * time.Hour).Truncate(groupDuration)
+	until := to.Add(-1 * time.Hour).Truncate(groupDuration).Add(groupDuration)
+	return after, until
+}
+
+// GetCompositeTimeframe returns the partition of the composite candles of a timeframe, e.g. 1h => 1h_composite
+func GetCompositeTimeframe(timeframe string) string {
+	return timeframe + config.COMPOSITE_SUFFIX
+}
+
+func IsCompositeTimeframe(timeframe string) bool {
+	return strings.HasSuffix(timeframe, config.COMPOSITE_SUFFIX)
+}
+
+// GetBaseTimeframe returns the 1h timeframe the records of a timeframe are grouped from
+func GetBaseTimeframe(timeframe string) string {
+	if IsCompositeTimeframe(timeframe) {
+		return GetCompositeTimeframe(config.ONE_HOUR)
+	}
+	return config.ONE_HOUR
+}
+
+// ParseRetention parses a retention period in days, weeks or years ("90d", "2w", "2y") or as a go duration ("36h")
+func ParseRetention(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour, "y": 365 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if count, found := strings.CutSuffix(value, suffix); found {
+			number, err := strconv.Atoi(count)
+			if err != nil || number <= 0 {
+				return 0, fmt.Errorf("invalid retention: %s", value)
+			}
+			return time.Duration(number) * unit, nil
+		}
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid retention: %s", value)
+	}
+	return duration, nil
+}
+
+// RetentionCutoff returns the time before which the records of a timeframe expire,
+// ok is false when RETENTION_POLICIES keeps the timeframe forever
+func RetentionCutoff(timeframe string) (cutoff time.Time, ok bool, err error) {
+	policy, found := config.LoadConfig().RetentionPolicies[timeframe]
+	if !found || policy == "" || policy == "forever" {
+		return time.Time{}, false, nil
+	}
+	retention, err := ParseRetention(policy)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return clock.Now().Add(-retention).Truncate(time.Hour), true, nil
+}
+
+// NextMonth returns the start of the UTC month following t, the archive keeps one file per UTC month
+func NextMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
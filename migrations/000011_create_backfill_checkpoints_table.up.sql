@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS backfill_checkpoints (
+    currency TEXT NOT NULL,
+    timeframe TEXT NOT NULL,            -- e.g., 1h
+    start_time TIMESTAMPTZ NOT NULL,    -- first kline requested by the run
+    last_timestamp TIMESTAMPTZ NULL,    -- last kline stored by the run
+    is_complete BOOLEAN DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (currency, timeframe)
+);
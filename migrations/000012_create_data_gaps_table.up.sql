@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS data_gaps (
+    currency TEXT NOT NULL,
+    timeframe TEXT NOT NULL,            -- e.g., 1h
+    start_time TIMESTAMPTZ NOT NULL,    -- first missing timestamp
+    end_time TIMESTAMPTZ NOT NULL,      -- last missing timestamp
+    attempts INT NOT NULL DEFAULT 1,    -- number of scans that failed to fill the gap
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (currency, timeframe, start_time)
+);
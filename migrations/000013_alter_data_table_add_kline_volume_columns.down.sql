@@ -0,0 +1,5 @@
+ALTER TABLE data_btc DROP COLUMN quote_volume, DROP COLUMN trade_count, DROP COLUMN taker_buy_base_volume, DROP COLUMN taker_buy_quote_volume;
+ALTER TABLE data_eth DROP COLUMN quote_volume, DROP COLUMN trade_count, DROP COLUMN taker_buy_base_volume, DROP COLUMN taker_buy_quote_volume;
+ALTER TABLE data_sol DROP COLUMN quote_volume, DROP COLUMN trade_count, DROP COLUMN taker_buy_base_volume, DROP COLUMN taker_buy_quote_volume;
+ALTER TABLE data_bnb DROP COLUMN quote_volume, DROP COLUMN trade_count, DROP COLUMN taker_buy_base_volume, DROP COLUMN taker_buy_quote_volume;
+ALTER TABLE data_trump DROP COLUMN quote_volume, DROP COLUMN trade_count, DROP COLUMN taker_buy_base_volume, DROP COLUMN taker_buy_quote_volume;
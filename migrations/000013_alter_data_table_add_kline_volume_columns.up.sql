@@ -0,0 +1,5 @@
+ALTER TABLE data_btc ADD COLUMN quote_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN trade_count BIGINT NOT NULL DEFAULT 0, ADD COLUMN taker_buy_base_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN taker_buy_quote_volume NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_eth ADD COLUMN quote_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN trade_count BIGINT NOT NULL DEFAULT 0, ADD COLUMN taker_buy_base_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN taker_buy_quote_volume NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_sol ADD COLUMN quote_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN trade_count BIGINT NOT NULL DEFAULT 0, ADD COLUMN taker_buy_base_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN taker_buy_quote_volume NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_bnb ADD COLUMN quote_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN trade_count BIGINT NOT NULL DEFAULT 0, ADD COLUMN taker_buy_base_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN taker_buy_quote_volume NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_trump ADD COLUMN quote_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN trade_count BIGINT NOT NULL DEFAULT 0, ADD COLUMN taker_buy_base_volume NUMERIC NOT NULL DEFAULT 0, ADD COLUMN taker_buy_quote_volume NUMERIC NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS depth_btc;
+DROP TABLE IF EXISTS depth_eth;
+DROP TABLE IF EXISTS depth_sol;
+DROP TABLE IF EXISTS depth_bnb;
+DROP TABLE IF EXISTS depth_trump;
@@ -0,0 +1,85 @@
+CREATE TABLE IF NOT EXISTS depth_btc (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    best_bid NUMERIC NOT NULL,
+    best_ask NUMERIC NOT NULL,
+    mid_price NUMERIC NOT NULL,
+    spread NUMERIC NOT NULL,
+    bid_depth_05 NUMERIC NOT NULL, -- quote volume within 0.5% below the mid price
+    ask_depth_05 NUMERIC NOT NULL,
+    bid_depth_1 NUMERIC NOT NULL,
+    ask_depth_1 NUMERIC NOT NULL,
+    bid_depth_2 NUMERIC NOT NULL,
+    ask_depth_2 NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS depth_eth (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    best_bid NUMERIC NOT NULL,
+    best_ask NUMERIC NOT NULL,
+    mid_price NUMERIC NOT NULL,
+    spread NUMERIC NOT NULL,
+    bid_depth_05 NUMERIC NOT NULL, -- quote volume within 0.5% below the mid price
+    ask_depth_05 NUMERIC NOT NULL,
+    bid_depth_1 NUMERIC NOT NULL,
+    ask_depth_1 NUMERIC NOT NULL,
+    bid_depth_2 NUMERIC NOT NULL,
+    ask_depth_2 NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS depth_sol (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    best_bid NUMERIC NOT NULL,
+    best_ask NUMERIC NOT NULL,
+    mid_price NUMERIC NOT NULL,
+    spread NUMERIC NOT NULL,
+    bid_depth_05 NUMERIC NOT NULL, -- quote volume within 0.5% below the mid price
+    ask_depth_05 NUMERIC NOT NULL,
+    bid_depth_1 NUMERIC NOT NULL,
+    ask_depth_1 NUMERIC NOT NULL,
+    bid_depth_2 NUMERIC NOT NULL,
+    ask_depth_2 NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS depth_bnb (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    best_bid NUMERIC NOT NULL,
+    best_ask NUMERIC NOT NULL,
+    mid_price NUMERIC NOT NULL,
+    spread NUMERIC NOT NULL,
+    bid_depth_05 NUMERIC NOT NULL, -- quote volume within 0.5% below the mid price
+    ask_depth_05 NUMERIC NOT NULL,
+    bid_depth_1 NUMERIC NOT NULL,
+    ask_depth_1 NUMERIC NOT NULL,
+    bid_depth_2 NUMERIC NOT NULL,
+    ask_depth_2 NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS depth_trump (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    best_bid NUMERIC NOT NULL,
+    best_ask NUMERIC NOT NULL,
+    mid_price NUMERIC NOT NULL,
+    spread NUMERIC NOT NULL,
+    bid_depth_05 NUMERIC NOT NULL, -- quote volume within 0.5% below the mid price
+    ask_depth_05 NUMERIC NOT NULL,
+    bid_depth_1 NUMERIC NOT NULL,
+    ask_depth_1 NUMERIC NOT NULL,
+    bid_depth_2 NUMERIC NOT NULL,
+    ask_depth_2 NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
@@ -0,0 +1,5 @@
+ALTER TABLE data_btc DROP COLUMN vwap;
+ALTER TABLE data_eth DROP COLUMN vwap;
+ALTER TABLE data_sol DROP COLUMN vwap;
+ALTER TABLE data_bnb DROP COLUMN vwap;
+ALTER TABLE data_trump DROP COLUMN vwap;
@@ -0,0 +1,5 @@
+ALTER TABLE data_btc ADD COLUMN vwap NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_eth ADD COLUMN vwap NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_sol ADD COLUMN vwap NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_bnb ADD COLUMN vwap NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_trump ADD COLUMN vwap NUMERIC NOT NULL DEFAULT 0;
@@ -0,0 +1,35 @@
+DROP TABLE IF EXISTS indicator_btc_1h_composite;
+DROP TABLE IF EXISTS indicator_btc_4h_composite;
+DROP TABLE IF EXISTS indicator_btc_1d_composite;
+DROP TABLE IF EXISTS indicator_eth_1h_composite;
+DROP TABLE IF EXISTS indicator_eth_4h_composite;
+DROP TABLE IF EXISTS indicator_eth_1d_composite;
+DROP TABLE IF EXISTS indicator_sol_1h_composite;
+DROP TABLE IF EXISTS indicator_sol_4h_composite;
+DROP TABLE IF EXISTS indicator_sol_1d_composite;
+DROP TABLE IF EXISTS indicator_bnb_1h_composite;
+DROP TABLE IF EXISTS indicator_bnb_4h_composite;
+DROP TABLE IF EXISTS indicator_bnb_1d_composite;
+DROP TABLE IF EXISTS indicator_trump_1h_composite;
+DROP TABLE IF EXISTS indicator_trump_4h_composite;
+DROP TABLE IF EXISTS indicator_trump_1d_composite;
+DROP TABLE IF EXISTS data_btc_1h_composite;
+DROP TABLE IF EXISTS data_btc_4h_composite;
+DROP TABLE IF EXISTS data_btc_1d_composite;
+DROP TABLE IF EXISTS data_eth_1h_composite;
+DROP TABLE IF EXISTS data_eth_4h_composite;
+DROP TABLE IF EXISTS data_eth_1d_composite;
+DROP TABLE IF EXISTS data_sol_1h_composite;
+DROP TABLE IF EXISTS data_sol_4h_composite;
+DROP TABLE IF EXISTS data_sol_1d_composite;
+DROP TABLE IF EXISTS data_bnb_1h_composite;
+DROP TABLE IF EXISTS data_bnb_4h_composite;
+DROP TABLE IF EXISTS data_bnb_1d_composite;
+DROP TABLE IF EXISTS data_trump_1h_composite;
+DROP TABLE IF EXISTS data_trump_4h_composite;
+DROP TABLE IF EXISTS data_trump_1d_composite;
+ALTER TABLE data_btc DROP COLUMN median_price;
+ALTER TABLE data_eth DROP COLUMN median_price;
+ALTER TABLE data_sol DROP COLUMN median_price;
+ALTER TABLE data_bnb DROP COLUMN median_price;
+ALTER TABLE data_trump DROP COLUMN median_price;
@@ -0,0 +1,120 @@
+ALTER TABLE data_btc ADD COLUMN median_price NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_eth ADD COLUMN median_price NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_sol ADD COLUMN median_price NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_bnb ADD COLUMN median_price NUMERIC NOT NULL DEFAULT 0;
+ALTER TABLE data_trump ADD COLUMN median_price NUMERIC NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS data_btc_1h_composite PARTITION OF data_btc FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_data_btc_1h_composite_timestamp ON data_btc_1h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_btc_4h_composite PARTITION OF data_btc FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_data_btc_4h_composite_timestamp ON data_btc_4h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_btc_1d_composite PARTITION OF data_btc FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_data_btc_1d_composite_timestamp ON data_btc_1d_composite(timestamp);
+
+CREATE TABLE IF NOT EXISTS data_eth_1h_composite PARTITION OF data_eth FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_data_eth_1h_composite_timestamp ON data_eth_1h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_eth_4h_composite PARTITION OF data_eth FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_data_eth_4h_composite_timestamp ON data_eth_4h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_eth_1d_composite PARTITION OF data_eth FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_data_eth_1d_composite_timestamp ON data_eth_1d_composite(timestamp);
+
+CREATE TABLE IF NOT EXISTS data_sol_1h_composite PARTITION OF data_sol FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_data_sol_1h_composite_timestamp ON data_sol_1h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_sol_4h_composite PARTITION OF data_sol FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_data_sol_4h_composite_timestamp ON data_sol_4h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_sol_1d_composite PARTITION OF data_sol FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_data_sol_1d_composite_timestamp ON data_sol_1d_composite(timestamp);
+
+CREATE TABLE IF NOT EXISTS data_bnb_1h_composite PARTITION OF data_bnb FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_data_bnb_1h_composite_timestamp ON data_bnb_1h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_bnb_4h_composite PARTITION OF data_bnb FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_data_bnb_4h_composite_timestamp ON data_bnb_4h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_bnb_1d_composite PARTITION OF data_bnb FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_data_bnb_1d_composite_timestamp ON data_bnb_1d_composite(timestamp);
+
+CREATE TABLE IF NOT EXISTS data_trump_1h_composite PARTITION OF data_trump FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_data_trump_1h_composite_timestamp ON data_trump_1h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_trump_4h_composite PARTITION OF data_trump FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_data_trump_4h_composite_timestamp ON data_trump_4h_composite(timestamp);
+CREATE TABLE IF NOT EXISTS data_trump_1d_composite PARTITION OF data_trump FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_data_trump_1d_composite_timestamp ON data_trump_1d_composite(timestamp);
+
+CREATE TABLE IF NOT EXISTS indicator_btc_1h_composite PARTITION OF indicator_btc FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_indicator_btc_1h_composite_timestamp ON indicator_btc_1h_composite(timestamp);
+ALTER TABLE indicator_btc_1h_composite
+ADD CONSTRAINT fk_indicator_btc_1h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_btc_1h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_btc_4h_composite PARTITION OF indicator_btc FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_indicator_btc_4h_composite_timestamp ON indicator_btc_4h_composite(timestamp);
+ALTER TABLE indicator_btc_4h_composite
+ADD CONSTRAINT fk_indicator_btc_4h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_btc_4h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_btc_1d_composite PARTITION OF indicator_btc FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_indicator_btc_1d_composite_timestamp ON indicator_btc_1d_composite(timestamp);
+ALTER TABLE indicator_btc_1d_composite
+ADD CONSTRAINT fk_indicator_btc_1d_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_btc_1d_composite (timestamp) ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS indicator_eth_1h_composite PARTITION OF indicator_eth FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_indicator_eth_1h_composite_timestamp ON indicator_eth_1h_composite(timestamp);
+ALTER TABLE indicator_eth_1h_composite
+ADD CONSTRAINT fk_indicator_eth_1h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_eth_1h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_eth_4h_composite PARTITION OF indicator_eth FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_indicator_eth_4h_composite_timestamp ON indicator_eth_4h_composite(timestamp);
+ALTER TABLE indicator_eth_4h_composite
+ADD CONSTRAINT fk_indicator_eth_4h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_eth_4h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_eth_1d_composite PARTITION OF indicator_eth FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_indicator_eth_1d_composite_timestamp ON indicator_eth_1d_composite(timestamp);
+ALTER TABLE indicator_eth_1d_composite
+ADD CONSTRAINT fk_indicator_eth_1d_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_eth_1d_composite (timestamp) ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS indicator_sol_1h_composite PARTITION OF indicator_sol FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_indicator_sol_1h_composite_timestamp ON indicator_sol_1h_composite(timestamp);
+ALTER TABLE indicator_sol_1h_composite
+ADD CONSTRAINT fk_indicator_sol_1h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_sol_1h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_sol_4h_composite PARTITION OF indicator_sol FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_indicator_sol_4h_composite_timestamp ON indicator_sol_4h_composite(timestamp);
+ALTER TABLE indicator_sol_4h_composite
+ADD CONSTRAINT fk_indicator_sol_4h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_sol_4h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_sol_1d_composite PARTITION OF indicator_sol FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_indicator_sol_1d_composite_timestamp ON indicator_sol_1d_composite(timestamp);
+ALTER TABLE indicator_sol_1d_composite
+ADD CONSTRAINT fk_indicator_sol_1d_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_sol_1d_composite (timestamp) ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS indicator_bnb_1h_composite PARTITION OF indicator_bnb FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_indicator_bnb_1h_composite_timestamp ON indicator_bnb_1h_composite(timestamp);
+ALTER TABLE indicator_bnb_1h_composite
+ADD CONSTRAINT fk_indicator_bnb_1h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_bnb_1h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_bnb_4h_composite PARTITION OF indicator_bnb FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_indicator_bnb_4h_composite_timestamp ON indicator_bnb_4h_composite(timestamp);
+ALTER TABLE indicator_bnb_4h_composite
+ADD CONSTRAINT fk_indicator_bnb_4h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_bnb_4h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_bnb_1d_composite PARTITION OF indicator_bnb FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_indicator_bnb_1d_composite_timestamp ON indicator_bnb_1d_composite(timestamp);
+ALTER TABLE indicator_bnb_1d_composite
+ADD CONSTRAINT fk_indicator_bnb_1d_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_bnb_1d_composite (timestamp) ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS indicator_trump_1h_composite PARTITION OF indicator_trump FOR VALUES IN ('1h_composite');
+CREATE UNIQUE INDEX idx_indicator_trump_1h_composite_timestamp ON indicator_trump_1h_composite(timestamp);
+ALTER TABLE indicator_trump_1h_composite
+ADD CONSTRAINT fk_indicator_trump_1h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_trump_1h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_trump_4h_composite PARTITION OF indicator_trump FOR VALUES IN ('4h_composite');
+CREATE UNIQUE INDEX idx_indicator_trump_4h_composite_timestamp ON indicator_trump_4h_composite(timestamp);
+ALTER TABLE indicator_trump_4h_composite
+ADD CONSTRAINT fk_indicator_trump_4h_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_trump_4h_composite (timestamp) ON DELETE CASCADE;
+CREATE TABLE IF NOT EXISTS indicator_trump_1d_composite PARTITION OF indicator_trump FOR VALUES IN ('1d_composite');
+CREATE UNIQUE INDEX idx_indicator_trump_1d_composite_timestamp ON indicator_trump_1d_composite(timestamp);
+ALTER TABLE indicator_trump_1d_composite
+ADD CONSTRAINT fk_indicator_trump_1d_composite_data_timestamp
+FOREIGN KEY (data_timestamp) REFERENCES data_trump_1d_composite (timestamp) ON DELETE CASCADE;
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS funding_rate_btc;
+DROP TABLE IF EXISTS open_interest_btc;
+DROP TABLE IF EXISTS mark_price_btc;
+DROP TABLE IF EXISTS funding_rate_eth;
+DROP TABLE IF EXISTS open_interest_eth;
+DROP TABLE IF EXISTS mark_price_eth;
+DROP TABLE IF EXISTS funding_rate_sol;
+DROP TABLE IF EXISTS open_interest_sol;
+DROP TABLE IF EXISTS mark_price_sol;
+DROP TABLE IF EXISTS funding_rate_bnb;
+DROP TABLE IF EXISTS open_interest_bnb;
+DROP TABLE IF EXISTS mark_price_bnb;
+DROP TABLE IF EXISTS funding_rate_trump;
+DROP TABLE IF EXISTS open_interest_trump;
+DROP TABLE IF EXISTS mark_price_trump;
@@ -0,0 +1,144 @@
+CREATE TABLE IF NOT EXISTS funding_rate_btc (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- funding time
+    funding_rate NUMERIC NOT NULL,
+    mark_price NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS open_interest_btc (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    open_interest NUMERIC NOT NULL, -- open contracts in base asset
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS mark_price_btc (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- 1h kline open time
+    open NUMERIC NOT NULL,
+    high NUMERIC NOT NULL,
+    low NUMERIC NOT NULL,
+    close NUMERIC NOT NULL,
+    is_complete BOOLEAN DEFAULT FALSE,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS funding_rate_eth (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- funding time
+    funding_rate NUMERIC NOT NULL,
+    mark_price NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS open_interest_eth (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    open_interest NUMERIC NOT NULL, -- open contracts in base asset
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS mark_price_eth (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- 1h kline open time
+    open NUMERIC NOT NULL,
+    high NUMERIC NOT NULL,
+    low NUMERIC NOT NULL,
+    close NUMERIC NOT NULL,
+    is_complete BOOLEAN DEFAULT FALSE,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS funding_rate_sol (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- funding time
+    funding_rate NUMERIC NOT NULL,
+    mark_price NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS open_interest_sol (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    open_interest NUMERIC NOT NULL, -- open contracts in base asset
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS mark_price_sol (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- 1h kline open time
+    open NUMERIC NOT NULL,
+    high NUMERIC NOT NULL,
+    low NUMERIC NOT NULL,
+    close NUMERIC NOT NULL,
+    is_complete BOOLEAN DEFAULT FALSE,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS funding_rate_bnb (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- funding time
+    funding_rate NUMERIC NOT NULL,
+    mark_price NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS open_interest_bnb (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    open_interest NUMERIC NOT NULL, -- open contracts in base asset
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS mark_price_bnb (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- 1h kline open time
+    open NUMERIC NOT NULL,
+    high NUMERIC NOT NULL,
+    low NUMERIC NOT NULL,
+    close NUMERIC NOT NULL,
+    is_complete BOOLEAN DEFAULT FALSE,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS funding_rate_trump (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- funding time
+    funding_rate NUMERIC NOT NULL,
+    mark_price NUMERIC NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS open_interest_trump (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    open_interest NUMERIC NOT NULL, -- open contracts in base asset
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS mark_price_trump (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL, -- 1h kline open time
+    open NUMERIC NOT NULL,
+    high NUMERIC NOT NULL,
+    low NUMERIC NOT NULL,
+    close NUMERIC NOT NULL,
+    is_complete BOOLEAN DEFAULT FALSE,
+    UNIQUE (timestamp)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ticker_btc;
+DROP TABLE IF EXISTS ticker_eth;
+DROP TABLE IF EXISTS ticker_sol;
+DROP TABLE IF EXISTS ticker_bnb;
+DROP TABLE IF EXISTS ticker_trump;
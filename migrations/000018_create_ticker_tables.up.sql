@@ -0,0 +1,84 @@
+CREATE TABLE IF NOT EXISTS ticker_btc (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    last_price NUMERIC NOT NULL,
+    open_price NUMERIC NOT NULL,
+    high_price NUMERIC NOT NULL,
+    low_price NUMERIC NOT NULL,
+    price_change NUMERIC NOT NULL,
+    price_change_percent NUMERIC NOT NULL,
+    weighted_avg_price NUMERIC NOT NULL,
+    volume NUMERIC NOT NULL, -- rolling 24h volume
+    quote_volume NUMERIC NOT NULL,
+    trade_count BIGINT NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS ticker_eth (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    last_price NUMERIC NOT NULL,
+    open_price NUMERIC NOT NULL,
+    high_price NUMERIC NOT NULL,
+    low_price NUMERIC NOT NULL,
+    price_change NUMERIC NOT NULL,
+    price_change_percent NUMERIC NOT NULL,
+    weighted_avg_price NUMERIC NOT NULL,
+    volume NUMERIC NOT NULL, -- rolling 24h volume
+    quote_volume NUMERIC NOT NULL,
+    trade_count BIGINT NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS ticker_sol (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    last_price NUMERIC NOT NULL,
+    open_price NUMERIC NOT NULL,
+    high_price NUMERIC NOT NULL,
+    low_price NUMERIC NOT NULL,
+    price_change NUMERIC NOT NULL,
+    price_change_percent NUMERIC NOT NULL,
+    weighted_avg_price NUMERIC NOT NULL,
+    volume NUMERIC NOT NULL, -- rolling 24h volume
+    quote_volume NUMERIC NOT NULL,
+    trade_count BIGINT NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS ticker_bnb (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    last_price NUMERIC NOT NULL,
+    open_price NUMERIC NOT NULL,
+    high_price NUMERIC NOT NULL,
+    low_price NUMERIC NOT NULL,
+    price_change NUMERIC NOT NULL,
+    price_change_percent NUMERIC NOT NULL,
+    weighted_avg_price NUMERIC NOT NULL,
+    volume NUMERIC NOT NULL, -- rolling 24h volume
+    quote_volume NUMERIC NOT NULL,
+    trade_count BIGINT NOT NULL,
+    UNIQUE (timestamp)
+);
+
+CREATE TABLE IF NOT EXISTS ticker_trump (
+    id BIGSERIAL PRIMARY KEY,
+    symbol TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    last_price NUMERIC NOT NULL,
+    open_price NUMERIC NOT NULL,
+    high_price NUMERIC NOT NULL,
+    low_price NUMERIC NOT NULL,
+    price_change NUMERIC NOT NULL,
+    price_change_percent NUMERIC NOT NULL,
+    weighted_avg_price NUMERIC NOT NULL,
+    volume NUMERIC NOT NULL, -- rolling 24h volume
+    quote_volume NUMERIC NOT NULL,
+    trade_count BIGINT NOT NULL,
+    UNIQUE (timestamp)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS symbols (
+    symbol TEXT PRIMARY KEY,
+    base_asset TEXT NOT NULL,
+    quote_asset TEXT NOT NULL,
+    status TEXT NOT NULL,
+    tick_size NUMERIC NOT NULL DEFAULT 0,
+    step_size NUMERIC NOT NULL DEFAULT 0, -- lot size
+    min_qty NUMERIC NOT NULL DEFAULT 0,
+    min_notional NUMERIC NOT NULL DEFAULT 0,
+    quote_volume NUMERIC NOT NULL DEFAULT 0, -- rolling 24h quote volume
+    enabled BOOLEAN NOT NULL DEFAULT FALSE, -- tracked by discovery
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_symbols_quote_asset_quote_volume ON symbols (quote_asset, quote_volume DESC);
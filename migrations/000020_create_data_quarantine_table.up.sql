@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS data_quarantine (
+    id BIGSERIAL PRIMARY KEY,
+    currency TEXT NOT NULL,
+    timeframe TEXT NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL,
+    record JSONB NOT NULL, -- the rejected candle as fetched
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (currency, timeframe, timestamp)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS currencies (
+    key TEXT PRIMARY KEY, -- instrument key, e.g. btc or eth_btc
+    base_asset TEXT NOT NULL,
+    quote_asset TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active', -- active, paused
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO currencies (key, base_asset, quote_asset) VALUES ('btc', 'BTC', 'USDT') ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/backfill"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/fakebinance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// Test Suite Struct, the backfill pages through the fake binance server into STORAGE_BACKEND=memory
+type BackfillServiceTestSuite struct {
+	suite.Suite
+	server    *fakebinance.Server
+	redisMock *MockRedisService
+	service   *backfill.BackfillService
+	from      time.Time
+}
+
+func (suite *BackfillServiceTestSuite) SetupSuite() {
+	os.Setenv("STORAGE_BACKEND", "memory")
+}
+
+func (suite *BackfillServiceTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+	memory.DB.Reset()
+}
+
+func (suite *BackfillServiceTestSuite) SetupTest() {
+	memory.DB.Reset()
+	suite.server = fakebinance.NewServer(fakebinance.Options{Seed: 11})
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.Start()+"/api/v3/")
+
+	suite.redisMock = &MockRedisService{}
+	suite.redisMock.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
+	marketDataService := marketdata.NewMarketDataService(suite.redisMock)
+	suite.service = backfill.NewBackfillService(marketDataService, indicator.NewIndicatorService(suite.redisMock))
+
+	// 2500 complete hours and the forming one take three pages of 1000 klines
+	suite.from = clock.Now().Truncate(time.Hour).Add(-2500 * time.Hour)
+}
+
+func (suite *BackfillServiceTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+	os.Unsetenv("HTTP_RETRIES")
+}
+
+func (suite *BackfillServiceTestSuite) TestShouldPageThroughKlinesUpToNow() {
+	checkpoint, err := suite.service.Backfill("btc", suite.from)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, suite.server.Requests("klines"))
+	assert.Len(suite.T(), memory.DB.Candles("btc", config.ONE_HOUR), 2501)
+	assert.True(suite.T(), checkpoint.IsComplete)
+	assert.Equal(suite.T(), suite.from.Add(2499*time.Hour), *checkpoint.LastTimestamp)
+}
+
+func (suite *BackfillServiceTestSuite) TestShouldResumeFromCheckpointAfterInterruption() {
+	os.Setenv("HTTP_RETRIES", "0")
+	suite.server.Inject(fakebinance.Fault{Kind: fakebinance.FaultServerError, Endpoint: "klines", After: 1})
+
+	checkpoint, err := suite.service.Backfill("btc", suite.from)
+
+	assert.Error(suite.T(), err)
+	assert.False(suite.T(), checkpoint.IsComplete)
+	assert.Equal(suite.T(), suite.from.Add(999*time.Hour), *checkpoint.LastTimestamp)
+	assert.Len(suite.T(), memory.DB.Candles("btc", config.ONE_HOUR), 1000)
+	assert.Equal(suite.T(), 0, suite.publishedEvents(config.EVENT_NEW_GROUP_DATA_ADDED))
+
+	// the second run starts after the checkpoint, the first page is not fetched again
+	checkpoint, err = suite.service.Backfill("btc", suite.from)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4, suite.server.Requests("klines"))
+	assert.Len(suite.T(), memory.DB.Candles("btc", config.ONE_HOUR), 2501)
+	assert.True(suite.T(), checkpoint.IsComplete)
+	assert.Equal(suite.T(), suite.from, checkpoint.StartTime)
+	assert.Equal(suite.T(), suite.from.Add(2499*time.Hour), *checkpoint.LastTimestamp)
+}
+
+func (suite *BackfillServiceTestSuite) TestShouldGroupAndComputeIndicatorsOnceAtTheEnd() {
+	_, err := suite.service.Backfill("btc", suite.from)
+
+	assert.NoError(suite.T(), err)
+	// one grouping and one indicator batch per 4h and 1d timeframe for the three pages
+	assert.Equal(suite.T(), 2, suite.publishedEvents(config.EVENT_NEW_GROUP_DATA_ADDED))
+	assert.Equal(suite.T(), 2, suite.publishedEvents(config.EVENT_NEW_INDICATOR_ADDED))
+	assert.NotEmpty(suite.T(), memory.DB.Candles("btc", config.FOUR_HOUR))
+	assert.NotEmpty(suite.T(), memory.DB.Candles("btc", config.ONE_DAY))
+	assert.NotEmpty(suite.T(), memory.DB.Indicators("btc", config.FOUR_HOUR))
+	assert.NotEmpty(suite.T(), memory.DB.Indicators("btc", config.ONE_DAY))
+}
+
+func (suite *BackfillServiceTestSuite) TestShouldContinueFinishedBackfillFromLastKline() {
+	_, err := suite.service.Backfill("btc", suite.from)
+	assert.NoError(suite.T(), err)
+
+	checkpoint, err := suite.service.Backfill("btc", suite.from)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4, suite.server.Requests("klines"))
+	assert.Equal(suite.T(), suite.from.Add(2500*time.Hour), checkpoint.StartTime)
+	assert.True(suite.T(), checkpoint.IsComplete)
+}
+
+// publishedEvents counts the events of the name published by the services
+func (suite *BackfillServiceTestSuite) publishedEvents(eventName string) int {
+	count := 0
+	for _, call := range suite.redisMock.Calls {
+		if call.Method == "PublishEvent" && call.Arguments.String(1) == eventName {
+			count++
+		}
+	}
+	return count
+}
+
+func TestBackfillService(t *testing.T) {
+	suite.Run(t, new(BackfillServiceTestSuite))
+}
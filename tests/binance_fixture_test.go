@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var tickerFixture = `{"symbol":"BTCUSDT","lastPrice":"93501.01000000","openPrice":"93576.00000000","highPrice":"93822.41000000","lowPrice":"93210.30000000","volume":"2386.31321040"}`
+
+type BinanceFixtureTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	dir    string
+	klines []byte
+}
+
+func (suite *BinanceFixtureTestSuite) SetupTest() {
+	klines, _ := os.ReadFile("fixtures/binance/klines.json")
+	suite.klines = bytes.TrimSpace(klines)
+	suite.dir = suite.T().TempDir()
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ticker/24hr" {
+			w.Write([]byte(tickerFixture))
+			return
+		}
+		w.Write(suite.klines)
+	}))
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.URL+"/")
+	os.Setenv("BINANCE_FIXTURES_DIR", suite.dir)
+}
+
+func (suite *BinanceFixtureTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+	os.Unsetenv("BINANCE_FIXTURES_DIR")
+	os.Unsetenv("BINANCE_MODE")
+	os.Unsetenv("MOCK_BINANCE")
+	os.Unsetenv("BINANCE_FUTURES_BASE_API_URL")
+}
+
+func (suite *BinanceFixtureTestSuite) TestShouldFailReplayWithoutFixturesDir() {
+	assert.NoError(suite.T(), binance.CheckFixtures(), "live mode needs no fixtures")
+
+	os.Setenv("BINANCE_MODE", "replay")
+	assert.NoError(suite.T(), binance.CheckFixtures())
+
+	os.Setenv("BINANCE_FIXTURES_DIR", filepath.Join(suite.dir, "missing"))
+	assert.ErrorContains(suite.T(), binance.CheckFixtures(), "BINANCE_MODE=replay needs fixtures recorded with BINANCE_MODE=record")
+}
+
+func (suite *BinanceFixtureTestSuite) TestShouldServeGeneratedDataWithMockBinanceAndNoFixtures() {
+	os.Setenv("MOCK_BINANCE", "true")
+	os.Setenv("BINANCE_FIXTURES_DIR", filepath.Join(suite.dir, "missing"))
+	suite.server.Close()
+
+	assert.NoError(suite.T(), binance.CheckFixtures())
+	records, err := binance.FetchKline("btc", "1h", 3)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 3)
+	assert.True(suite.T(), records[2].Close.IsPositive())
+	assert.NoDirExists(suite.T(), filepath.Join(suite.dir, "missing"))
+}
+
+func (suite *BinanceFixtureTestSuite) TestShouldSliceRecordedMarkPriceKlines() {
+	os.Setenv("BINANCE_FUTURES_BASE_API_URL", suite.server.URL+"/fapi/v1/")
+	firstHour := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	secondHour := firstHour.Add(time.Hour)
+
+	os.Setenv("BINANCE_MODE", "record")
+	_, err := binance.FetchMarkPriceKlines("btc", "1h", firstHour, secondHour, 2)
+	assert.NoError(suite.T(), err)
+	suite.server.Close()
+	os.Setenv("BINANCE_MODE", "replay")
+
+	assert.FileExists(suite.T(), filepath.Join(suite.dir, "fapi", "v1", "markPriceKlines", "interval-1h_symbol-BTCUSDT.json"))
+	records, err := binance.FetchMarkPriceKlines("btc", "1h", secondHour, secondHour, 10)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), secondHour, records[0].Timestamp)
+}
+
+func (suite *BinanceFixtureTestSuite) record() {
+	os.Setenv("BINANCE_MODE", "record")
+	_, err := binance.FetchKline("btc", "1h", 2)
+	assert.NoError(suite.T(), err)
+	_, err = binance.FetchTicker("btc")
+	assert.NoError(suite.T(), err)
+
+	// replay must not need the server
+	suite.server.Close()
+	os.Setenv("BINANCE_MODE", "replay")
+}
+
+func (suite *BinanceFixtureTestSuite) TestShouldRecordFixturesKeyedByEndpointAndQuery() {
+	suite.record()
+
+	assert.FileExists(suite.T(), filepath.Join(suite.dir, "klines", "interval-1h_symbol-BTCUSDT.json"))
+	assert.FileExists(suite.T(), filepath.Join(suite.dir, "ticker", "24hr", "symbol-BTCUSDT.json"))
+}
+
+func (suite *BinanceFixtureTestSuite) TestShouldReplayResponsesByteForByte() {
+	suite.record()
+
+	resp, err := binance.GetHTTPClient().Get(suite.server.URL + "/klines?symbol=BTCUSDT&interval=1h")
+	assert.NoError(suite.T(), err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(suite.T(), string(suite.klines), string(body))
+
+	resp, err = binance.GetHTTPClient().Get(suite.server.URL + "/ticker/24hr?symbol=BTCUSDT")
+	assert.NoError(suite.T(), err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(suite.T(), tickerFixture, string(body))
+}
+
+func (suite *BinanceFixtureTestSuite) TestShouldSliceRecordedKlines() {
+	suite.record()
+	firstHour := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	secondHour := firstHour.Add(time.Hour)
+
+	// without a startTime the latest klines are returned
+	records, err := binance.FetchKline("btc", "1h", 1)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), secondHour, records[0].Timestamp.UTC())
+
+	records, err = binance.FetchKlineRange("btc", "1h", firstHour, firstHour, 10)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), firstHour, records[0].Timestamp.UTC())
+	assertDecimal(suite.T(), 93420.55, records[0].Close)
+	assertDecimal(suite.T(), 131449213.41223107, records[0].QuoteVolume)
+	assert.Equal(suite.T(), int64(220811), records[0].TradeCount)
+	assertDecimal(suite.T(), 690.118239, records[0].TakerBuyBaseVolume)
+	assertDecimal(suite.T(), 64548319.52102245, records[0].TakerBuyQuoteVolume)
+
+	records, err = binance.FetchKlineRange("btc", "1h", firstHour, secondHour, 1)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), firstHour, records[0].Timestamp.UTC())
+}
+
+func (suite *BinanceFixtureTestSuite) TestShouldFailOnMissingFixture() {
+	suite.record()
+
+	_, err := binance.FetchKline("eth", "1h", 1)
+	assert.ErrorContains(suite.T(), err, "404")
+}
+
+func TestBinanceFixture(t *testing.T) {
+	suite.Run(t, new(BinanceFixtureTestSuite))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BinanceRateLimitTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	requests atomic.Int32
+}
+
+func (suite *BinanceRateLimitTestSuite) SetupTest() {
+	suite.requests.Store(0)
+	klines, _ := os.ReadFile("fixtures/binance/klines.json")
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first request is rejected with a ban of one second
+		if suite.requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "42")
+		w.Write(klines)
+	}))
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.URL+"/")
+}
+
+func (suite *BinanceRateLimitTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+}
+
+func (suite *BinanceRateLimitTestSuite) TestShouldHonorRetryAfterAndTrackUsedWeight() {
+	// the 429 is retried once the ban is over
+	startTime := time.Now()
+	records, err := binance.FetchKline("btc", "1h", 2)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 2)
+	assert.GreaterOrEqual(suite.T(), time.Since(startTime), 900*time.Millisecond)
+	assert.Equal(suite.T(), int32(2), suite.requests.Load())
+
+	if time.Now().Second() > 0 { // the weight resets with every new minute
+		assert.Equal(suite.T(), 42, binance.GetUsedWeight())
+		assert.Equal(suite.T(), "42", expvar.Get("binance_used_weight_1m").String())
+	}
+}
+
+func (suite *BinanceRateLimitTestSuite) TestShouldQueueLongerThanTheRequestTimeout() {
+	klines, _ := os.ReadFile("fixtures/binance/klines.json")
+	requests := atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write(klines)
+	}))
+	defer server.Close()
+	os.Setenv("BINANCE_BASE_API_URL", server.URL+"/")
+	os.Setenv("HTTP_REQUEST_TIMEOUT", "1")
+	os.Setenv("HTTP_RETRIES", "1")
+	defer os.Unsetenv("HTTP_REQUEST_TIMEOUT")
+	defer os.Unsetenv("HTTP_RETRIES")
+
+	// the ban outlasts the timeout of the single retry, the queued retry still goes out once the ban is over
+	startTime := time.Now()
+	records, err := binance.FetchKline("btc", "1h", 2)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 2)
+	assert.GreaterOrEqual(suite.T(), time.Since(startTime), 2900*time.Millisecond)
+	assert.Equal(suite.T(), int32(2), requests.Load())
+	assert.Equal(suite.T(), resilience.StateClosed, resilience.GetCircuitBreaker(config.EXCHANGE_BINANCE).State())
+}
+
+func (suite *BinanceRateLimitTestSuite) TestShouldTrackFuturesWeightSeparately() {
+	openInterest, _ := os.ReadFile("fixtures/binance/futures/openInterest.json")
+	requests := atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", "7")
+		w.Write(openInterest)
+	}))
+	defer server.Close()
+	os.Setenv("BINANCE_FUTURES_BASE_API_URL", server.URL+"/fapi/v1/")
+	defer os.Unsetenv("BINANCE_FUTURES_BASE_API_URL")
+
+	// the 429 pauses the futures requests until its Retry-After has passed
+	startTime := time.Now()
+	_, err := binance.FetchOpenInterest("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.GreaterOrEqual(suite.T(), time.Since(startTime), 900*time.Millisecond)
+	assert.Equal(suite.T(), int32(2), requests.Load())
+	assert.NotEmpty(suite.T(), expvar.Get("binance_futures_banned_until").String())
+
+	if time.Now().Second() > 0 {
+		assert.Equal(suite.T(), 7, binance.GetFuturesUsedWeight())
+		assert.Equal(suite.T(), "7", expvar.Get("binance_futures_used_weight_1m").String())
+	}
+}
+
+func TestBinanceRateLimit(t *testing.T) {
+	suite.Run(t, new(BinanceRateLimitTestSuite))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BinanceStreamTestSuite struct {
+	suite.Suite
+	server        *httptest.Server
+	mu            sync.Mutex
+	subscriptions [][]string
+}
+
+func (suite *BinanceStreamTestSuite) SetupTest() {
+	suite.subscriptions = nil
+	upgrader := websocket.Upgrader{}
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var request struct {
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		if err := conn.ReadJSON(&request); err != nil || request.Method != "SUBSCRIBE" {
+			return
+		}
+		suite.mu.Lock()
+		suite.subscriptions = append(suite.subscriptions, request.Params)
+		connection := len(suite.subscriptions)
+		suite.mu.Unlock()
+
+		conn.WriteJSON(map[string]any{"result": nil, "id": 1})
+		openTime := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC).UnixMilli()
+		if connection == 1 {
+			// forming candle, then the socket drops
+			conn.WriteMessage(websocket.TextMessage, klineEvent("BTCUSDT", openTime, "100", "105", false))
+			return
+		}
+		conn.WriteMessage(websocket.TextMessage, klineEvent("BTCUSDT", openTime, "100", "110", true))
+		time.Sleep(time.Second)
+	}))
+	os.Setenv("BINANCE_STREAM_URL", "ws"+strings.TrimPrefix(suite.server.URL, "http"))
+}
+
+func (suite *BinanceStreamTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_STREAM_URL")
+}
+
+func (suite *BinanceStreamTestSuite) TestShouldUpsertFormingCandleAndCompleteItAfterReconnect() {
+	records := make(chan *dto.DataDto, 2)
+	stream := binance.NewKlineStream([]string{"btc"}, "1h", func(currency string, record *dto.DataDto) {
+		assert.Equal(suite.T(), "btc", currency)
+		records <- record
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go stream.Run(ctx)
+
+	forming := suite.receive(ctx, records)
+	assert.False(suite.T(), forming.IsComplete)
+	assertDecimal(suite.T(), 105.0, forming.Close)
+
+	closed := suite.receive(ctx, records)
+	assert.True(suite.T(), closed.IsComplete)
+	assertDecimal(suite.T(), 110.0, closed.Close)
+	assert.Equal(suite.T(), "BTCUSDT", closed.Symbol)
+	assert.Equal(suite.T(), "1h", closed.Timeframe)
+	assert.True(suite.T(), forming.Timestamp.Equal(closed.Timestamp))
+
+	suite.mu.Lock()
+	defer suite.mu.Unlock()
+	assert.Len(suite.T(), suite.subscriptions, 2)
+	for _, params := range suite.subscriptions {
+		assert.Equal(suite.T(), []string{"btcusdt@kline_1h"}, params)
+	}
+}
+
+func (suite *BinanceStreamTestSuite) receive(ctx context.Context, records <-chan *dto.DataDto) *dto.DataDto {
+	select {
+	case record := <-records:
+		return record
+	case <-ctx.Done():
+		suite.FailNow("timed out waiting for kline")
+		return nil
+	}
+}
+
+func klineEvent(symbol string, openTime int64, open, close string, closed bool) []byte {
+	event, _ := json.Marshal(dto.BinanceKlineEvent{
+		EventType: "kline",
+		Symbol:    symbol,
+		Kline: dto.BinanceStreamKline{
+			OpenTime: openTime,
+			Interval: "1h",
+			Open:     open,
+			Close:    close,
+			High:     close,
+			Low:      open,
+			Volume:   "10",
+			IsClosed: closed,
+		},
+	})
+	return event
+}
+
+func TestBinanceStream(t *testing.T) {
+	suite.Run(t, new(BinanceStreamTestSuite))
+}
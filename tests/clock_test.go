@@ -0,0 +1,110 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ClockTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	local  *time.Location
+}
+
+func (suite *ClockTestSuite) SetupTest() {
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/time":
+			w.Write([]byte(`{"serverTime":1735689600123}`))
+		case "/klines":
+			w.Write([]byte(`[[1735689600000,"100.0","102.0","99.0","101.0","4.5",1735693199999,"453.5",7,"2.0","199.0","0"]]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.URL+"/")
+	suite.local = time.Local
+}
+
+func (suite *ClockTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+	clock.SetOffset(0)
+	time.Local = suite.local
+}
+
+func (suite *ClockTestSuite) TestShouldFindNextBoundary() {
+	hour := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		now      time.Time
+		interval time.Duration
+		expected time.Time
+	}{
+		{name: "before the delay", now: hour.Add(2 * time.Second), interval: time.Hour, expected: hour.Add(5 * time.Second)},
+		{name: "at the delay", now: hour.Add(5 * time.Second), interval: time.Hour, expected: hour.Add(time.Hour + 5*time.Second)},
+		{name: "mid hour", now: hour.Add(30 * time.Minute), interval: time.Hour, expected: hour.Add(time.Hour + 5*time.Second)},
+		{name: "four hours", now: hour.Add(30 * time.Minute), interval: 4 * time.Hour, expected: hour.Add(2*time.Hour + 5*time.Second)},
+		{name: "local zone", now: hour.Add(30 * time.Minute).In(time.FixedZone("UTC+5:30", 19800)), interval: time.Hour, expected: hour.Add(time.Hour + 5*time.Second)},
+	}
+
+	for _, tc := range testCases {
+		next := clock.NextBoundary(tc.now, tc.interval, 5*time.Second)
+		assert.True(suite.T(), tc.expected.Equal(next), "%s: %s", tc.name, next)
+		assert.Equal(suite.T(), time.UTC, next.Location(), tc.name)
+	}
+}
+
+func (suite *ClockTestSuite) TestShouldSyncOffsetWithServerTime() {
+	offset, err := clock.Sync(func() (time.Time, error) {
+		return time.Now().Add(3 * time.Second), nil
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.InDelta(suite.T(), float64(3*time.Second), float64(offset), float64(50*time.Millisecond))
+	assert.Equal(suite.T(), offset, clock.Offset())
+	assert.WithinDuration(suite.T(), time.Now().Add(3*time.Second), clock.Now(), 50*time.Millisecond)
+	assert.Equal(suite.T(), time.UTC, clock.Now().Location())
+}
+
+func (suite *ClockTestSuite) TestShouldFetchBinanceServerTime() {
+	serverTime, err := binance.FetchServerTime()
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), time.UnixMilli(1735689600123).UTC(), serverTime)
+}
+
+func (suite *ClockTestSuite) TestShouldKeepSyncedOffsetWhenServerTimeFails() {
+	suite.server.Close()
+	clock.SetOffset(time.Second)
+
+	_, err := clock.Sync(binance.FetchServerTime)
+
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), time.Second, clock.Offset())
+}
+
+func (suite *ClockTestSuite) TestShouldMapKlineTimestampsToUtc() {
+	time.Local = time.FixedZone("UTC+6", 6*3600)
+	hour := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	records, err := binance.FetchKlineRange("btc", "1h", hour, hour, 1)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), hour, records[0].Timestamp)
+	assert.True(suite.T(), records[0].IsComplete)
+}
+
+func TestClock(t *testing.T) {
+	suite.Run(t, new(ClockTestSuite))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/composite"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CompositeTestSuite struct {
+	suite.Suite
+	hour time.Time
+}
+
+func (suite *CompositeTestSuite) SetupTest() {
+	suite.hour = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (suite *CompositeTestSuite) venue(open, high, low, close, volume float64) *dto.DataDto {
+	return &dto.DataDto{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: suite.hour, Open: decimal.NewFromFloat(open), High: decimal.NewFromFloat(high), Low: decimal.NewFromFloat(low), Close: decimal.NewFromFloat(close), Volume: decimal.NewFromFloat(volume), TradeCount: 10, IsComplete: true}
+}
+
+func (suite *CompositeTestSuite) TestShouldWeightVenuesByVolume() {
+	venues := []*dto.DataDto{
+		suite.venue(100, 110, 90, 100, 3),
+		suite.venue(104, 114, 94, 104, 1),
+	}
+
+	candle := composite.BuildCandle("BTCUSDT", "1h_composite", venues, 0.05)
+
+	assert.Equal(suite.T(), "1h_composite", candle.Timeframe)
+	assert.Equal(suite.T(), suite.hour, candle.Timestamp)
+	assert.InDelta(suite.T(), 101, candle.Open.InexactFloat64(), 1e-9)
+	assert.InDelta(suite.T(), 111, candle.High.InexactFloat64(), 1e-9)
+	assert.InDelta(suite.T(), 91, candle.Low.InexactFloat64(), 1e-9)
+	assert.InDelta(suite.T(), 101, candle.Close.InexactFloat64(), 1e-9)
+	assert.InDelta(suite.T(), 101, candle.Vwap.InexactFloat64(), 1e-9)
+	assertDecimal(suite.T(), 4.0, candle.Volume)
+	assert.Equal(suite.T(), int64(20), candle.TradeCount)
+	assertDecimal(suite.T(), 102.0, candle.MedianPrice)
+	assert.True(suite.T(), candle.IsComplete)
+}
+
+func (suite *CompositeTestSuite) TestShouldIgnoreOutlierVenues() {
+	venues := []*dto.DataDto{
+		suite.venue(100, 101, 99, 100, 1),
+		suite.venue(101, 102, 100, 101, 1),
+		suite.venue(150, 151, 149, 150, 100),
+	}
+
+	candle := composite.BuildCandle("BTCUSDT", "1h_composite", venues, 0.01)
+
+	assertDecimal(suite.T(), 101.0, candle.MedianPrice)
+	assert.InDelta(suite.T(), 100.5, candle.Close.InexactFloat64(), 1e-9)
+	assertDecimal(suite.T(), 2.0, candle.Volume)
+}
+
+func (suite *CompositeTestSuite) TestShouldWeightVenuesEquallyWithoutVolume() {
+	venues := []*dto.DataDto{
+		suite.venue(100, 100, 100, 100, 0),
+		suite.venue(102, 102, 102, 102, 0),
+	}
+	venues[1].IsComplete = false
+
+	candle := composite.BuildCandle("BTCUSDT", "1h_composite", venues, 0.05)
+
+	assert.InDelta(suite.T(), 101, candle.Close.InexactFloat64(), 1e-9)
+	assert.False(suite.T(), candle.IsComplete)
+}
+
+func (suite *CompositeTestSuite) TestShouldKeepBothVenuesWhenTwoDisagree() {
+	venues := []*dto.DataDto{
+		suite.venue(100, 100, 100, 100, 1),
+		suite.venue(200, 200, 200, 200, 1),
+	}
+
+	candle := composite.BuildCandle("BTCUSDT", "1h_composite", venues, 0.01)
+
+	assertDecimal(suite.T(), 150.0, candle.MedianPrice)
+	assert.InDelta(suite.T(), 150, candle.Close.InexactFloat64(), 1e-9)
+}
+
+func (suite *CompositeTestSuite) TestShouldReturnNilWithoutVenues() {
+	assert.Nil(suite.T(), composite.BuildCandle("BTCUSDT", "1h_composite", nil, 0.01))
+}
+
+func (suite *CompositeTestSuite) TestShouldStoreCompositeCandlesInTheirOwnPartitions() {
+	assert.Equal(suite.T(), "4h_composite", utils.GetCompositeTimeframe("4h"))
+	assert.Equal(suite.T(), "1h_composite", utils.GetBaseTimeframe("1d_composite"))
+	assert.Equal(suite.T(), "1h", utils.GetBaseTimeframe("1d"))
+	assert.Nil(suite.T(), validator.NewValidator().ValidateCurrencyAndTimeframe("btc", "4h_composite"))
+	assert.NotNil(suite.T(), validator.NewValidator().ValidateCurrencyAndTimeframe("btc", "4h_unknown"))
+}
+
+func TestComposite(t *testing.T) {
+	suite.Run(t, new(CompositeTestSuite))
+}
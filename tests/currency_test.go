@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CurrencyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CurrencyTestSuite) TearDownTest() {
+	instrument.SetCurrencies(nil)
+}
+
+func (suite *CurrencyTestSuite) TestShouldTrackDefaultCurrenciesUntilTheRegistryIsLoaded() {
+	assert.Equal(suite.T(), config.DefaultCurrencies, instrument.TrackedKeys())
+}
+
+func (suite *CurrencyTestSuite) TestShouldTrackActiveCurrenciesOfTheRegistry() {
+	instrument.SetCurrencies(map[string]string{
+		"eth":     config.CURRENCY_STATUS_ACTIVE,
+		"btc":     config.CURRENCY_STATUS_ACTIVE,
+		"sol_btc": config.CURRENCY_STATUS_PAUSED,
+	})
+
+	assert.Equal(suite.T(), []string{"btc", "eth"}, instrument.TrackedKeys())
+}
+
+func (suite *CurrencyTestSuite) TestShouldValidateCurrenciesFromTheRegistry() {
+	currencyValidator := validator.NewValidator()
+	instrument.SetCurrencies(map[string]string{"btc": config.CURRENCY_STATUS_ACTIVE})
+
+	assert.NotNil(suite.T(), currencyValidator.ValidateCurrencyAndTimeframe("eth", "1h"))
+
+	instrument.Register("eth", config.CURRENCY_STATUS_ACTIVE)
+	assert.Nil(suite.T(), currencyValidator.ValidateCurrencyAndTimeframe("eth", "1h"))
+	assert.NotNil(suite.T(), currencyValidator.ValidateCurrencyAndTimeframe("eth", "2h"))
+
+	instrument.Unregister("eth")
+	assert.NotNil(suite.T(), currencyValidator.ValidateCurrencyAndTimeframe("eth", "1h"))
+}
+
+func (suite *CurrencyTestSuite) TestShouldKeepServingPausedCurrencies() {
+	instrument.SetCurrencies(map[string]string{"btc": config.CURRENCY_STATUS_ACTIVE})
+	instrument.Register("eth", config.CURRENCY_STATUS_PAUSED)
+
+	status, ok := instrument.CurrencyStatus("eth")
+	assert.True(suite.T(), ok)
+	assert.Equal(suite.T(), config.CURRENCY_STATUS_PAUSED, status)
+	assert.NotContains(suite.T(), instrument.TrackedKeys(), "eth")
+	assert.Nil(suite.T(), validator.NewValidator().ValidateCurrencyAndTimeframe("eth", "4h"))
+}
+
+func (suite *CurrencyTestSuite) TestShouldStopTrackingRemovedDiscoveredCurrencies() {
+	instrument.Enable("ada")
+	assert.Contains(suite.T(), instrument.TrackedKeys(), "ada")
+
+	instrument.Disable("ada")
+	assert.NotContains(suite.T(), instrument.TrackedKeys(), "ada")
+}
+
+func TestCurrency(t *testing.T) {
+	suite.Run(t, new(CurrencyTestSuite))
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertDecimal compares a decimal against a float literal by value
+func assertDecimal(t assert.TestingT, expected float64, actual decimal.Decimal) bool {
+	return assertDecimalEqual(t, decimal.NewFromFloat(expected), actual)
+}
+
+// assertDecimalEqual compares decimals by value, assert.Equal would also compare their exponent
+func assertDecimalEqual(t assert.TestingT, expected decimal.Decimal, actual decimal.Decimal) bool {
+	return assert.Truef(t, expected.Equal(actual), "expected %s, actual %s", expected, actual)
+}
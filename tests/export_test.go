@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/export"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// exports run on STORAGE_BACKEND=memory, three 1h candles of which the first two have indicators
+type ExportTestSuite struct {
+	suite.Suite
+	exportService *export.ExportService
+	start         time.Time
+}
+
+type exportedParquetRow struct {
+	Timestamp time.Time `parquet:"timestamp,timestamp(millisecond)"`
+	Close     float64   `parquet:"close"`
+	Sma       *float64  `parquet:"sma,optional"`
+}
+
+func (suite *ExportTestSuite) SetupSuite() {
+	os.Setenv("STORAGE_BACKEND", "memory")
+}
+
+func (suite *ExportTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+	memory.DB.Reset()
+}
+
+func (suite *ExportTestSuite) SetupTest() {
+	memory.DB.Reset()
+	suite.exportService = export.NewExportService()
+	suite.start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var records []*dto.DataDto
+	var indicators []*dto.IndicatorDto
+	for i := 0; i < 3; i++ {
+		timestamp := suite.start.Add(time.Duration(i) * time.Hour)
+		record := memoryCandle(timestamp, 100+float64(i), 101+float64(i), true)
+		record.Close = decimal.RequireFromString("101.000000001").Add(decimal.NewFromInt(int64(i)))
+		records = append(records, record)
+		if i < 2 {
+			indicators = append(indicators, &dto.IndicatorDto{Timeframe: "1h", Timestamp: timestamp, DataTimestamp: timestamp, SMA: 100.5 + float64(i)})
+		}
+	}
+	memory.DB.UpsertCandles("btc", "1h", records)
+	memory.DB.UpsertIndicators("btc", "1h", indicators)
+}
+
+func (suite *ExportTestSuite) TestShouldExportSelectedColumnsAsCsv() {
+	var output bytes.Buffer
+	count, err := suite.exportService.Export(context.Background(), dto.ExportRequestDto{
+		Currency: "btc", Timeframe: "1h", Format: "csv", Columns: []string{"timestamp", "close", "sma"},
+	}, &output)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), count)
+	records, err := csv.NewReader(&output).ReadAll()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), [][]string{
+		{"timestamp", "close", "sma"},
+		{"2025-01-01T00:00:00Z", "101.000000001", "100.5"},
+		{"2025-01-01T01:00:00Z", "102.000000001", "101.5"},
+		{"2025-01-01T02:00:00Z", "103.000000001", ""},
+	}, records)
+}
+
+func (suite *ExportTestSuite) TestShouldExportTimeRangeAsNdjsonWithUnixTimestamps() {
+	from := suite.start.Add(time.Hour)
+	var output bytes.Buffer
+	count, err := suite.exportService.Export(context.Background(), dto.ExportRequestDto{
+		Currency: "btc", Timeframe: "1h", StartTime: &from, Format: "ndjson", TimeFormat: "unix_ms", Columns: []string{"timestamp", "symbol", "close", "is_complete", "rsi"},
+	}, &output)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), count)
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Len(suite.T(), lines, 2)
+	assert.Equal(suite.T(), `{"timestamp":1735693200000,"symbol":"BTCUSDT","close":102.000000001,"is_complete":true,"rsi":0}`, lines[0])
+
+	var last map[string]any
+	assert.NoError(suite.T(), json.Unmarshal([]byte(lines[1]), &last))
+	assert.Nil(suite.T(), last["rsi"])
+}
+
+func (suite *ExportTestSuite) TestShouldExportParquet() {
+	var output bytes.Buffer
+	count, err := suite.exportService.Export(context.Background(), dto.ExportRequestDto{
+		Currency: "btc", Timeframe: "1h", Format: "parquet",
+	}, &output)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), count)
+
+	rows, err := parquet.Read[exportedParquetRow](bytes.NewReader(output.Bytes()), int64(output.Len()))
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), rows, 3)
+	assert.True(suite.T(), rows[0].Timestamp.Equal(suite.start))
+	assert.InDelta(suite.T(), 101.000000001, rows[0].Close, 1e-9)
+	assert.InDelta(suite.T(), 100.5, *rows[0].Sma, 0.0001)
+	assert.Nil(suite.T(), rows[2].Sma)
+}
+
+func (suite *ExportTestSuite) TestShouldRejectUnknownColumnsAndFormats() {
+	var output bytes.Buffer
+	_, err := suite.exportService.Export(context.Background(), dto.ExportRequestDto{Currency: "btc", Timeframe: "1h", Columns: []string{"close", "price"}}, &output)
+	assert.ErrorContains(suite.T(), err, "unknown columns")
+
+	_, err = suite.exportService.Export(context.Background(), dto.ExportRequestDto{Currency: "btc", Timeframe: "1h", Format: "xlsx"}, &output)
+	assert.ErrorContains(suite.T(), err, "unknown export format")
+
+	_, err = suite.exportService.Export(context.Background(), dto.ExportRequestDto{Currency: "btc", Timeframe: "1h", TimeFormat: "local"}, &output)
+	assert.ErrorContains(suite.T(), err, "unknown time format")
+	assert.Zero(suite.T(), output.Len())
+}
+
+func TestExport(t *testing.T) {
+	suite.Run(t, new(ExportTestSuite))
+}
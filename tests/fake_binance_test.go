@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata/quality"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/fakebinance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FakeBinanceTestSuite struct {
+	suite.Suite
+	server *fakebinance.Server
+	day    time.Time
+}
+
+func (suite *FakeBinanceTestSuite) SetupTest() {
+	suite.day = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.server = fakebinance.NewServer(fakebinance.Options{Seed: 42, StreamInterval: 50 * time.Millisecond})
+	url := suite.server.Start()
+	os.Setenv("BINANCE_BASE_API_URL", url+"/api/v3/")
+	os.Setenv("BINANCE_STREAM_URL", "ws"+strings.TrimPrefix(url, "http")+"/ws")
+	os.Setenv("HTTP_RETRY_BASE_DELAY", "10")
+	os.Setenv("HTTP_RETRY_MAX_DELAY", "50")
+}
+
+func (suite *FakeBinanceTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+	os.Unsetenv("BINANCE_STREAM_URL")
+	os.Unsetenv("HTTP_RETRY_BASE_DELAY")
+	os.Unsetenv("HTTP_RETRY_MAX_DELAY")
+	os.Unsetenv("HTTP_REQUEST_TIMEOUT")
+}
+
+func (suite *FakeBinanceTestSuite) TestShouldServeDeterministicKlines() {
+	records, err := binance.FetchKlineRange("btc", "1h", suite.day, suite.day.Add(23*time.Hour), 1000)
+	assert.NoError(suite.T(), err)
+	again, _ := binance.FetchKlineRange("btc", "1h", suite.day.Add(12*time.Hour), suite.day.Add(12*time.Hour), 1)
+
+	assert.Len(suite.T(), records, 24)
+	assertDecimalEqual(suite.T(), records[12].Close, again[0].Close)
+	for i, record := range records {
+		assert.Equal(suite.T(), suite.day.Add(time.Duration(i)*time.Hour), record.Timestamp)
+		assert.True(suite.T(), record.IsComplete)
+		assert.Empty(suite.T(), quality.CheckCandle(record, time.Now()))
+		if i > 0 {
+			assertDecimalEqual(suite.T(), records[i-1].Close, record.Open)
+		}
+	}
+
+	day, _ := binance.FetchKlineRange("btc", "1d", suite.day, suite.day, 1)
+	assertDecimalEqual(suite.T(), records[0].Open, day[0].Open)
+	assertDecimalEqual(suite.T(), records[23].Close, day[0].Close)
+}
+
+func (suite *FakeBinanceTestSuite) TestShouldLeaveOutMissingHours() {
+	suite.server.DropHours(suite.day.Add(3*time.Hour), suite.day.Add(4*time.Hour))
+
+	records, err := binance.FetchKlineRange("btc", "1h", suite.day, suite.day.Add(23*time.Hour), 1000)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 22)
+	assert.Equal(suite.T(), suite.day.Add(5*time.Hour), records[3].Timestamp)
+}
+
+func (suite *FakeBinanceTestSuite) TestShouldRetryServerErrorBurst() {
+	suite.server.Inject(fakebinance.Fault{Kind: fakebinance.FaultServerError, Endpoint: "klines", Times: 2, Status: 502})
+
+	records, err := binance.FetchKline("btc", "1h", 3)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 3)
+	assert.Equal(suite.T(), 3, suite.server.Requests("klines"))
+}
+
+func (suite *FakeBinanceTestSuite) TestShouldWaitForRetryAfter() {
+	suite.server.Inject(fakebinance.Fault{Kind: fakebinance.FaultRateLimit, Endpoint: "ticker/24hr", RetryAfter: 1})
+	startTime := time.Now()
+
+	ticker, err := binance.FetchTickerSnapshot("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), ticker.LastPrice.IsPositive())
+	assert.GreaterOrEqual(suite.T(), time.Since(startTime), 900*time.Millisecond)
+	assert.Equal(suite.T(), 2, suite.server.Requests("ticker/24hr"))
+}
+
+func (suite *FakeBinanceTestSuite) TestShouldRetrySlowResponse() {
+	os.Setenv("HTTP_REQUEST_TIMEOUT", "1")
+	suite.server.Inject(fakebinance.Fault{Kind: fakebinance.FaultSlow, Endpoint: "time", Delay: 1500 * time.Millisecond})
+
+	serverTime, err := binance.FetchServerTime()
+
+	assert.NoError(suite.T(), err)
+	assert.WithinDuration(suite.T(), time.Now(), serverTime, time.Second)
+	assert.Equal(suite.T(), 2, suite.server.Requests("time"))
+}
+
+func (suite *FakeBinanceTestSuite) TestShouldFailOnTruncatedJson() {
+	suite.server.Inject(fakebinance.Fault{Kind: fakebinance.FaultTruncated, Endpoint: "exchangeInfo"})
+
+	_, err := binance.FetchExchangeInfo()
+	assert.Error(suite.T(), err)
+
+	symbols, err := binance.FetchExchangeInfo()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), symbols, 2)
+	assert.Equal(suite.T(), "BTCUSDT", symbols[0].Symbol)
+	assert.Equal(suite.T(), "USDT", symbols[0].QuoteAsset)
+	assert.Equal(suite.T(), "TRADING", symbols[0].Status)
+	assertDecimal(suite.T(), 0.01, symbols[0].TickSize)
+}
+
+func (suite *FakeBinanceTestSuite) TestShouldServeFixtureFiles() {
+	suite.server.Close()
+	suite.server = fakebinance.NewServer(fakebinance.Options{FixturesDir: "fixtures/binance"})
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.Start()+"/api/v3/")
+
+	ticker, err := binance.FetchTickerSnapshot("btc")
+
+	assert.NoError(suite.T(), err)
+	assertDecimal(suite.T(), 93505.0, ticker.LastPrice)
+}
+
+func (suite *FakeBinanceTestSuite) TestShouldStreamFormingKlines() {
+	records := make(chan *dto.DataDto, 10)
+	stream := binance.NewKlineStream([]string{"btc"}, "1h", func(currency string, record *dto.DataDto) {
+		records <- record
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	go stream.Run(ctx)
+
+	select {
+	case record := <-records:
+		assert.Equal(suite.T(), "BTCUSDT", record.Symbol)
+		assert.Equal(suite.T(), time.Now().UTC().Truncate(time.Hour), record.Timestamp)
+		assert.False(suite.T(), record.IsComplete)
+		assert.True(suite.T(), record.Close.IsPositive())
+	case <-ctx.Done():
+		suite.FailNow("timed out waiting for kline")
+	}
+}
+
+func (suite *FakeBinanceTestSuite) TestShouldParseFaultScript() {
+	faults, err := fakebinance.ParseFaults("klines:429:1:2, *:5xx:3:502,ticker/24hr:slow:1:2s,exchangeInfo:truncated")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []fakebinance.Fault{
+		{Kind: fakebinance.FaultRateLimit, Endpoint: "klines", Times: 1, RetryAfter: 2},
+		{Kind: fakebinance.FaultServerError, Times: 3, Status: 502},
+		{Kind: fakebinance.FaultSlow, Endpoint: "ticker/24hr", Times: 1, Delay: 2 * time.Second},
+		{Kind: fakebinance.FaultTruncated, Endpoint: "exchangeInfo", Times: 1},
+	}, faults)
+
+	for _, script := range []string{"klines", "klines:teapot", "klines:5xx:0", "klines:slow:1"} {
+		_, err := fakebinance.ParseFaults(script)
+		assert.Error(suite.T(), err, script)
+	}
+}
+
+func TestFakeBinance(t *testing.T) {
+	suite.Run(t, new(FakeBinanceTestSuite))
+}
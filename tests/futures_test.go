@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type FuturesTestSuite struct {
+	suite.Suite
+	server  *httptest.Server
+	queries map[string]string // endpoint => query
+	hour    time.Time
+}
+
+func (suite *FuturesTestSuite) SetupTest() {
+	suite.hour = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.queries = make(map[string]string)
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := path.Base(r.URL.Path)
+		suite.queries[endpoint] = r.URL.RawQuery
+		fixture, err := os.ReadFile("fixtures/binance/futures/" + endpoint + ".json")
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(fixture)
+	}))
+	os.Setenv("BINANCE_FUTURES_BASE_API_URL", suite.server.URL+"/fapi/v1/")
+}
+
+func (suite *FuturesTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_FUTURES_BASE_API_URL")
+}
+
+func (suite *FuturesTestSuite) TestShouldFetchFundingRates() {
+	records, err := binance.FetchFundingRates("btc", suite.hour, suite.hour.Add(24*time.Hour))
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "endTime=1735776000000&limit=1000&startTime=1735689600000&symbol=BTCUSDT", suite.queries["fundingRate"])
+	assert.Len(suite.T(), records, 2)
+	assert.Equal(suite.T(), "BTCUSDT", records[0].Symbol)
+	assert.Equal(suite.T(), suite.hour, records[0].Timestamp)
+	assertDecimal(suite.T(), 0.0001, records[0].FundingRate)
+	assertDecimal(suite.T(), 93429.12, records[0].MarkPrice)
+	assertDecimal(suite.T(), -0.000025, records[1].FundingRate)
+}
+
+func (suite *FuturesTestSuite) TestShouldFetchOpenInterest() {
+	record, err := binance.FetchOpenInterest("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "symbol=BTCUSDT", suite.queries["openInterest"])
+	assertDecimal(suite.T(), 81234.567, record.OpenInterest)
+	assert.Equal(suite.T(), time.UnixMilli(1735690215123).UTC(), record.Timestamp)
+}
+
+func (suite *FuturesTestSuite) TestShouldFetchMarkPriceKlines() {
+	records, err := binance.FetchMarkPriceKlines("btc", "1h", suite.hour, suite.hour.Add(time.Hour-time.Millisecond), 1)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "endTime=1735693199999&interval=1h&limit=1&startTime=1735689600000&symbol=BTCUSDT", suite.queries["markPriceKlines"])
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), suite.hour, records[0].Timestamp)
+	assertDecimal(suite.T(), 93429.12, records[0].Open)
+	assertDecimal(suite.T(), 93600.0, records[0].High)
+	assertDecimal(suite.T(), 93300.1, records[0].Low)
+	assertDecimal(suite.T(), 93550.4, records[0].Close)
+	assert.True(suite.T(), records[0].IsComplete)
+}
+
+func (suite *FuturesTestSuite) TestShouldRejectMalformedMarkPriceKline() {
+	suite.server.Close()
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[[1735689600000,"93429.12","93600.00","93300.10","93550.40","0","1735693199999","0",60,"0","0","0"]]`))
+	}))
+	os.Setenv("BINANCE_FUTURES_BASE_API_URL", suite.server.URL+"/fapi/v1/")
+
+	_, err := binance.FetchMarkPriceKlines("btc", "1h", suite.hour, suite.hour.Add(time.Hour-time.Millisecond), 1)
+
+	assert.ErrorContains(suite.T(), err, "unexpected response format: open time is float64, close time is string")
+}
+
+func (suite *FuturesTestSuite) TestShouldFailOnErrorStatus() {
+	suite.server.Close()
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	os.Setenv("BINANCE_FUTURES_BASE_API_URL", suite.server.URL+"/fapi/v1/")
+
+	_, err := binance.FetchOpenInterest("btc")
+
+	assert.ErrorContains(suite.T(), err, "binance futures API returned status: 400")
+}
+
+func TestFutures(t *testing.T) {
+	suite.Run(t, new(FuturesTestSuite))
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/gap"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/fakebinance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// Test Suite Struct, the gaps are repaired from the fake binance server into STORAGE_BACKEND=memory
+type GapServiceTestSuite struct {
+	suite.Suite
+	server            *fakebinance.Server
+	marketDataService *marketdata.MarketDataService
+	gapService        *gap.GapService
+	start             time.Time
+	last              time.Time
+}
+
+func (suite *GapServiceTestSuite) SetupSuite() {
+	os.Setenv("STORAGE_BACKEND", "memory")
+
+	mockRedis := &MockRedisService{}
+	mockRedis.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
+
+	suite.marketDataService = marketdata.NewMarketDataService(mockRedis)
+	suite.gapService = gap.NewGapService(suite.marketDataService, indicator.NewIndicatorService(mockRedis))
+}
+
+func (suite *GapServiceTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+	memory.DB.Reset()
+}
+
+func (suite *GapServiceTestSuite) SetupTest() {
+	memory.DB.Reset()
+	suite.server = fakebinance.NewServer(fakebinance.Options{Seed: 7})
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.Start()+"/api/v3/")
+
+	// two complete days and the hours of the current one, the first record opens a 1d group
+	suite.last = clock.Now().Truncate(time.Hour).Add(-time.Hour)
+	suite.start = suite.last.Truncate(24 * time.Hour).Add(-47 * time.Hour)
+}
+
+func (suite *GapServiceTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+	os.Unsetenv("GAP_MAX_ATTEMPTS")
+}
+
+func (suite *GapServiceTestSuite) TestShouldDetectAndFillMissingHours() {
+	suite.seed(10, 11, 30)
+
+	report, err := suite.gapService.ScanAndRepair("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, report.MissingHours)
+	assert.Equal(suite.T(), 3, report.FilledHours)
+	assert.Empty(suite.T(), report.Unfilled)
+	assert.Len(suite.T(), memory.DB.Candles("btc", config.ONE_HOUR), suite.hours())
+
+	gaps, err := suite.gapService.GetGaps("btc")
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), gaps)
+}
+
+func (suite *GapServiceTestSuite) TestShouldRecordGapsThatCannotBeFilled() {
+	suite.seed(10, 11, 12, 30)
+	suite.server.DropHours(suite.hour(11), suite.hour(12))
+
+	report, err := suite.gapService.ScanAndRepair("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4, report.MissingHours)
+	assert.Equal(suite.T(), 2, report.FilledHours)
+	assert.Len(suite.T(), report.Unfilled, 1)
+
+	gaps, err := suite.gapService.GetGaps("btc")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), gaps, 1)
+	assert.Equal(suite.T(), suite.hour(11), gaps[0].StartTime)
+	assert.Equal(suite.T(), suite.hour(12), gaps[0].EndTime)
+	assert.Equal(suite.T(), 1, gaps[0].Attempts)
+
+	// the next scan tries again and counts the attempt
+	_, err = suite.gapService.ScanAndRepair("btc")
+	assert.NoError(suite.T(), err)
+	again, _ := suite.gapService.GetGaps("btc")
+	assert.Len(suite.T(), again, 1)
+	assert.Equal(suite.T(), 2, again[0].Attempts)
+	assert.Equal(suite.T(), gaps[0].DetectedAt, again[0].DetectedAt)
+}
+
+func (suite *GapServiceTestSuite) TestShouldStopRetryingGapAfterMaxAttempts() {
+	os.Setenv("GAP_MAX_ATTEMPTS", "2")
+	suite.seed(20)
+	suite.server.DropHours(suite.hour(20))
+
+	for range 2 {
+		_, err := suite.gapService.ScanAndRepair("btc")
+		assert.NoError(suite.T(), err)
+	}
+	requests := suite.server.Requests("klines")
+
+	report, err := suite.gapService.ScanAndRepair("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), requests, suite.server.Requests("klines"))
+	assert.Equal(suite.T(), 0, report.FilledHours)
+	assert.Len(suite.T(), report.Unfilled, 1)
+	gaps, _ := suite.gapService.GetGaps("btc")
+	assert.Equal(suite.T(), 2, gaps[0].Attempts)
+}
+
+func (suite *GapServiceTestSuite) TestShouldRegroupOnlyTheGroupsOfRepairedHours() {
+	suite.seed()
+	assert.NoError(suite.T(), suite.marketDataService.StoreGroupedRecords("btc", config.FOUR_HOUR))
+	assert.NoError(suite.T(), suite.marketDataService.StoreGroupedRecords("btc", config.ONE_DAY))
+	// the stored groups are zeroed, only the ones that are rebuilt get their volume back
+	for _, timeframe := range []string{config.FOUR_HOUR, config.ONE_DAY} {
+		groups := memory.DB.Candles("btc", timeframe)
+		assert.NotEmpty(suite.T(), groups)
+		for i := range groups {
+			groups[i].Volume = decimal.Zero
+			memory.DB.UpsertCandles("btc", timeframe, []*dto.DataDto{&groups[i]})
+		}
+	}
+	repaired := suite.hour(5)
+	memory.DB.DeleteCandles("btc", config.ONE_HOUR, func(record dto.DataDto) bool { return record.Timestamp.Equal(repaired) })
+
+	report, err := suite.gapService.ScanAndRepair("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, report.FilledHours)
+	fourHours := suite.rebuiltGroups(config.FOUR_HOUR)
+	assert.Len(suite.T(), fourHours, 1)
+	assert.Equal(suite.T(), suite.hour(7), fourHours[0].Timestamp)
+	assertDecimalEqual(suite.T(), suite.sumVolume(suite.hour(3), suite.hour(7)), fourHours[0].Volume)
+	days := suite.rebuiltGroups(config.ONE_DAY)
+	assert.Len(suite.T(), days, 1)
+	assert.Equal(suite.T(), suite.hour(23), days[0].Timestamp)
+	assertDecimalEqual(suite.T(), suite.sumVolume(suite.hour(-1), suite.hour(23)), days[0].Volume)
+}
+
+// seed stores the 1h records from the fake server, except the given hours counted from the first one
+func (suite *GapServiceTestSuite) seed(skipped ...int) {
+	records, err := binance.FetchKlineRange("btc", config.ONE_HOUR, suite.start, suite.last, 1000)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, suite.hours())
+
+	kept := make([]*dto.DataDto, 0, len(records))
+	for i, record := range records {
+		if !slices.Contains(skipped, i) {
+			kept = append(kept, record)
+		}
+	}
+	assert.NoError(suite.T(), suite.marketDataService.ImportBatchData("btc", kept))
+}
+
+func (suite *GapServiceTestSuite) hour(index int) time.Time {
+	return suite.start.Add(time.Duration(index) * time.Hour)
+}
+
+func (suite *GapServiceTestSuite) hours() int {
+	return int(suite.last.Sub(suite.start)/time.Hour) + 1
+}
+
+// rebuiltGroups returns the groups of the timeframe whose volume is not zeroed
+func (suite *GapServiceTestSuite) rebuiltGroups(timeframe string) []dto.DataDto {
+	var groups []dto.DataDto
+	for _, group := range memory.DB.Candles("btc", timeframe) {
+		if !group.Volume.IsZero() {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// sumVolume sums the volume of the 1h records in (after, until]
+func (suite *GapServiceTestSuite) sumVolume(after time.Time, until time.Time) decimal.Decimal {
+	volume := decimal.Zero
+	for _, record := range memory.DB.Candles("btc", config.ONE_HOUR) {
+		if record.Timestamp.After(after) && !record.Timestamp.After(until) {
+			volume = volume.Add(record.Volume)
+		}
+	}
+	return volume
+}
+
+func TestGapService(t *testing.T) {
+	suite.Run(t, new(GapServiceTestSuite))
+}
@@ -2,18 +2,27 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"log"
 	"net"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/backfill"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/currency"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/export"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/futures"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/gap"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/orderbook"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/ticker"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/trade"
 	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
-	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
 	mygrpc "github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/grpc"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
 	pb "github.com/chyngyz-sydykov/marketpulse/proto/marketpulse"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -25,39 +34,36 @@ import (
 // Test Suite Struct
 type GrpcTestSuite struct {
 	suite.Suite
-	db                *sql.DB
 	marketDataService *marketdata.MarketDataService
 	indicatorService  *indicator.IndicatorService
 	redisMock         *MockRedisService
 }
 
 func (suite *GrpcTestSuite) SetupSuite() {
-	err := database.ConnectDB()
-	if err != nil {
-		log.Fatal("❌ Failed to connect to database:", err)
-	}
+	os.Setenv("STORAGE_BACKEND", "memory")
 
 	mockRedis := &MockRedisService{}
 	mockRedis.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
 
 	suite.marketDataService = marketdata.NewMarketDataService(mockRedis)
 	suite.indicatorService = indicator.NewIndicatorService(mockRedis)
-	suite.db = database.DB
 	suite.redisMock = mockRedis
 }
 
-// Clear DB Before Each Test
+func (suite *GrpcTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+	memory.DB.Reset()
+}
+
+// Clear the store Before Each Test
 func (suite *GrpcTestSuite) SetupTest() {
-	_, err := suite.db.Exec("DELETE FROM data_btc; DELETE FROM indicator_btc;")
-	if err != nil {
-		log.Fatalf("❌ Failed to clean DB: %v", err)
-	}
+	memory.DB.Reset()
 }
 
 func (suite *GrpcTestSuite) TestGrpcMarketDataEndpointShouldReturnCorrectData() {
 	records := []*dto.DataDto{
-		{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: time.Date(2020, 1, 1, 1, 0, 0, 0, time.Now().Location()), Open: 110, High: 120, Low: 100, Close: 105, Volume: 111, Trend: -0.005, IsComplete: true},
-		{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: time.Date(2022, 1, 1, 1, 0, 0, 0, time.Now().Location()), Open: 211, High: 221, Low: 201, Close: 206, Volume: 223, IsComplete: true},
+		{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: time.Date(2020, 1, 1, 1, 0, 0, 0, time.Now().Location()), Open: decimal.NewFromFloat(110), High: decimal.NewFromFloat(120), Low: decimal.NewFromFloat(100), Close: decimal.NewFromFloat(105), Volume: decimal.NewFromFloat(111), Trend: decimal.NewFromFloat(-0.005), IsComplete: true},
+		{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: time.Date(2022, 1, 1, 1, 0, 0, 0, time.Now().Location()), Open: decimal.NewFromFloat(211), High: decimal.NewFromFloat(221), Low: decimal.NewFromFloat(201), Close: decimal.NewFromFloat(206), Volume: decimal.NewFromFloat(223), IsComplete: true},
 	}
 
 	suite.marketDataService.UpsertBatchData("btc", records)
@@ -66,16 +72,20 @@ func (suite *GrpcTestSuite) TestGrpcMarketDataEndpointShouldReturnCorrectData()
 		Symbol:     "BTCUSDT",
 		Timeframe:  "4h",
 		Timestamp:  time.Date(2020, 1, 1, 2, 0, 0, 0, time.Now().Location()),
-		Open:       310,
-		High:       320,
-		Low:        300,
-		Close:      305,
-		Volume:     311,
+		Open:       decimal.NewFromFloat(310),
+		High:       decimal.NewFromFloat(320),
+		Low:        decimal.NewFromFloat(300),
+		Close:      decimal.NewFromFloat(305),
+		Volume:     decimal.NewFromFloat(311),
 		IsComplete: false,
 	}
 
 	suite.marketDataService.StoreData("btc", record)
 
+	suite.marketDataService.ImportBatchData("btc", []*dto.DataDto{
+		{Symbol: "BTCUSDT", Timeframe: "1h_composite", Timestamp: time.Date(2020, 1, 1, 1, 0, 0, 0, time.Now().Location()), Open: decimal.NewFromFloat(112), High: decimal.NewFromFloat(121), Low: decimal.NewFromFloat(101), Close: decimal.NewFromFloat(106), Volume: decimal.NewFromFloat(350), MedianPrice: decimal.NewFromFloat(105.5), IsComplete: true},
+	})
+
 	listener, server := createInMemoryGrpcServer(suite)
 	defer server.Stop()
 
@@ -121,11 +131,24 @@ func (suite *GrpcTestSuite) TestGrpcMarketDataEndpointShouldReturnCorrectData()
 			request:   &pb.OHLCRequest{Currency: "btc", Timeframe: "1h"},
 			expectLen: 2,
 		},
+		{
+			name:      "Fetch composite 1H data",
+			request:   &pb.OHLCRequest{Currency: "btc", Timeframe: "1h", Source: "composite"},
+			expectLen: 1,
+			expectData: &pb.OHLCResponse{Records: []*pb.OHLCData{
+				{Currency: "BTCUSDT", Timeframe: "1h_composite", Open: 112, High: 121, Low: 101, Close: 106, Volume: 350, MedianPrice: 105.5, IsComplete: true},
+			}},
+		},
 		{
 			name:      "Invalid currency",
 			request:   &pb.OHLCRequest{Currency: "unknown currency", Timeframe: "1h"},
 			expectErr: "resource value(s) is invalid",
 		},
+		{
+			name:      "Invalid source",
+			request:   &pb.OHLCRequest{Currency: "btc", Timeframe: "1h", Source: "unknown source"},
+			expectErr: "resource value(s) is invalid",
+		},
 	}
 
 	// ✅ Loop through test cases
@@ -154,6 +177,7 @@ func (suite *GrpcTestSuite) TestGrpcMarketDataEndpointShouldReturnCorrectData()
 				assert.Equal(suite.T(), tc.expectData.Records[0].Close, response.Records[0].Close)
 				assert.Equal(suite.T(), tc.expectData.Records[0].Volume, response.Records[0].Volume)
 				assert.Equal(suite.T(), tc.expectData.Records[0].IsComplete, response.Records[0].IsComplete)
+				assert.Equal(suite.T(), tc.expectData.Records[0].MedianPrice, response.Records[0].MedianPrice)
 			}
 		})
 	}
@@ -162,18 +186,17 @@ func (suite *GrpcTestSuite) TestGrpcMarketDataEndpointShouldReturnCorrectData()
 func (suite *GrpcTestSuite) TestGrpcIndicatorEndpointShouldReturnCorrectData() {
 	// Arrange: Insert mock indicator data
 	records := []*dto.DataDto{
-		{Symbol: "BTCUSDT", Timeframe: "4h", Timestamp: time.Date(2020, 1, 1, 4, 0, 0, 0, time.Now().Location()), Open: 110, High: 120, Low: 100, Close: 105, Volume: 111, Trend: -0.005, IsComplete: true},
-		{Symbol: "BTCUSDT", Timeframe: "4h", Timestamp: time.Date(2020, 1, 1, 8, 0, 0, 0, time.Now().Location()), Open: 211, High: 221, Low: 201, Close: 206, Volume: 223, IsComplete: true},
+		{Symbol: "BTCUSDT", Timeframe: "4h", Timestamp: time.Date(2020, 1, 1, 4, 0, 0, 0, time.Now().Location()), Open: decimal.NewFromFloat(110), High: decimal.NewFromFloat(120), Low: decimal.NewFromFloat(100), Close: decimal.NewFromFloat(105), Volume: decimal.NewFromFloat(111), Trend: decimal.NewFromFloat(-0.005), IsComplete: true},
+		{Symbol: "BTCUSDT", Timeframe: "4h", Timestamp: time.Date(2020, 1, 1, 8, 0, 0, 0, time.Now().Location()), Open: decimal.NewFromFloat(211), High: decimal.NewFromFloat(221), Low: decimal.NewFromFloat(201), Close: decimal.NewFromFloat(206), Volume: decimal.NewFromFloat(223), IsComplete: true},
 	}
 
 	suite.marketDataService.UpsertBatchData("btc", records)
 
-	_, err := suite.db.Exec(`INSERT INTO indicator_btc_4h (timeframe, timestamp,data_timestamp, sma, ema, tr, std_dev, lower_bollinger, upper_bollinger, rsi, volatility, macd, macd_signal) VALUES 
-	('4h', '2020-01-01 04:00:00', '2020-01-01 04:00:00', 105, 107, 5, 2.5, 100, 110, 45, 0.03, 0.5, 0.3),
-	('4h', '2020-01-01 08:00:00', '2020-01-01 08:00:00', 205, 207, 6, 3.1, 200, 210, 50, 0.02, 0.6, 0.4)`)
-	if err != nil {
-		log.Fatalf("Failed to insert mock indicator data: %v", err)
-	}
+	memory.DB.UpsertIndicators("btc", "4h", []*dto.IndicatorDto{
+		{Timeframe: "4h", Timestamp: time.Date(2020, 1, 1, 4, 0, 0, 0, time.UTC), DataTimestamp: time.Date(2020, 1, 1, 4, 0, 0, 0, time.UTC), SMA: 105, EMA: 107, TR: 5, StdDev: 2.5, LowerBollinger: 100, UpperBollinger: 110, RSI: 45, Volatility: 0.03, MACD: 0.5, MACDSignal: 0.3},
+		{Timeframe: "4h", Timestamp: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC), DataTimestamp: time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC), SMA: 205, EMA: 207, TR: 6, StdDev: 3.1, LowerBollinger: 200, UpperBollinger: 210, RSI: 50, Volatility: 0.02, MACD: 0.6, MACDSignal: 0.4},
+	})
+
 	listener, server := createInMemoryGrpcServer(suite)
 	defer server.Stop()
 
@@ -264,7 +287,18 @@ func TestGrpcServer(t *testing.T) {
 func createInMemoryGrpcServer(suite *GrpcTestSuite) (net.Listener, *grpc.Server) {
 
 	server := grpc.NewServer()
-	pb.RegisterMarketPulseServer(server, mygrpc.NewGrpcService(suite.marketDataService, suite.indicatorService))
+	pb.RegisterMarketPulseServer(server, mygrpc.NewGrpcService(
+		suite.marketDataService,
+		suite.indicatorService,
+		backfill.NewBackfillService(suite.marketDataService, suite.indicatorService),
+		gap.NewGapService(suite.marketDataService, suite.indicatorService),
+		orderbook.NewOrderBookService(suite.redisMock),
+		trade.NewTradeService(suite.marketDataService),
+		futures.NewFuturesService(suite.redisMock),
+		ticker.NewTickerService(suite.redisMock),
+		currency.NewCurrencyService(),
+		export.NewExportService(),
+	))
 
 	listener, err := net.Listen("tcp", "localhost:11111")
 	if err != nil {
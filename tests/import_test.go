@@ -0,0 +1,180 @@
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/backfill"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// the dumps are written like data.binance.vision serves them, the services run on STORAGE_BACKEND=memory
+type ImportTestSuite struct {
+	suite.Suite
+	dir             string
+	backfillService *backfill.BackfillService
+	january         time.Time
+}
+
+func (suite *ImportTestSuite) SetupSuite() {
+	os.Setenv("STORAGE_BACKEND", "memory")
+}
+
+func (suite *ImportTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+	memory.DB.Reset()
+}
+
+func (suite *ImportTestSuite) SetupTest() {
+	memory.DB.Reset()
+	redisMock := &MockRedisService{}
+	redisMock.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
+	marketDataService := marketdata.NewMarketDataService(redisMock)
+	suite.backfillService = backfill.NewBackfillService(marketDataService, indicator.NewIndicatorService(redisMock))
+
+	suite.dir = suite.T().TempDir()
+	suite.january = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// a monthly dump with millisecond timestamps and a daily one with a header and microsecond timestamps
+	suite.writeDump("BTCUSDT-1h-2024-01.zip", klineRows(suite.january, 48, false), true)
+	suite.writeDump("BTCUSDT-1h-2025-01-01.zip", "open_time,open,high,low,close,volume,close_time,quote_volume,count,taker_buy_volume,taker_buy_quote_volume,ignore\n"+
+		klineRows(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 24, true), true)
+}
+
+func (suite *ImportTestSuite) TestShouldImportVerifiedDumpsAndGroupThem() {
+	suite.writeDump("ETHUSDT-1h-2024-01.zip", klineRows(suite.january, 24, false), true)
+	suite.writeDump("BTCUSDT-4h-2024-01.zip", klineRows(suite.january, 12, false), true)
+
+	report, err := suite.backfillService.Import("btc", suite.dir)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, report.Files)
+	assert.Equal(suite.T(), 72, report.Records)
+	assert.Zero(suite.T(), report.Overlapping)
+	assert.Empty(suite.T(), report.Skipped)
+	assert.True(suite.T(), report.From.Equal(suite.january))
+	assert.True(suite.T(), report.To.Equal(time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)))
+
+	candles := memory.DB.Candles("btc", "1h")
+	assert.Len(suite.T(), candles, 72)
+	assert.Equal(suite.T(), "BTCUSDT", candles[0].Symbol)
+	assert.True(suite.T(), candles[0].IsComplete)
+	assertDecimal(suite.T(), 100.6, candles[0].Close)
+	assert.Equal(suite.T(), int64(10), candles[0].TradeCount)
+	assert.True(suite.T(), candles[48].Timestamp.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.NotEmpty(suite.T(), memory.DB.Candles("btc", "4h"))
+	assert.NotEmpty(suite.T(), memory.DB.Candles("btc", "1d"))
+	assert.NotEmpty(suite.T(), memory.DB.Indicators("btc", "4h"))
+}
+
+func (suite *ImportTestSuite) TestShouldBeIdempotentAndReportOverlaps() {
+	stored := memoryCandle(suite.january, 100, 150, true)
+	memory.DB.UpsertCandles("btc", "1h", []*dto.DataDto{stored})
+
+	report, err := suite.backfillService.Import("btc", suite.dir)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, report.Overlapping)
+	assert.Equal(suite.T(), 1, report.Conflicting)
+	assertDecimal(suite.T(), 100.6, memory.DB.Candles("btc", "1h")[0].Close)
+
+	report, err = suite.backfillService.Import("btc", suite.dir)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 72, report.Overlapping)
+	assert.Zero(suite.T(), report.Conflicting)
+	assert.Len(suite.T(), memory.DB.Candles("btc", "1h"), 72)
+}
+
+func (suite *ImportTestSuite) TestShouldSkipDumpsWithMissingOrWrongChecksum() {
+	suite.writeDump("BTCUSDT-1h-2024-02.zip", klineRows(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 24, false), false)
+	path := filepath.Join(suite.dir, "BTCUSDT-1h-2024-03.zip")
+	suite.writeDump(filepath.Base(path), klineRows(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), 24, false), true)
+	assert.NoError(suite.T(), os.WriteFile(path+".CHECKSUM", []byte(strings.Repeat("0", 64)+"  BTCUSDT-1h-2024-03.zip\n"), 0o644))
+
+	report, err := suite.backfillService.Import("btc", suite.dir)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, report.Files)
+	assert.Len(suite.T(), report.Skipped, 2)
+	assert.Contains(suite.T(), report.Skipped[0], "checksum file of BTCUSDT-1h-2024-02.zip is missing")
+	assert.Contains(suite.T(), report.Skipped[1], "checksum mismatch for BTCUSDT-1h-2024-03.zip")
+	assert.Len(suite.T(), memory.DB.Candles("btc", "1h"), 72)
+}
+
+// 16400 hours regroup to more 4h records than a single postgres INSERT of grouped records accepts
+func (suite *ImportTestSuite) TestShouldRegroupMultiYearDumps() {
+	suite.dir = suite.T().TempDir()
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.writeDump("BTCUSDT-1h-2021-01.zip", klineRows(start, 16400, false), true)
+
+	report, err := suite.backfillService.Import("btc", suite.dir)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 16400, report.Records)
+	assert.Zero(suite.T(), report.Quarantined)
+	assert.Len(suite.T(), memory.DB.Candles("btc", "1h"), 16400)
+	groups := memory.DB.Candles("btc", "4h")
+	assert.Greater(suite.T(), len(groups), 4095)
+	assert.Len(suite.T(), memory.DB.Indicators("btc", "4h"), len(groups))
+}
+
+// writeDump zips the CSV rows and writes the sha256 CHECKSUM file next to it
+func (suite *ImportTestSuite) writeDump(name string, rows string, withChecksum bool) {
+	path := filepath.Join(suite.dir, name)
+	file, err := os.Create(path)
+	assert.NoError(suite.T(), err)
+	writer := zip.NewWriter(file)
+	entry, err := writer.Create(strings.TrimSuffix(name, ".zip") + ".csv")
+	assert.NoError(suite.T(), err)
+	_, err = entry.Write([]byte(rows))
+	assert.NoError(suite.T(), err)
+	assert.NoError(suite.T(), writer.Close())
+	assert.NoError(suite.T(), file.Close())
+
+	if withChecksum {
+		content, err := os.ReadFile(path)
+		assert.NoError(suite.T(), err)
+		sum := sha256.Sum256(content)
+		assert.NoError(suite.T(), os.WriteFile(path+".CHECKSUM", []byte(hex.EncodeToString(sum[:])+"  "+name+"\n"), 0o644))
+	}
+}
+
+func klineRows(start time.Time, hours int, microseconds bool) string {
+	var rows strings.Builder
+	for i := 0; i < hours; i++ {
+		openTime := start.Add(time.Duration(i) * time.Hour)
+		closeTime := openTime.Add(time.Hour - time.Millisecond)
+		// the prices oscillate so the quality checks see a realistic spread of returns
+		open, close := 100+float64(i%4)*0.5, 100.1+float64((i+1)%4)*0.5
+		if microseconds {
+			fmt.Fprintf(&rows, "%d,", openTime.UnixMicro())
+		} else {
+			fmt.Fprintf(&rows, "%d,", openTime.UnixMilli())
+		}
+		fmt.Fprintf(&rows, "%.2f,%.2f,%.2f,%.2f,2.5,", open, math.Max(open, close)+0.5, math.Min(open, close)-0.5, close)
+		if microseconds {
+			fmt.Fprintf(&rows, "%d,", closeTime.UnixMicro())
+		} else {
+			fmt.Fprintf(&rows, "%d,", closeTime.UnixMilli())
+		}
+		fmt.Fprintf(&rows, "%.2f,10,1.5,%.2f,0\n", close*2.5, close*1.5)
+	}
+	return rows.String()
+}
+
+func TestImport(t *testing.T) {
+	suite.Run(t, new(ImportTestSuite))
+}
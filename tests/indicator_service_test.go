@@ -1,17 +1,17 @@
 package main
 
 import (
-	"database/sql"
-	"fmt"
-	"log"
 	"math"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/chyngyz-sydykov/marketpulse/config"
 	indicator "github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
 	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
-	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -20,7 +20,6 @@ import (
 // Test Suite Struct
 type IndicatorServiceTestSuite struct {
 	suite.Suite
-	db                *sql.DB
 	indicatorService  *indicator.IndicatorService
 	marketDataService *marketdata.MarketDataService
 	redisMock         *MockRedisService
@@ -34,10 +33,7 @@ type oclh struct {
 }
 
 func (suite *IndicatorServiceTestSuite) SetupSuite() {
-	err := database.ConnectDB()
-	if err != nil {
-		log.Fatal("❌ Failed to connect to database:", err)
-	}
+	os.Setenv("STORAGE_BACKEND", "memory")
 
 	mockRedisMarketData := &MockRedisService{}
 	mockRedisMarketData.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
@@ -47,16 +43,17 @@ func (suite *IndicatorServiceTestSuite) SetupSuite() {
 
 	suite.indicatorService = indicator.NewIndicatorService(mockRedis)
 	suite.marketDataService = marketdata.NewMarketDataService(mockRedisMarketData)
-	suite.db = database.DB
 	suite.redisMock = mockRedis
 }
 
-// Clear DB Before Each Test
+func (suite *IndicatorServiceTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+	memory.DB.Reset()
+}
+
+// Clear the store Before Each Test
 func (suite *IndicatorServiceTestSuite) SetupTest() {
-	_, err := suite.db.Exec("DELETE FROM data_btc; DELETE FROM indicator_btc;")
-	if err != nil {
-		log.Fatalf("❌ Failed to clean DB: %v", err)
-	}
+	memory.DB.Reset()
 	suite.redisMock.ExpectedCalls = nil
 	suite.redisMock.Calls = nil
 }
@@ -82,9 +79,7 @@ func (suite *IndicatorServiceTestSuite) TestShouldNotCreateIndicatorIfDataDoesNo
 	err := suite.indicatorService.ComputeAndUpsertBatch("btc", "4h")
 	assert.NoError(suite.T(), err)
 
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM indicator_btc`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := suite.countIndicators()
 	assert.Equal(suite.T(), 0, count)
 
 	suite.redisMock.AssertNotCalled(suite.T(), "PublishEvent", mock.Anything, mock.Anything, "MarketPulse")
@@ -95,12 +90,12 @@ func (suite *IndicatorServiceTestSuite) TestShouldNotCreateIndicatorIfHistoryDat
 		Symbol:     "BTCUSDT",
 		Timeframe:  "4h",
 		Timestamp:  time.Date(2020, 1, 1, 1, 0, 0, 0, time.Now().Location()),
-		Open:       5.0,
-		Close:      8.0,
-		Low:        1,
-		High:       10,
-		Volume:     10,
-		Trend:      calculateTrend(5.0, 8.0),
+		Open:       decimal.NewFromFloat(5.0),
+		Close:      decimal.NewFromFloat(8.0),
+		Low:        decimal.NewFromFloat(1),
+		High:       decimal.NewFromFloat(10),
+		Volume:     decimal.NewFromFloat(10),
+		Trend:      calculateTrend(decimal.NewFromFloat(5.0), decimal.NewFromFloat(8.0)),
 		IsComplete: true,
 	}})
 	assert.NoError(suite.T(), err)
@@ -108,9 +103,7 @@ func (suite *IndicatorServiceTestSuite) TestShouldNotCreateIndicatorIfHistoryDat
 	suite.redisMock.AssertNotCalled(suite.T(), "PublishEvent", mock.Anything, mock.Anything, "MarketPulse")
 	assert.NoError(suite.T(), err)
 
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM indicator_btc`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := suite.countIndicators()
 	assert.Equal(suite.T(), 0, count)
 }
 
@@ -125,11 +118,11 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreSingleIndicat
 			Symbol:     "BTCUSDT",
 			Timeframe:  "1h",
 			Timestamp:  tmpTime,
-			Open:       float64(i),
-			Close:      float64(i),
-			Low:        float64(i - 1),
-			High:       float64(i + 1),
-			Volume:     2,
+			Open:       decimal.NewFromFloat(float64(i)),
+			Close:      decimal.NewFromFloat(float64(i)),
+			Low:        decimal.NewFromFloat(float64(i - 1)),
+			High:       decimal.NewFromFloat(float64(i + 1)),
+			Volume:     decimal.NewFromFloat(2),
 			IsComplete: true,
 		})
 		ohlcList = append(ohlcList, oclh{
@@ -146,9 +139,7 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreSingleIndicat
 	err := suite.indicatorService.ComputeAndUpsertBatch("btc", "4h")
 	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewIndicatorAdded", "MarketPulse")
 	assert.NoError(suite.T(), err)
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM indicator_btc`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := suite.countIndicators()
 	assert.Equal(suite.T(), 1, count)
 
 	inidcatorData := suite.getIndicatorByTimeframeAndTimestamp("4h", time.Date(2020, 1, 1, 4, 0, 0, 0, time.Now().Location()))
@@ -174,11 +165,11 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreMultipleIndic
 			Symbol:     "BTCUSDT",
 			Timeframe:  "1h",
 			Timestamp:  tmpTime,
-			Open:       float64(i),
-			Close:      float64(i),
-			Low:        float64(i - 1),
-			High:       float64(i + 1),
-			Volume:     2,
+			Open:       decimal.NewFromFloat(float64(i)),
+			Close:      decimal.NewFromFloat(float64(i)),
+			Low:        decimal.NewFromFloat(float64(i - 1)),
+			High:       decimal.NewFromFloat(float64(i + 1)),
+			Volume:     decimal.NewFromFloat(2),
 			IsComplete: true,
 		})
 		ohlcList = append(ohlcList, oclh{
@@ -196,9 +187,7 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreMultipleIndic
 	err := suite.indicatorService.ComputeAndUpsertBatch("btc", "4h")
 	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewIndicatorAdded", "MarketPulse")
 	assert.NoError(suite.T(), err)
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM indicator_btc`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := suite.countIndicators()
 	assert.Equal(suite.T(), 2, count)
 
 	indicatorData := suite.getIndicatorByTimeframeAndTimestamp("4h", time.Date(2020, 1, 1, 4, 0, 0, 0, time.Now().Location()))
@@ -211,7 +200,7 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreMultipleIndic
 		LowerBollinger: lowerBollinger,
 		UpperBollinger: upperBollinger,
 		RSI:            50,
-		TR:             TR(firstIndicatorGroup, dto.DataDto{Close: 0}),
+		TR:             TR(firstIndicatorGroup, dto.DataDto{Close: decimal.NewFromFloat(0)}),
 	}, indicatorData)
 
 	secondInidcatorData := suite.getIndicatorByTimeframeAndTimestamp("4h", time.Date(2020, 1, 1, 8, 0, 0, 0, time.Now().Location()))
@@ -224,7 +213,7 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreMultipleIndic
 		LowerBollinger: lowerBollinger,
 		UpperBollinger: upperBollinger,
 		RSI:            50,
-		TR:             TR(secondIndicatorGroup, dto.DataDto{Close: 0}),
+		TR:             TR(secondIndicatorGroup, dto.DataDto{Close: decimal.NewFromFloat(0)}),
 	}, secondInidcatorData)
 }
 
@@ -238,11 +227,11 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreMultipleIndic
 			Symbol:     "BTCUSDT",
 			Timeframe:  "1h",
 			Timestamp:  tmpTime,
-			Open:       float64(i),
-			Close:      float64(i),
-			Low:        float64(i - 1),
-			High:       float64(i + 1),
-			Volume:     2,
+			Open:       decimal.NewFromFloat(float64(i)),
+			Close:      decimal.NewFromFloat(float64(i)),
+			Low:        decimal.NewFromFloat(float64(i - 1)),
+			High:       decimal.NewFromFloat(float64(i + 1)),
+			Volume:     decimal.NewFromFloat(2),
 			IsComplete: true,
 		})
 		ohlcList = append(ohlcList, oclh{
@@ -261,11 +250,11 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreMultipleIndic
 			Symbol:     "BTCUSDT",
 			Timeframe:  "1h",
 			Timestamp:  time.Date(2020, 1, 1, 5, 0, 0, 0, time.Now().Location()),
-			Open:       float64(5),
-			Close:      float64(5),
-			Low:        float64(4),
-			High:       float64(6),
-			Volume:     2,
+			Open:       decimal.NewFromFloat(float64(5)),
+			Close:      decimal.NewFromFloat(float64(5)),
+			Low:        decimal.NewFromFloat(float64(4)),
+			High:       decimal.NewFromFloat(float64(6)),
+			Volume:     decimal.NewFromFloat(2),
 			IsComplete: true,
 		},
 	})
@@ -275,9 +264,7 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreMultipleIndic
 
 	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewIndicatorAdded", "MarketPulse")
 	assert.NoError(suite.T(), err)
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM indicator_btc`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := suite.countIndicators()
 	assert.Equal(suite.T(), 1, count)
 
 	inidcatorData := suite.getIndicatorByTimeframeAndTimestamp("4h", time.Date(2020, 1, 1, 4, 0, 0, 0, time.Now().Location()))
@@ -290,25 +277,27 @@ func (suite *IndicatorServiceTestSuite) TestShouldCalculateAndStoreMultipleIndic
 		LowerBollinger: lowerBollinger,
 		UpperBollinger: upperBollinger,
 		RSI:            50,
-		TR:             TR(ohlcList, dto.DataDto{Close: 0}),
+		TR:             TR(ohlcList, dto.DataDto{Close: decimal.NewFromFloat(0)}),
 	}, inidcatorData)
 }
 
-func (suite *IndicatorServiceTestSuite) getIndicatorByTimeframeAndTimestamp(timeframe string, time time.Time) dto.IndicatorDto {
-	var indicator dto.IndicatorDto
-	query := fmt.Sprintf(`SELECT sma, ema, tr, std_dev, lower_bollinger, upper_bollinger, rsi FROM indicator_btc_%s WHERE timestamp = $1`, timeframe)
-	err := suite.db.QueryRow(query, time).
-		Scan(&indicator.SMA, &indicator.EMA, &indicator.TR, &indicator.StdDev, &indicator.LowerBollinger, &indicator.UpperBollinger, &indicator.RSI)
-	assert.NoError(suite.T(), err)
-	return indicator
+func (suite *IndicatorServiceTestSuite) getIndicatorByTimeframeAndTimestamp(timeframe string, timestamp time.Time) dto.IndicatorDto {
+	for _, indicator := range memory.DB.Indicators("btc", timeframe) {
+		if indicator.Timestamp.Equal(timestamp) {
+			return indicator
+		}
+	}
+	suite.T().Errorf("no %s indicator at %s", timeframe, timestamp)
+	return dto.IndicatorDto{}
 }
 
-func (suite *IndicatorServiceTestSuite) getDataByTimeframeAndTimestamp(timeframe string, time time.Time) dto.DataDto {
-	var data dto.DataDto
-	query := fmt.Sprintf(`SELECT open, close, low, high FROM data_btc_%s WHERE timestamp = $1`, timeframe)
-	err := suite.db.QueryRow(query, time).Scan(&data.Open, &data.Close, &data.Low, &data.High)
-	assert.NoError(suite.T(), err)
-	return data
+// countIndicators counts the stored btc indicators across all timeframes, like indicator_btc did
+func (suite *IndicatorServiceTestSuite) countIndicators() int {
+	count := 0
+	for _, timeframe := range config.DefaultTimeframes {
+		count += len(memory.DB.Indicators("btc", timeframe))
+	}
+	return count
 }
 
 func (suite *IndicatorServiceTestSuite) assertIndicators(expected, actual dto.IndicatorDto) {
@@ -401,7 +390,7 @@ func TR(prices []oclh, previousData dto.DataDto) float64 {
 		high = math.Max(price.High, high)
 		low = math.Min(price.Low, low)
 	}
-	return math.Max((high - low), math.Max(math.Abs(high-previousData.Close), math.Abs(low-previousData.Close)))
+	return math.Max((high - low), math.Max(math.Abs(high-previousData.Close.InexactFloat64()), math.Abs(low-previousData.Close.InexactFloat64())))
 }
 
 func TestIndicatorServiceTestSuite(t *testing.T) {
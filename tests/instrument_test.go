@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type InstrumentTestSuite struct {
+	suite.Suite
+}
+
+func (suite *InstrumentTestSuite) TestShouldParseBareCurrenciesAsUsdtPairs() {
+	parsed, err := instrument.Parse("BTC")
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), instrument.Instrument{Base: "btc", Quote: "usdt"}, parsed)
+	assert.Equal(suite.T(), "btc", parsed.Key())
+	assert.Equal(suite.T(), "BTCUSDT", parsed.Symbol())
+}
+
+func (suite *InstrumentTestSuite) TestShouldParsePairsWithAnySeparator() {
+	for _, value := range []string{"eth/btc", "ETH-BTC", "eth_btc"} {
+		parsed, err := instrument.Parse(value)
+
+		assert.Nil(suite.T(), err, value)
+		assert.Equal(suite.T(), "eth_btc", parsed.Key(), value)
+		assert.Equal(suite.T(), "ETHBTC", parsed.Symbol(), value)
+		assert.Equal(suite.T(), "ETH/BTC", parsed.String(), value)
+	}
+}
+
+func (suite *InstrumentTestSuite) TestShouldKeepUsdtPairKeysBare() {
+	parsed, err := instrument.Parse("sol/usdt")
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "sol", parsed.Key())
+	assert.Equal(suite.T(), instrument.New("sol", ""), instrument.FromKey("sol"))
+	assert.Equal(suite.T(), "SOL-USDC", instrument.FromKey("sol_usdc").SymbolWithSeparator("-"))
+}
+
+func (suite *InstrumentTestSuite) TestShouldRejectInvalidInstruments() {
+	for _, value := range []string{"", "btc;drop", "eth/", "eth/btc/usdt"} {
+		_, err := instrument.Parse(value)
+
+		assert.NotNil(suite.T(), err, value)
+	}
+}
+
+func (suite *InstrumentTestSuite) TestShouldTrackInstrumentsFromConfig() {
+	os.Setenv("INSTRUMENTS", "eth/btc,BTC-EUR,btc,invalid/")
+	defer os.Unsetenv("INSTRUMENTS")
+
+	keys := instrument.TrackedKeys()
+
+	assert.Contains(suite.T(), keys, "btc")
+	assert.Contains(suite.T(), keys, "eth_btc")
+	assert.Contains(suite.T(), keys, "btc_eur")
+	assert.NotContains(suite.T(), keys, "invalid")
+	assert.Nil(suite.T(), validator.NewValidator().ValidateCurrencyAndTimeframe("eth_btc", "1h"))
+	assert.NotNil(suite.T(), validator.NewValidator().ValidateCurrencyAndTimeframe("sol_btc", "1h"))
+}
+
+func TestInstrument(t *testing.T) {
+	suite.Run(t, new(InstrumentTestSuite))
+}
@@ -1,53 +1,48 @@
 package main
 
 import (
-	"database/sql"
-	"fmt"
-	"log"
-	"math"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
 	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
-	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
-// Test Suite Struct
+// Test Suite Struct, the service runs on STORAGE_BACKEND=memory
 type MarketDataServiceTestSuite struct {
 	suite.Suite
-	db        *sql.DB
 	service   *marketdata.MarketDataService
 	redisMock *MockRedisService
 }
 
 func (suite *MarketDataServiceTestSuite) SetupSuite() {
-	err := database.ConnectDB()
-	if err != nil {
-		log.Fatal("❌ Failed to connect to database:", err)
-	}
+	os.Setenv("STORAGE_BACKEND", "memory")
 
 	mockRedis := &MockRedisService{}
 	mockRedis.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
 
 	suite.service = marketdata.NewMarketDataService(mockRedis)
-	suite.db = database.DB
 	suite.redisMock = mockRedis
 }
 
+func (suite *MarketDataServiceTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+	memory.DB.Reset()
+}
+
 // Clear DB Before Each Test
 func (suite *MarketDataServiceTestSuite) SetupTest() {
-	_, err := suite.db.Exec("DELETE FROM data_btc_1h; DELETE FROM data_btc_4h; DELETE FROM data_btc_1d;")
-	if err != nil {
-		log.Fatalf("❌ Failed to clean DB: %v", err)
-	}
+	memory.DB.Reset()
 }
 
-func calculateTrend(open, close float64) float64 {
-	return math.Round((close-open)/open*10000) / 10000
+func calculateTrend(open, close decimal.Decimal) decimal.Decimal {
+	return close.Sub(open).Div(open).Round(4)
 }
 
 // ✅ Test Case 1: Store 1H Record
@@ -56,11 +51,11 @@ func (suite *MarketDataServiceTestSuite) TestShouldStoreSingleRecord() {
 		Symbol:     "BTCUSDT",
 		Timeframe:  "1h",
 		Timestamp:  time.Now().Truncate(time.Hour),
-		Open:       110,
-		High:       120,
-		Low:        100,
-		Close:      105,
-		Volume:     111,
+		Open:       decimal.NewFromFloat(110),
+		High:       decimal.NewFromFloat(120),
+		Low:        decimal.NewFromFloat(100),
+		Close:      decimal.NewFromFloat(105),
+		Volume:     decimal.NewFromFloat(111),
 		IsComplete: true,
 	}
 
@@ -71,14 +66,14 @@ func (suite *MarketDataServiceTestSuite) TestShouldStoreSingleRecord() {
 	// Verify stored record
 	stored := suite.getDataByTimeframeAndTimestamp("1h", record.Timestamp)
 	suite.assertRecordValues(*record, stored)
-	assert.Equal(suite.T(), calculateTrend(record.Open, record.Close), stored.Trend)
+	assertDecimalEqual(suite.T(), calculateTrend(record.Open, record.Close), stored.Trend)
 }
 
 // ✅ Test Case 2: Store Multiple 1H Records
 func (suite *MarketDataServiceTestSuite) TestShouldStoreMultipleRecordsWithBatchUpsert() {
 	records := []*dto.DataDto{
-		{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: time.Now().Add(-time.Hour), Open: 210, High: 220, Low: 200, Close: 205, Volume: 222, IsComplete: true},
-		{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: time.Now(), Open: 211, High: 221, Low: 201, Close: 206, Volume: 223, IsComplete: true},
+		{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: time.Now().Add(-time.Hour), Open: decimal.NewFromFloat(210), High: decimal.NewFromFloat(220), Low: decimal.NewFromFloat(200), Close: decimal.NewFromFloat(205), Volume: decimal.NewFromFloat(222), IsComplete: true},
+		{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: time.Now(), Open: decimal.NewFromFloat(211), High: decimal.NewFromFloat(221), Low: decimal.NewFromFloat(201), Close: decimal.NewFromFloat(206), Volume: decimal.NewFromFloat(223), IsComplete: true},
 	}
 
 	err := suite.service.UpsertBatchData("btc", records)
@@ -89,7 +84,7 @@ func (suite *MarketDataServiceTestSuite) TestShouldStoreMultipleRecordsWithBatch
 	for _, rec := range records {
 		stored := suite.getDataByTimeframeAndTimestamp("1h", rec.Timestamp)
 		suite.assertRecordValues(*rec, stored)
-		assert.Equal(suite.T(), calculateTrend(rec.Open, rec.Close), stored.Trend)
+		assertDecimalEqual(suite.T(), calculateTrend(rec.Open, rec.Close), stored.Trend)
 	}
 }
 
@@ -97,88 +92,77 @@ func (suite *MarketDataServiceTestSuite) TestShouldStoreMultipleRecordsWithBatch
 func (suite *MarketDataServiceTestSuite) TestShoulGroupTwo1HRecordsToNotComplete4HourRecord() {
 	timestamp1 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 1, 0, 0, 0, time.Now().Location())
 	timestamp2 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 2, 0, 0, 0, time.Now().Location())
-	suite.db.Exec(`INSERT INTO data_btc_1h (symbol, timeframe, timestamp, open, high, low, close, volume, is_complete)
-							 VALUES
-							 ('btc', '1h', $1, 1, 10, 1, 5, 1, true),
-							 ('btc', '1h', $2, 2, 11, 0, 6, 1, true)`, timestamp1, timestamp2)
+	memory.DB.UpsertCandles("btc", "1h", []*dto.DataDto{
+		oneHourCandle(timestamp1, 1, 10, 1, 5, 1),
+		oneHourCandle(timestamp2, 2, 11, 0, 6, 1),
+	})
 
 	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewGroupDataAdded", "MarketPulse")
 	err := suite.service.StoreGroupedRecords("btc", "4h")
 	assert.NoError(suite.T(), err)
 
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_4h`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := len(memory.DB.Candles("btc", "4h"))
 	assert.Equal(suite.T(), 1, count)
 
-	var stored dto.DataDto
-	err = suite.db.QueryRow(`SELECT open, high, low, close, volume, is_complete, trend FROM data_btc_4h ORDER BY timestamp asc LIMIT 1`).
-		Scan(&stored.Open, &stored.High, &stored.Low, &stored.Close, &stored.Volume, &stored.IsComplete, &stored.Trend)
-	assert.NoError(suite.T(), err)
+	stored := memory.DB.Candles("btc", "4h")[0]
 	suite.assertRecordValues(dto.DataDto{
-		Open:       1,
-		High:       11,
-		Low:        0,
-		Close:      6,
-		Volume:     2,
+		Open:       decimal.NewFromFloat(1),
+		High:       decimal.NewFromFloat(11),
+		Low:        decimal.NewFromFloat(0),
+		Close:      decimal.NewFromFloat(6),
+		Volume:     decimal.NewFromFloat(2),
 		IsComplete: false,
 	}, stored)
-	assert.Equal(suite.T(), calculateTrend(stored.Open, stored.Close), stored.Trend)
+	assertDecimalEqual(suite.T(), calculateTrend(stored.Open, stored.Close), stored.Trend)
 }
 
 func (suite *MarketDataServiceTestSuite) TestShouldGroupUpdateExistingInCompleteGroupRecordWhenNew1HRecordIsAdded() {
 	timestamp1 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 1, 0, 0, 0, time.Now().Location())
 	timestamp2 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 2, 0, 0, 0, time.Now().Location())
-	suite.db.Exec(`INSERT INTO data_btc_1h (symbol, timeframe, timestamp, open, high, low, close, volume, is_complete)
-							 VALUES
-							 ('btc', '1h', $1, 1, 10, 1, 5, 1, true),
-							 ('btc', '1h', $2, 2, 11, 0, 6, 1, true)`, timestamp1, timestamp2)
+	memory.DB.UpsertCandles("btc", "1h", []*dto.DataDto{
+		oneHourCandle(timestamp1, 1, 10, 1, 5, 1),
+		oneHourCandle(timestamp2, 2, 11, 0, 6, 1),
+	})
 
 	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewGroupDataAdded", "MarketPulse")
 	err := suite.service.StoreGroupedRecords("btc", "4h")
 	assert.NoError(suite.T(), err)
 
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_4h`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := len(memory.DB.Candles("btc", "4h"))
 	assert.Equal(suite.T(), 1, count)
 
-	var stored dto.DataDto
-	err = suite.db.QueryRow(`SELECT open, high, low, close, volume, is_complete, trend FROM data_btc_4h ORDER BY timestamp asc LIMIT 1`).
-		Scan(&stored.Open, &stored.High, &stored.Low, &stored.Close, &stored.Volume, &stored.IsComplete, &stored.Trend)
-	assert.NoError(suite.T(), err)
+	stored := memory.DB.Candles("btc", "4h")[0]
 	suite.assertRecordValues(dto.DataDto{
-		Open:       1,
-		High:       11,
-		Low:        0,
-		Close:      6,
-		Volume:     2,
+		Open:       decimal.NewFromFloat(1),
+		High:       decimal.NewFromFloat(11),
+		Low:        decimal.NewFromFloat(0),
+		Close:      decimal.NewFromFloat(6),
+		Volume:     decimal.NewFromFloat(2),
 		IsComplete: false,
 	}, stored)
-	assert.Equal(suite.T(), calculateTrend(stored.Open, stored.Close), stored.Trend)
+	assertDecimalEqual(suite.T(), calculateTrend(stored.Open, stored.Close), stored.Trend)
 
 	timestamp3 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 3, 0, 0, 0, time.Now().Location())
-	suite.db.Exec(`INSERT INTO data_btc_1h (symbol, timeframe, timestamp, open,close, low, high, volume, is_complete)
-							 VALUES ('btc', '1h', $1, 2, 9, 0, 12, 2, true)`, timestamp3)
+	memory.DB.UpsertCandles("btc", "1h", []*dto.DataDto{
+		oneHourCandle(timestamp3, 2, 12, 0, 9, 2),
+	})
 	// update existing 4h record
 	err = suite.service.StoreGroupedRecords("btc", "4h")
 	assert.NoError(suite.T(), err)
 
-	suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_4h`).Scan(&count)
+	count = len(memory.DB.Candles("btc", "4h"))
 	assert.Equal(suite.T(), 1, count)
 
-	err = suite.db.QueryRow(`SELECT open, high, low, close, volume, is_complete, trend FROM data_btc_4h ORDER BY timestamp asc LIMIT 1`).
-		Scan(&stored.Open, &stored.High, &stored.Low, &stored.Close, &stored.Volume, &stored.IsComplete, &stored.Trend)
-	assert.NoError(suite.T(), err)
+	stored = memory.DB.Candles("btc", "4h")[0]
 	suite.assertRecordValues(dto.DataDto{
-		Open:       1,
-		Close:      9,
-		Low:        0,
-		High:       12,
-		Volume:     4,
+		Open:       decimal.NewFromFloat(1),
+		Close:      decimal.NewFromFloat(9),
+		Low:        decimal.NewFromFloat(0),
+		High:       decimal.NewFromFloat(12),
+		Volume:     decimal.NewFromFloat(4),
 		IsComplete: false,
 	}, stored)
-	assert.Equal(suite.T(), calculateTrend(stored.Open, stored.Close), stored.Trend)
+	assertDecimalEqual(suite.T(), calculateTrend(stored.Open, stored.Close), stored.Trend)
 }
 
 func (suite *MarketDataServiceTestSuite) TestShoulGroup1HRecordsWithinTimeTangeToComplete4HourRecord() {
@@ -186,35 +170,52 @@ func (suite *MarketDataServiceTestSuite) TestShoulGroup1HRecordsWithinTimeTangeT
 	timestamp2 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 2, 0, 0, 0, time.Now().Location())
 	timestamp3 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 3, 0, 0, 0, time.Now().Location())
 	timestamp4 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 4, 0, 0, 0, time.Now().Location())
-	suite.db.Exec(`INSERT INTO data_btc_1h (symbol, timeframe, timestamp, open, close, high, low, volume, is_complete)
-							 VALUES
-							 ('btc', '1h', $1, 1, 5, 10, 3, 1, true),
-							 ('btc', '1h', $2, 5, 6, 11, 1, 1, true),
-							 ('btc', '1h', $3, 6, 7, 9, 0, 1, true),
-							 ('btc', '1h', $4, 7, 8, 8, 3, 1, true)`, timestamp1, timestamp2, timestamp3, timestamp4)
+	memory.DB.UpsertCandles("btc", "1h", []*dto.DataDto{
+		oneHourCandle(timestamp1, 1, 10, 3, 5, 1),
+		oneHourCandle(timestamp2, 5, 11, 1, 6, 1),
+		oneHourCandle(timestamp3, 6, 9, 0, 7, 1),
+		oneHourCandle(timestamp4, 7, 8, 3, 8, 1),
+	})
 
 	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewGroupDataAdded", "MarketPulse")
 	err := suite.service.StoreGroupedRecords("btc", "4h")
 	assert.NoError(suite.T(), err)
 
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_4h`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := len(memory.DB.Candles("btc", "4h"))
 	assert.Equal(suite.T(), 1, count)
 
-	var complete4hData dto.DataDto
-	err = suite.db.QueryRow(`SELECT open, high, low, close, volume, is_complete, trend FROM data_btc_4h ORDER BY timestamp asc LIMIT 1`).
-		Scan(&complete4hData.Open, &complete4hData.High, &complete4hData.Low, &complete4hData.Close, &complete4hData.Volume, &complete4hData.IsComplete, &complete4hData.Trend)
-	assert.NoError(suite.T(), err)
+	complete4hData := memory.DB.Candles("btc", "4h")[0]
 	suite.assertRecordValues(dto.DataDto{
-		Open:       1,
-		Close:      8,
-		Low:        0,
-		High:       11,
-		Volume:     4,
+		Open:       decimal.NewFromFloat(1),
+		Close:      decimal.NewFromFloat(8),
+		Low:        decimal.NewFromFloat(0),
+		High:       decimal.NewFromFloat(11),
+		Volume:     decimal.NewFromFloat(4),
 		IsComplete: true,
 	}, complete4hData)
-	assert.Equal(suite.T(), calculateTrend(complete4hData.Open, complete4hData.Close), complete4hData.Trend)
+	assertDecimalEqual(suite.T(), calculateTrend(complete4hData.Open, complete4hData.Close), complete4hData.Trend)
+}
+
+func (suite *MarketDataServiceTestSuite) TestShouldSumOrderFlowFieldsWhenGrouping1HRecordsTo4HourRecord() {
+	timestamp1 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 1, 0, 0, 0, time.Now().Location())
+	timestamp2 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 2, 0, 0, 0, time.Now().Location())
+	timestamp3 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 3, 0, 0, 0, time.Now().Location())
+	timestamp4 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 4, 0, 0, 0, time.Now().Location())
+	memory.DB.UpsertCandles("btc", "1h", []*dto.DataDto{
+		withOrderFlow(oneHourCandle(timestamp1, 1, 10, 3, 5, 1), 100, 10, 0.5, 50),
+		withOrderFlow(oneHourCandle(timestamp2, 5, 11, 1, 6, 1), 200, 20, 0.25, 25),
+		withOrderFlow(oneHourCandle(timestamp3, 6, 9, 0, 7, 1), 300, 30, 0.5, 50),
+		withOrderFlow(oneHourCandle(timestamp4, 7, 8, 3, 8, 1), 400, 40, 0.25, 25),
+	})
+
+	err := suite.service.StoreGroupedRecords("btc", "4h")
+	assert.NoError(suite.T(), err)
+
+	stored := suite.getDataByTimeframeAndTimestamp("4h", timestamp4)
+	assertDecimal(suite.T(), 1000.0, stored.QuoteVolume)
+	assert.Equal(suite.T(), int64(100), stored.TradeCount)
+	assertDecimal(suite.T(), 1.5, stored.TakerBuyBaseVolume)
+	assertDecimal(suite.T(), 150.0, stored.TakerBuyQuoteVolume)
 }
 
 func (suite *MarketDataServiceTestSuite) TestShoulGroup1HRecordsExceedingTimeRangeToCompleteAndInComplete4HourRecords() {
@@ -225,46 +226,43 @@ func (suite *MarketDataServiceTestSuite) TestShoulGroup1HRecordsExceedingTimeRan
 	timestamp4 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 4, 0, 0, 0, time.Now().Location())
 	timestamp5 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 5, 0, 0, 0, time.Now().Location())
 	timestamp6 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 6, 0, 0, 0, time.Now().Location())
-	suite.db.Exec(`INSERT INTO data_btc_1h (symbol, timeframe, timestamp, open, close, high, low, volume, is_complete)
-							 VALUES
-							 ('btc', '1h', $1, 1, 5, 10, 3, 1, true),
-							 ('btc', '1h', $2, 5, 6, 11, 1, 1, true),
-							 ('btc', '1h', $3, 6, 7, 9, 0, 1, true),
-							 ('btc', '1h', $4, 7, 8, 8, 3, 1, true),
-							 ('btc', '1h', $5, 1, 6, 9, 2, 1, true),
-							 ('btc', '1h', $6, 6, 1, 11, 3, 1, true)`,
-		timestamp1, timestamp2, timestamp3, timestamp4, timestamp5, timestamp6)
+	memory.DB.UpsertCandles("btc", "1h", []*dto.DataDto{
+		oneHourCandle(timestamp1, 1, 10, 3, 5, 1),
+		oneHourCandle(timestamp2, 5, 11, 1, 6, 1),
+		oneHourCandle(timestamp3, 6, 9, 0, 7, 1),
+		oneHourCandle(timestamp4, 7, 8, 3, 8, 1),
+		oneHourCandle(timestamp5, 1, 9, 2, 6, 1),
+		oneHourCandle(timestamp6, 6, 11, 3, 1, 1),
+	})
 	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewGroupDataAdded", "MarketPulse")
 	err := suite.service.StoreGroupedRecords("btc", "4h")
 	assert.NoError(suite.T(), err)
 
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_4h`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := len(memory.DB.Candles("btc", "4h"))
 	assert.Equal(suite.T(), 2, count)
 
 	complete4hData := suite.getDataByTimeframeAndTimestamp("4h", timestamp4)
 	suite.assertRecordValues(dto.DataDto{
-		Open:       1,
-		Close:      8,
-		Low:        0,
-		High:       11,
-		Volume:     4,
+		Open:       decimal.NewFromFloat(1),
+		Close:      decimal.NewFromFloat(8),
+		Low:        decimal.NewFromFloat(0),
+		High:       decimal.NewFromFloat(11),
+		Volume:     decimal.NewFromFloat(4),
 		IsComplete: true,
 	}, complete4hData)
-	assert.Equal(suite.T(), calculateTrend(complete4hData.Open, complete4hData.Close), complete4hData.Trend)
+	assertDecimalEqual(suite.T(), calculateTrend(complete4hData.Open, complete4hData.Close), complete4hData.Trend)
 
 	next4HGroupTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 8, 0, 0, 0, time.Now().Location())
 	incomplete4hData := suite.getDataByTimeframeAndTimestamp("4h", next4HGroupTime)
 	suite.assertRecordValues(dto.DataDto{
-		Open:       1,
-		Close:      1,
-		Low:        2,
-		High:       11,
-		Volume:     2,
+		Open:       decimal.NewFromFloat(1),
+		Close:      decimal.NewFromFloat(1),
+		Low:        decimal.NewFromFloat(2),
+		High:       decimal.NewFromFloat(11),
+		Volume:     decimal.NewFromFloat(2),
 		IsComplete: false,
 	}, incomplete4hData)
-	assert.Equal(suite.T(), calculateTrend(incomplete4hData.Open, incomplete4hData.Close), incomplete4hData.Trend)
+	assertDecimalEqual(suite.T(), calculateTrend(incomplete4hData.Open, incomplete4hData.Close), incomplete4hData.Trend)
 }
 
 func (suite *MarketDataServiceTestSuite) TestShoulGroup1HRecordsBetweenDaysTo4HourRecords() {
@@ -274,46 +272,43 @@ func (suite *MarketDataServiceTestSuite) TestShoulGroup1HRecordsBetweenDaysTo4Ho
 	timestamp4 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 1, 0, 0, 0, time.Now().Location())
 	timestamp5 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 2, 0, 0, 0, time.Now().Location())
 	timestamp6 := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 3, 0, 0, 0, time.Now().Location())
-	suite.db.Exec(`INSERT INTO data_btc_1h (symbol, timeframe, timestamp, open, close, high, low, volume, is_complete)
-							 VALUES
-							 ('btc', '1h', $1, 100, 105, 110, 80, 10, true),
-							 ('btc', '1h', $2, 105, 110, 160, 100, 15, true),
-							 ('btc', '1h', $3, 110, 115, 120, 105, 20, true),
-							 ('btc', '1h', $4, 115, 120, 125, 110, 25, true),
-							 ('btc', '1h', $5, 120, 125, 150, 70, 30, true),
-							 ('btc', '1h', $6, 125, 130, 135, 120, 35, true)`,
-		timestamp1, timestamp2, midnight, timestamp4, timestamp5, timestamp6)
+	memory.DB.UpsertCandles("btc", "1h", []*dto.DataDto{
+		oneHourCandle(timestamp1, 100, 110, 80, 105, 10),
+		oneHourCandle(timestamp2, 105, 160, 100, 110, 15),
+		oneHourCandle(midnight, 110, 120, 105, 115, 20),
+		oneHourCandle(timestamp4, 115, 125, 110, 120, 25),
+		oneHourCandle(timestamp5, 120, 150, 70, 125, 30),
+		oneHourCandle(timestamp6, 125, 135, 120, 130, 35),
+	})
 	err := suite.service.StoreGroupedRecords("btc", "4h")
 	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewGroupDataAdded", "MarketPulse")
 	assert.NoError(suite.T(), err)
 
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_4h`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := len(memory.DB.Candles("btc", "4h"))
 	assert.Equal(suite.T(), 2, count)
 
 	complete4hData := suite.getDataByTimeframeAndTimestamp("4h", midnight)
 
 	suite.assertRecordValues(dto.DataDto{
-		Open:       100,
-		Close:      115,
-		Low:        80,
-		High:       160,
-		Volume:     45,
+		Open:       decimal.NewFromFloat(100),
+		Close:      decimal.NewFromFloat(115),
+		Low:        decimal.NewFromFloat(80),
+		High:       decimal.NewFromFloat(160),
+		Volume:     decimal.NewFromFloat(45),
 		IsComplete: true,
 	}, complete4hData)
 
 	next4HGroupTime := time.Date(time.Now().Year(), time.Now().Month(), time.Now().Day(), 4, 0, 0, 0, time.Now().Location())
 	incomplete4hData := suite.getDataByTimeframeAndTimestamp("4h", next4HGroupTime)
 	suite.assertRecordValues(dto.DataDto{
-		Open:       115,
-		Close:      130,
-		Low:        70,
-		High:       150,
-		Volume:     90,
+		Open:       decimal.NewFromFloat(115),
+		Close:      decimal.NewFromFloat(130),
+		Low:        decimal.NewFromFloat(70),
+		High:       decimal.NewFromFloat(150),
+		Volume:     decimal.NewFromFloat(90),
 		IsComplete: false,
 	}, incomplete4hData)
-	assert.Equal(suite.T(), calculateTrend(incomplete4hData.Open, incomplete4hData.Close), incomplete4hData.Trend)
+	assertDecimalEqual(suite.T(), calculateTrend(incomplete4hData.Open, incomplete4hData.Close), incomplete4hData.Trend)
 }
 
 func (suite *MarketDataServiceTestSuite) TestShouldGroup1HRecordsTo1DRecords() {
@@ -325,11 +320,11 @@ func (suite *MarketDataServiceTestSuite) TestShouldGroup1HRecordsTo1DRecords() {
 			Symbol:     "BTCUSDT",
 			Timeframe:  "1h",
 			Timestamp:  tmpTime,
-			Open:       float64(i),
-			Close:      float64(i),
-			Low:        float64(i - 1),
-			High:       float64(i + 1),
-			Volume:     2,
+			Open:       decimal.NewFromFloat(float64(i)),
+			Close:      decimal.NewFromFloat(float64(i)),
+			Low:        decimal.NewFromFloat(float64(i - 1)),
+			High:       decimal.NewFromFloat(float64(i + 1)),
+			Volume:     decimal.NewFromFloat(2),
 			IsComplete: true,
 		})
 	}
@@ -339,64 +334,95 @@ func (suite *MarketDataServiceTestSuite) TestShouldGroup1HRecordsTo1DRecords() {
 	err = suite.service.StoreGroupedRecords("btc", "1d")
 	assert.NoError(suite.T(), err)
 
-	var count int
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_1d where is_complete = true`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count := suite.countCandles("1d", true)
 	assert.Equal(suite.T(), 2, count)
-	err = suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_1d where is_complete = false`).Scan(&count)
-	assert.NoError(suite.T(), err)
+	count = suite.countCandles("1d", false)
 	assert.Equal(suite.T(), 1, count)
 
 	jan2Time := time.Date(2020, 1, 1, 24, 0, 0, 0, time.Now().Location())
 	jan2Data := suite.getDataByTimeframeAndTimestamp("1d", jan2Time)
 	suite.assertRecordValues(dto.DataDto{
-		Open:       1,
-		Close:      23,
-		Low:        0,
-		High:       24,
-		Volume:     46,
+		Open:       decimal.NewFromFloat(1),
+		Close:      decimal.NewFromFloat(23),
+		Low:        decimal.NewFromFloat(0),
+		High:       decimal.NewFromFloat(24),
+		Volume:     decimal.NewFromFloat(46),
 		IsComplete: true,
 	}, jan2Data)
 
 	jan3Time := time.Date(2020, 1, 2, 24, 0, 0, 0, time.Now().Location())
 	jan3Data := suite.getDataByTimeframeAndTimestamp("1d", jan3Time)
 	suite.assertRecordValues(dto.DataDto{
-		Open:       24,
-		Close:      47,
-		Low:        23,
-		High:       48,
-		Volume:     48,
+		Open:       decimal.NewFromFloat(24),
+		Close:      decimal.NewFromFloat(47),
+		Low:        decimal.NewFromFloat(23),
+		High:       decimal.NewFromFloat(48),
+		Volume:     decimal.NewFromFloat(48),
 		IsComplete: true,
 	}, jan3Data)
 
 	jan4Time := time.Date(2020, 1, 3, 24, 0, 0, 0, time.Now().Location())
 	jan4Data := suite.getDataByTimeframeAndTimestamp("1d", jan4Time)
 	suite.assertRecordValues(dto.DataDto{
-		Open:       48,
-		Close:      49,
-		Low:        47,
-		High:       50,
-		Volume:     4,
+		Open:       decimal.NewFromFloat(48),
+		Close:      decimal.NewFromFloat(49),
+		Low:        decimal.NewFromFloat(47),
+		High:       decimal.NewFromFloat(50),
+		Volume:     decimal.NewFromFloat(4),
 		IsComplete: false,
 	}, jan4Data)
 }
 
 func (suite *MarketDataServiceTestSuite) assertRecordValues(expected, actual dto.DataDto) {
-	assert.Equal(suite.T(), expected.Open, actual.Open)
-	assert.Equal(suite.T(), expected.Close, actual.Close)
-	assert.Equal(suite.T(), expected.Low, actual.Low)
-	assert.Equal(suite.T(), expected.High, actual.High)
-	assert.Equal(suite.T(), expected.Volume, actual.Volume)
+	assertDecimalEqual(suite.T(), expected.Open, actual.Open)
+	assertDecimalEqual(suite.T(), expected.Close, actual.Close)
+	assertDecimalEqual(suite.T(), expected.Low, actual.Low)
+	assertDecimalEqual(suite.T(), expected.High, actual.High)
+	assertDecimalEqual(suite.T(), expected.Volume, actual.Volume)
 	assert.Equal(suite.T(), expected.IsComplete, actual.IsComplete)
 }
 
-func (suite *MarketDataServiceTestSuite) getDataByTimeframeAndTimestamp(timeframe string, time time.Time) dto.DataDto {
-	var data dto.DataDto
-	query := fmt.Sprintf(`SELECT open, high, low, close, volume, is_complete, trend FROM data_btc_%s WHERE timestamp = $1`, timeframe)
-	err := suite.db.QueryRow(query, time).
-		Scan(&data.Open, &data.High, &data.Low, &data.Close, &data.Volume, &data.IsComplete, &data.Trend)
-	assert.NoError(suite.T(), err)
-	return data
+func (suite *MarketDataServiceTestSuite) getDataByTimeframeAndTimestamp(timeframe string, timestamp time.Time) dto.DataDto {
+	for _, record := range memory.DB.Candles("btc", timeframe) {
+		if record.Timestamp.Equal(timestamp) {
+			return record
+		}
+	}
+	suite.T().Errorf("no %s record at %s", timeframe, timestamp)
+	return dto.DataDto{}
+}
+
+func (suite *MarketDataServiceTestSuite) countCandles(timeframe string, isComplete bool) int {
+	count := 0
+	for _, record := range memory.DB.Candles("btc", timeframe) {
+		if record.IsComplete == isComplete {
+			count++
+		}
+	}
+	return count
+}
+
+// oneHourCandle returns a complete 1h candle like the rows inserted by the aggregator tests
+func oneHourCandle(timestamp time.Time, open, high, low, close, volume float64) *dto.DataDto {
+	return &dto.DataDto{
+		Symbol:     "btc",
+		Timeframe:  "1h",
+		Timestamp:  timestamp,
+		Open:       decimal.NewFromFloat(open),
+		High:       decimal.NewFromFloat(high),
+		Low:        decimal.NewFromFloat(low),
+		Close:      decimal.NewFromFloat(close),
+		Volume:     decimal.NewFromFloat(volume),
+		IsComplete: true,
+	}
+}
+
+func withOrderFlow(record *dto.DataDto, quoteVolume float64, tradeCount int64, takerBuyBaseVolume, takerBuyQuoteVolume float64) *dto.DataDto {
+	record.QuoteVolume = decimal.NewFromFloat(quoteVolume)
+	record.TradeCount = tradeCount
+	record.TakerBuyBaseVolume = decimal.NewFromFloat(takerBuyBaseVolume)
+	record.TakerBuyQuoteVolume = decimal.NewFromFloat(takerBuyQuoteVolume)
+	return record
 }
 
 // ✅ Run the Test Suite
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/exchange"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// Test Suite Struct
+type MarketDataSourceTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	fixtures map[string]string // request path => fixture file
+	requests []*http.Request
+}
+
+func (suite *MarketDataSourceTestSuite) SetupSuite() {
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.requests = append(suite.requests, r)
+		fixture, ok := suite.fixtures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, err := os.ReadFile(fixture)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+
+	os.Setenv("COINBASE_BASE_API_URL", suite.server.URL+"/coinbase/")
+	os.Setenv("KRAKEN_BASE_API_URL", suite.server.URL+"/kraken/")
+	os.Setenv("OKX_BASE_API_URL", suite.server.URL+"/okx/")
+}
+
+func (suite *MarketDataSourceTestSuite) TearDownSuite() {
+	suite.server.Close()
+	os.Unsetenv("COINBASE_BASE_API_URL")
+	os.Unsetenv("KRAKEN_BASE_API_URL")
+	os.Unsetenv("OKX_BASE_API_URL")
+}
+
+func (suite *MarketDataSourceTestSuite) SetupTest() {
+	suite.requests = nil
+	suite.fixtures = map[string]string{
+		"/coinbase/products/BTC-USDT/candles": "fixtures/coinbase/candles.json",
+		"/coinbase/products/BTC-USDT/stats":   "fixtures/coinbase/stats.json",
+		"/kraken/OHLC":                        "fixtures/kraken/ohlc.json",
+		"/kraken/Ticker":                      "fixtures/kraken/ticker.json",
+		"/okx/market/candles":                 "fixtures/okx/candles.json",
+		"/okx/market/history-candles":         "fixtures/okx/candles.json",
+		"/okx/market/ticker":                  "fixtures/okx/ticker.json",
+	}
+}
+
+var firstCandleTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (suite *MarketDataSourceTestSuite) TestShouldMapSymbolsPerExchange() {
+	testCases := []struct {
+		source   string
+		currency string
+		symbol   string
+	}{
+		{source: "binance", currency: "btc", symbol: "BTCUSDT"},
+		{source: "coinbase", currency: "eth", symbol: "ETH-USDT"},
+		{source: "kraken", currency: "btc", symbol: "XBTUSDT"},
+		{source: "kraken", currency: "sol", symbol: "SOLUSDT"},
+		{source: "okx", currency: "bnb", symbol: "BNB-USDT"},
+		{source: "binance", currency: "eth_btc", symbol: "ETHBTC"},
+		{source: "coinbase", currency: "btc_eur", symbol: "BTC-EUR"},
+		{source: "kraken", currency: "eth_btc", symbol: "ETHXBT"},
+		{source: "okx", currency: "sol_usdc", symbol: "SOL-USDC"},
+	}
+
+	for _, tc := range testCases {
+		source, err := exchange.NewMarketDataSource(tc.source)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), tc.source, source.Name())
+		assert.Equal(suite.T(), tc.symbol, source.Symbol(tc.currency))
+	}
+
+	_, err := exchange.NewMarketDataSource("unknown")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *MarketDataSourceTestSuite) TestShouldSelectSourcePerCurrencyFromConfiguration() {
+	os.Setenv("MARKET_DATA_SOURCES", "btc:kraken, eth:okx")
+	defer os.Unsetenv("MARKET_DATA_SOURCES")
+
+	testCases := map[string]string{"btc": "kraken", "eth": "okx", "sol": "binance"}
+	for currency, expected := range testCases {
+		source, err := exchange.GetSourceForCurrency(currency)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), expected, source.Name())
+	}
+}
+
+func (suite *MarketDataSourceTestSuite) TestCoinbaseShouldFetchKlineRangeInAscendingOrder() {
+	source, _ := exchange.NewMarketDataSource("coinbase")
+
+	records, err := source.FetchKlineRange("btc", "1h", firstCandleTime, firstCandleTime.Add(2*time.Hour), 300)
+
+	assert.NoError(suite.T(), err)
+	suite.assertCandles(records, "BTC-USDT", []float64{93576.0, 93420.55, 93501.01}, []float64{93420.55, 93501.01, 93650.44})
+	assertDecimal(suite.T(), 140.52811961, records[0].Volume)
+	assert.Equal(suite.T(), "3600", suite.requests[0].URL.Query().Get("granularity"))
+	assert.Equal(suite.T(), "2025-01-01T00:00:00Z", suite.requests[0].URL.Query().Get("start"))
+}
+
+func (suite *MarketDataSourceTestSuite) TestCoinbaseShouldRejectUnsupportedInterval() {
+	source, _ := exchange.NewMarketDataSource("coinbase")
+
+	_, err := source.FetchKline("btc", "4h", 10)
+
+	assert.ErrorContains(suite.T(), err, "does not support interval")
+	assert.Empty(suite.T(), suite.requests)
+}
+
+func (suite *MarketDataSourceTestSuite) TestCoinbaseShouldFetchTicker() {
+	source, _ := exchange.NewMarketDataSource("coinbase")
+
+	ticker, err := source.FetchTicker("btc")
+
+	assert.NoError(suite.T(), err)
+	assertDecimal(suite.T(), 93576.0, ticker.Open)
+	assertDecimal(suite.T(), 95120.99, ticker.High)
+	assertDecimal(suite.T(), 92880.01, ticker.Low)
+	assertDecimal(suite.T(), 94871.15, ticker.Close)
+	assertDecimal(suite.T(), 5120.38716344, ticker.Volume)
+}
+
+func (suite *MarketDataSourceTestSuite) TestKrakenShouldFetchLatestKlines() {
+	source, _ := exchange.NewMarketDataSource("kraken")
+
+	records, err := source.FetchKline("btc", "1h", 2)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 2)
+	assertDecimal(suite.T(), 93499.9, records[1].Open)
+	assertDecimal(suite.T(), 93648.0, records[1].Close)
+	assertDecimal(suite.T(), 11.200183, records[1].Volume)
+	assert.Equal(suite.T(), "XBTUSDT", suite.requests[0].URL.Query().Get("pair"))
+	assert.Equal(suite.T(), "60", suite.requests[0].URL.Query().Get("interval"))
+}
+
+func (suite *MarketDataSourceTestSuite) TestKrakenShouldFetchKlineRange() {
+	source, _ := exchange.NewMarketDataSource("kraken")
+
+	records, err := source.FetchKlineRange("btc", "1h", firstCandleTime, firstCandleTime.Add(2*time.Hour), 720)
+
+	assert.NoError(suite.T(), err)
+	suite.assertCandles(records, "XBTUSDT", []float64{93580.0, 93425.3, 93499.9}, []float64{93425.3, 93499.9, 93648.0})
+	assert.Equal(suite.T(), "1735689599", suite.requests[0].URL.Query().Get("since"))
+}
+
+func (suite *MarketDataSourceTestSuite) TestKrakenShouldRejectMalformedCandle() {
+	fixture := filepath.Join(suite.T().TempDir(), "ohlc.json")
+	os.WriteFile(fixture, []byte(`{"error":[],"result":{"XBTUSDT":[[1735689600,93580.0,"93820.1","93315.2","93425.3","93561.7","12.4",1830]],"last":1735689600}}`), 0o644)
+	suite.fixtures["/kraken/OHLC"] = fixture
+	source, _ := exchange.NewMarketDataSource("kraken")
+
+	_, err := source.FetchKline("btc", "1h", 1)
+
+	assert.ErrorContains(suite.T(), err, "unexpected response format: field 1 is float64")
+}
+
+func (suite *MarketDataSourceTestSuite) TestBinanceShouldRejectMalformedKline() {
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.URL+"/binance/")
+	defer os.Unsetenv("BINANCE_BASE_API_URL")
+	testCases := []struct {
+		kline string
+		err   string
+	}{
+		{kline: `[1735689600000,"93429.12","93600.00","93300.10","93550.40","12.5",1735693199999]`, err: "unexpected response format: kline has 7 fields"},
+		{kline: `[1735689600000,93429.12,"93600.00","93300.10","93550.40","12.5",1735693199999,"1169000.1",60,"6.1","570000.2","0"]`, err: "unexpected response format: field 1 is float64"},
+		{kline: `[1735689600000,"93429.12","93600.00","93300.10","93550.40","12.5",1735693199999,"1169000.1","60","6.1","570000.2","0"]`, err: "trade count is string"},
+	}
+	source, _ := exchange.NewMarketDataSource("binance")
+
+	for _, tc := range testCases {
+		fixture := filepath.Join(suite.T().TempDir(), "klines.json")
+		os.WriteFile(fixture, []byte("["+tc.kline+"]"), 0o644)
+		suite.fixtures["/binance/klines"] = fixture
+
+		_, err := source.FetchKline("btc", "1h", 1)
+		assert.ErrorContains(suite.T(), err, tc.err)
+		_, err = source.FetchKlineRange("btc", "1h", firstCandleTime, firstCandleTime, 1)
+		assert.ErrorContains(suite.T(), err, tc.err)
+	}
+}
+
+func (suite *MarketDataSourceTestSuite) TestKrakenShouldFetchTicker() {
+	source, _ := exchange.NewMarketDataSource("kraken")
+
+	ticker, err := source.FetchTicker("btc")
+
+	assert.NoError(suite.T(), err)
+	assertDecimal(suite.T(), 93580.0, ticker.Open)
+	assertDecimal(suite.T(), 95119.4, ticker.High)
+	assertDecimal(suite.T(), 92877.8, ticker.Low)
+	assertDecimal(suite.T(), 94871.5, ticker.Close)
+	assertDecimal(suite.T(), 640.12838401, ticker.Volume)
+}
+
+func (suite *MarketDataSourceTestSuite) TestOkxShouldFetchLatestKlinesInAscendingOrder() {
+	source, _ := exchange.NewMarketDataSource("okx")
+
+	records, err := source.FetchKline("btc", "4h", 3)
+
+	assert.NoError(suite.T(), err)
+	suite.assertCandles(records, "BTC-USDT", []float64{93577.3, 93421.7, 93502.1}, []float64{93421.7, 93502.1, 93650.0})
+	assert.Equal(suite.T(), "4H", suite.requests[0].URL.Query().Get("bar"))
+}
+
+func (suite *MarketDataSourceTestSuite) TestOkxShouldFetchKlineRangeFromHistory() {
+	source, _ := exchange.NewMarketDataSource("okx")
+
+	records, err := source.FetchKlineRange("btc", "1h", firstCandleTime, firstCandleTime.Add(2*time.Hour), 100)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 3)
+	assert.Equal(suite.T(), "/okx/market/history-candles", suite.requests[0].URL.Path)
+	assert.Equal(suite.T(), "1735689599999", suite.requests[0].URL.Query().Get("before"))
+	assert.Equal(suite.T(), "1735696800001", suite.requests[0].URL.Query().Get("after"))
+}
+
+func (suite *MarketDataSourceTestSuite) TestOkxShouldReturnApiError() {
+	suite.fixtures["/okx/market/candles"] = "fixtures/okx/error.json"
+	source, _ := exchange.NewMarketDataSource("okx")
+
+	_, err := source.FetchKline("btc", "1h", 3)
+
+	assert.ErrorContains(suite.T(), err, "Instrument ID does not exist")
+}
+
+func (suite *MarketDataSourceTestSuite) TestOkxShouldFetchTicker() {
+	source, _ := exchange.NewMarketDataSource("okx")
+
+	ticker, err := source.FetchTicker("btc")
+
+	assert.NoError(suite.T(), err)
+	assertDecimal(suite.T(), 93577.3, ticker.Open)
+	assertDecimal(suite.T(), 95119.9, ticker.High)
+	assertDecimal(suite.T(), 92879.2, ticker.Low)
+	assertDecimal(suite.T(), 94870.9, ticker.Close)
+	assertDecimal(suite.T(), 5161.82210034, ticker.Volume)
+}
+
+func (suite *MarketDataSourceTestSuite) TestShouldReturnErrorOnHttpFailure() {
+	suite.fixtures = map[string]string{}
+	for _, name := range []string{"coinbase", "kraken", "okx"} {
+		source, _ := exchange.NewMarketDataSource(name)
+		_, err := source.FetchTicker("btc")
+		assert.ErrorContains(suite.T(), err, "returned status: 404", name)
+	}
+}
+
+func (suite *MarketDataSourceTestSuite) assertCandles(records []*dto.DataDto, symbol string, opens []float64, closes []float64) {
+	assert.Len(suite.T(), records, len(opens))
+	for i, record := range records {
+		assert.Equal(suite.T(), symbol, record.Symbol)
+		assert.True(suite.T(), firstCandleTime.Add(time.Duration(i)*time.Hour).Equal(record.Timestamp))
+		assertDecimal(suite.T(), opens[i], record.Open)
+		assertDecimal(suite.T(), closes[i], record.Close)
+		assert.True(suite.T(), record.IsComplete)
+	}
+}
+
+// ✅ Run the Test Suite
+func TestMarketDataSource(t *testing.T) {
+	suite.Run(t, new(MarketDataSourceTestSuite))
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// the services run on STORAGE_BACKEND=memory, no postgres is needed
+type MemoryStorageTestSuite struct {
+	suite.Suite
+	marketDataService *marketdata.MarketDataService
+	indicatorService  *indicator.IndicatorService
+	redisMock         *MockRedisService
+}
+
+func (suite *MemoryStorageTestSuite) SetupSuite() {
+	os.Setenv("STORAGE_BACKEND", "memory")
+}
+
+func (suite *MemoryStorageTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+	memory.DB.Reset()
+}
+
+func (suite *MemoryStorageTestSuite) SetupTest() {
+	memory.DB.Reset()
+	suite.redisMock = &MockRedisService{}
+	suite.redisMock.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
+	suite.marketDataService = marketdata.NewMarketDataService(suite.redisMock)
+	suite.indicatorService = indicator.NewIndicatorService(suite.redisMock)
+}
+
+func (suite *MemoryStorageTestSuite) TestShouldUpsertRecordsByTimestamp() {
+	timestamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := suite.marketDataService.StoreData("btc", memoryCandle(timestamp, 100, 110, true))
+	assert.NoError(suite.T(), err)
+	stored := memory.DB.Candles("btc", "1h")
+	id := *stored[0].Id
+
+	err = suite.marketDataService.UpsertBatchData("btc", []*dto.DataDto{memoryCandle(timestamp, 100, 120, true)})
+	assert.NoError(suite.T(), err)
+
+	stored = memory.DB.Candles("btc", "1h")
+	assert.Len(suite.T(), stored, 1)
+	assert.Equal(suite.T(), id, *stored[0].Id)
+	assertDecimal(suite.T(), 120, stored[0].Close)
+	assertDecimalEqual(suite.T(), calculateTrend(stored[0].Open, stored[0].Close), stored[0].Trend)
+}
+
+func (suite *MemoryStorageTestSuite) TestShouldNotOverwriteExistingRecordWhenStoringSingleRecord() {
+	timestamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(suite.T(), suite.marketDataService.StoreData("btc", memoryCandle(timestamp, 100, 110, true)))
+	assert.NoError(suite.T(), suite.marketDataService.StoreData("btc", memoryCandle(timestamp, 100, 130, true)))
+
+	stored := memory.DB.Candles("btc", "1h")
+	assert.Len(suite.T(), stored, 1)
+	assertDecimal(suite.T(), 110, stored[0].Close)
+}
+
+func (suite *MemoryStorageTestSuite) TestShouldFilterSortAndLimitRecordsByRequest() {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var records []*dto.DataDto
+	for i := 0; i < 5; i++ {
+		records = append(records, memoryCandle(start.Add(time.Duration(i)*time.Hour), 100, 100+float64(i), true))
+	}
+	assert.NoError(suite.T(), suite.marketDataService.UpsertBatchData("btc", records))
+
+	from := start.Add(time.Hour)
+	to := start.Add(3 * time.Hour)
+	result, err := suite.marketDataService.GetRecordsByRequest(dto.OHLCRequestDto{
+		Currency: "btc", Timeframe: "1h", StartTime: &from, EndTime: &to, Limit: 2, SortField: "timestamp", SortOrder: "DESC",
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 2)
+	assert.True(suite.T(), result[0].Timestamp.Equal(to))
+	assert.True(suite.T(), result[1].Timestamp.Equal(start.Add(2*time.Hour)))
+}
+
+func (suite *MemoryStorageTestSuite) TestShouldGroupOneHourRecordsIntoCompleteAndIncompleteGroups() {
+	start := time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)
+	var records []*dto.DataDto
+	for i := 0; i < 6; i++ {
+		records = append(records, memoryCandle(start.Add(time.Duration(i)*time.Hour), 100+float64(i), 101+float64(i), true))
+	}
+	assert.NoError(suite.T(), suite.marketDataService.UpsertBatchData("btc", records))
+
+	assert.NoError(suite.T(), suite.marketDataService.StoreGroupedRecords("btc", "4h"))
+
+	groups := memory.DB.Candles("btc", "4h")
+	assert.Len(suite.T(), groups, 2)
+	assert.True(suite.T(), groups[0].IsComplete)
+	assertDecimal(suite.T(), 100, groups[0].Open)
+	assertDecimal(suite.T(), 104, groups[0].Close)
+	assertDecimal(suite.T(), 4, groups[0].Volume)
+	assert.False(suite.T(), groups[1].IsComplete)
+	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewGroupDataAdded", "MarketPulse")
+}
+
+func (suite *MemoryStorageTestSuite) TestShouldComputeIndicatorsOnlyForUnprocessedRecords() {
+	start := time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)
+	var records []*dto.DataDto
+	for i := 0; i < 8; i++ {
+		records = append(records, memoryCandle(start.Add(time.Duration(i)*time.Hour), 100+float64(i), 101+float64(i), true))
+	}
+	assert.NoError(suite.T(), suite.marketDataService.UpsertBatchData("btc", records))
+	assert.NoError(suite.T(), suite.marketDataService.StoreGroupedRecords("btc", "4h"))
+
+	assert.NoError(suite.T(), suite.indicatorService.ComputeAndUpsertBatch("btc", "4h"))
+
+	indicators := memory.DB.Indicators("btc", "4h")
+	assert.Len(suite.T(), indicators, 2)
+	assert.InDelta(suite.T(), 102.5, indicators[0].SMA, 0.0001)
+	suite.redisMock.AssertCalled(suite.T(), "PublishEvent", mock.Anything, "NewIndicatorAdded", "MarketPulse")
+
+	result, err := suite.indicatorService.GetRecordsByRequest(dto.IndicatorRequestDto{
+		Currency: "btc", Timeframe: "4h", Limit: 1, SortField: "timestamp", SortOrder: "DESC",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), result, 1)
+	assert.True(suite.T(), result[0].Timestamp.Equal(indicators[1].Timestamp))
+}
+
+func (suite *MemoryStorageTestSuite) TestShouldQuarantineAndReleaseRecords() {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	broken := memoryCandle(start, 100, 110, true)
+	broken.High = decimal.NewFromFloat(90)
+
+	accepted, err := suite.marketDataService.ScreenData("btc", []*dto.DataDto{broken})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), accepted)
+
+	quarantined, err := suite.marketDataService.GetQuarantined(dto.QuarantineRequestDto{Currency: "btc"})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), quarantined, 1)
+
+	_, err = suite.marketDataService.ReleaseQuarantined(quarantined[0].Id)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), memory.DB.Candles("btc", "1h"), 1)
+	assert.Empty(suite.T(), memory.DB.Quarantined())
+}
+
+func (suite *MemoryStorageTestSuite) TestShouldQuarantineOnlyFirstCandleOfLevelShiftAcrossRuns() {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var history []*dto.DataDto
+	for i := 0; i < 30; i++ {
+		close := 100.0 + float64(i%2)
+		history = append(history, memoryCandle(start.Add(time.Duration(i)*time.Hour), close, close, true))
+	}
+	assert.NoError(suite.T(), suite.marketDataService.UpsertBatchData("btc", history))
+
+	// the hourly job screens one candle per run, the first candle after a crash is quarantined, the next ones follow it
+	for i := 30; i < 34; i++ {
+		close := 70.0 + float64(i%2)*0.7
+		accepted, err := suite.marketDataService.ScreenData("btc", []*dto.DataDto{memoryCandle(start.Add(time.Duration(i)*time.Hour), close, close, true)})
+		assert.NoError(suite.T(), err)
+		if i == 30 {
+			assert.Empty(suite.T(), accepted)
+			continue
+		}
+		assert.Len(suite.T(), accepted, 1, i)
+		assert.NoError(suite.T(), suite.marketDataService.UpsertBatchData("btc", accepted))
+	}
+	assert.Len(suite.T(), memory.DB.Quarantined(), 1)
+}
+
+func memoryCandle(timestamp time.Time, open float64, close float64, isComplete bool) *dto.DataDto {
+	return &dto.DataDto{
+		Symbol:     "BTCUSDT",
+		Timeframe:  "1h",
+		Timestamp:  timestamp,
+		Open:       decimal.NewFromFloat(open),
+		High:       decimal.NewFromFloat(max(open, close) + 1),
+		Low:        decimal.NewFromFloat(min(open, close) - 1),
+		Close:      decimal.NewFromFloat(close),
+		Volume:     decimal.NewFromFloat(1),
+		IsComplete: isComplete,
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	suite.Run(t, new(MemoryStorageTestSuite))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/orderbook"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type OrderBookTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	query    string
+	requests int
+}
+
+func (suite *OrderBookTestSuite) SetupTest() {
+	depth, _ := os.ReadFile("fixtures/binance/depth.json")
+	suite.requests = 0
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.query = r.URL.RawQuery
+		suite.requests++
+		w.Write(depth)
+	}))
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.URL+"/")
+}
+
+func (suite *OrderBookTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+	os.Unsetenv("STORAGE_BACKEND")
+	clock.SetOffset(0)
+	memory.DB.Reset()
+}
+
+func (suite *OrderBookTestSuite) TestShouldSummarizeLiquidityOfDepthSnapshot() {
+	book, err := binance.FetchDepth("btc", 1000)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "limit=1000&symbol=BTCUSDT", suite.query)
+
+	snapshot := orderbook.Summarize(book)
+
+	assert.Equal(suite.T(), "BTCUSDT", snapshot.Symbol)
+	assertDecimal(suite.T(), 100.0, snapshot.BestBid)
+	assertDecimal(suite.T(), 100.2, snapshot.BestAsk)
+	assertDecimal(suite.T(), 100.1, snapshot.MidPrice)
+	assertDecimal(suite.T(), 0.2, snapshot.Spread)
+
+	assertDecimal(suite.T(), 299.2, snapshot.BidDepth05)
+	assertDecimal(suite.T(), 301.2, snapshot.AskDepth05)
+	assertDecimal(suite.T(), 398.4, snapshot.BidDepth1)
+	assertDecimal(suite.T(), 402.2, snapshot.AskDepth1)
+	assertDecimal(suite.T(), 693.9, snapshot.BidDepth2)
+	assertDecimal(suite.T(), 810.2, snapshot.AskDepth2)
+}
+
+func (suite *OrderBookTestSuite) TestShouldFetchDepthOncePerMinute() {
+	service, redisMock := suite.memoryService()
+
+	// the cron and the NewDataAdded event collect within the same minute
+	first, err := service.CollectSnapshot("btc")
+	assert.NoError(suite.T(), err)
+	second, err := service.CollectSnapshot("btc")
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), 1, suite.requests)
+	assert.Equal(suite.T(), first.Timestamp, second.Timestamp)
+	assertDecimal(suite.T(), 0.2, second.Spread)
+	assert.Len(suite.T(), memory.DB.DepthSnapshots("btc"), 1)
+	redisMock.AssertNumberOfCalls(suite.T(), "PublishEvent", 1)
+}
+
+func (suite *OrderBookTestSuite) TestShouldFetchDepthAgainInNextMinute() {
+	service, redisMock := suite.memoryService()
+
+	_, err := service.CollectSnapshot("btc")
+	assert.NoError(suite.T(), err)
+	clock.SetOffset(clock.Offset() + time.Minute)
+	_, err = service.CollectSnapshot("btc")
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), 2, suite.requests)
+	snapshots, err := service.GetRecordsByRequest(dto.DepthRequestDto{Currency: "btc", Limit: 10, SortOrder: "DESC"})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), snapshots, 2)
+	assert.Equal(suite.T(), time.Minute, snapshots[0].Timestamp.Sub(snapshots[1].Timestamp))
+	redisMock.AssertNumberOfCalls(suite.T(), "PublishEvent", 2)
+}
+
+// memoryService returns an order book service on STORAGE_BACKEND=memory with the clock a second into a minute
+func (suite *OrderBookTestSuite) memoryService() (*orderbook.OrderBookService, *MockRedisService) {
+	os.Setenv("STORAGE_BACKEND", "memory")
+	memory.DB.Reset()
+	now := time.Now().UTC()
+	clock.SetOffset(now.Truncate(time.Minute).Add(time.Second).Sub(now))
+
+	redisMock := &MockRedisService{}
+	redisMock.On("PublishEvent", mock.Anything, config.EVENT_NEW_DEPTH_ADDED, "MarketPulse").Return(nil)
+	return orderbook.NewOrderBookService(redisMock), redisMock
+}
+
+func TestOrderBook(t *testing.T) {
+	suite.Run(t, new(OrderBookTestSuite))
+}
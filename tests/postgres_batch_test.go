@@ -0,0 +1,104 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	indicator "github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// Test Suite Struct, the batch upserts are postgres specific so this suite still needs the database
+type PostgresBatchTestSuite struct {
+	suite.Suite
+	db                *sql.DB
+	marketDataService *marketdata.MarketDataService
+	indicatorService  *indicator.IndicatorService
+}
+
+func (suite *PostgresBatchTestSuite) SetupSuite() {
+	err := database.ConnectDB()
+	if err != nil {
+		log.Fatal("❌ Failed to connect to database:", err)
+	}
+
+	mockRedis := &MockRedisService{}
+	mockRedis.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
+
+	suite.marketDataService = marketdata.NewMarketDataService(mockRedis)
+	suite.indicatorService = indicator.NewIndicatorService(mockRedis)
+	suite.db = database.DB
+}
+
+// Clear DB Before Each Test
+func (suite *PostgresBatchTestSuite) SetupTest() {
+	_, err := suite.db.Exec("DELETE FROM data_btc; DELETE FROM indicator_btc;")
+	if err != nil {
+		log.Fatalf("❌ Failed to clean DB: %v", err)
+	}
+}
+
+// 20800 1h records group to 5200 4h records, both exceed the 65535 bind parameters of a single INSERT
+func (suite *PostgresBatchTestSuite) TestShouldUpsertAndGroupMoreRecordsThanOneStatementAccepts() {
+	records := hourlyRecords(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 20800)
+
+	err := suite.marketDataService.ImportBatchData("btc", records)
+	assert.NoError(suite.T(), err)
+	err = suite.marketDataService.StoreGroupedRecords("btc", "4h")
+	assert.NoError(suite.T(), err)
+
+	var count int
+	assert.NoError(suite.T(), suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_1h`).Scan(&count))
+	assert.Equal(suite.T(), 20800, count)
+	assert.NoError(suite.T(), suite.db.QueryRow(`SELECT COUNT(*) FROM data_btc_4h`).Scan(&count))
+	assert.Equal(suite.T(), 5200, count)
+
+	var close decimal.Decimal
+	assert.NoError(suite.T(), suite.db.QueryRow(`SELECT close FROM data_btc_1h WHERE timestamp = $1`, records[20799].Timestamp).Scan(&close))
+	assertDecimalEqual(suite.T(), records[20799].Close, close)
+}
+
+func (suite *PostgresBatchTestSuite) TestShouldCalculateAndStoreMoreIndicatorsThanOneStatementAccepts() {
+	records := hourlyRecords(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), 20800)
+	assert.NoError(suite.T(), suite.marketDataService.ImportBatchData("btc", records))
+	assert.NoError(suite.T(), suite.marketDataService.StoreGroupedRecords("btc", "4h"))
+
+	err := suite.indicatorService.ComputeAndUpsertBatch("btc", "4h")
+	assert.NoError(suite.T(), err)
+
+	var count int
+	assert.NoError(suite.T(), suite.db.QueryRow(`SELECT COUNT(*) FROM indicator_btc WHERE timeframe = '4h'`).Scan(&count))
+	assert.Equal(suite.T(), 5200, count)
+}
+
+// hourlyRecords returns count complete 1h records starting at start
+func hourlyRecords(start time.Time, count int) []*dto.DataDto {
+	records := make([]*dto.DataDto, count)
+	for i := range records {
+		price := decimal.NewFromInt(int64(100 + i%10))
+		records[i] = &dto.DataDto{
+			Symbol:     "BTCUSDT",
+			Timeframe:  "1h",
+			Timestamp:  start.Add(time.Duration(i) * time.Hour),
+			Open:       price,
+			Close:      price.Add(decimal.NewFromInt(1)),
+			Low:        price.Sub(decimal.NewFromInt(1)),
+			High:       price.Add(decimal.NewFromInt(2)),
+			Volume:     decimal.NewFromInt(2),
+			IsComplete: true,
+		}
+	}
+	return records
+}
+
+func TestPostgresBatch(t *testing.T) {
+	suite.Run(t, new(PostgresBatchTestSuite))
+}
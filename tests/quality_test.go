@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata/quality"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type QualityTestSuite struct {
+	suite.Suite
+	hour time.Time
+}
+
+func (suite *QualityTestSuite) SetupTest() {
+	suite.hour = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *QualityTestSuite) candle(timestamp time.Time, open, high, low, close float64) *dto.DataDto {
+	return &dto.DataDto{Symbol: "BTCUSDT", Timeframe: "1h", Timestamp: timestamp, Open: decimal.NewFromFloat(open), High: decimal.NewFromFloat(high), Low: decimal.NewFromFloat(low), Close: decimal.NewFromFloat(close), Volume: decimal.NewFromFloat(10), IsComplete: true}
+}
+
+func (suite *QualityTestSuite) TestShouldCheckCandleRules() {
+	negativeVolume := suite.candle(suite.hour, 100, 110, 90, 105)
+	negativeVolume.Volume = decimal.NewFromFloat(-1)
+	dayCandle := suite.candle(suite.hour.Add(-12*time.Hour), 100, 110, 90, 105)
+	dayCandle.Timeframe = "1d"
+	nan := suite.candle(suite.hour, 100, 110, 90, 105)
+	nan.High = utils.ParseDecimal("NaN")
+
+	testCases := []struct {
+		name   string
+		record *dto.DataDto
+		reason string
+	}{
+		{name: "valid", record: suite.candle(suite.hour, 100, 110, 90, 105), reason: ""},
+		{name: "zero close of a parse error", record: suite.candle(suite.hour, 100, 110, 90, 0), reason: "non-positive price"},
+		{name: "negative low", record: suite.candle(suite.hour, 100, 110, -1, 105), reason: "non-positive price"},
+		{name: "nan", record: nan, reason: "non-positive price"},
+		{name: "negative volume", record: negativeVolume, reason: "negative volume"},
+		{name: "high below low", record: suite.candle(suite.hour, 100, 90, 110, 105), reason: "high below low"},
+		{name: "high below close", record: suite.candle(suite.hour, 100, 104, 90, 105), reason: "high below open or close"},
+		{name: "low above open", record: suite.candle(suite.hour, 100, 110, 101, 105), reason: "low above open or close"},
+		{name: "unaligned", record: suite.candle(suite.hour.Add(30*time.Minute), 100, 110, 90, 105), reason: "timestamp not aligned to 1h"},
+		{name: "aligned day", record: dayCandle, reason: ""},
+		{name: "future", record: suite.candle(suite.hour.Add(2*time.Hour), 100, 110, 90, 105), reason: "timestamp in the future"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(suite.T(), tc.reason, quality.CheckCandle(tc.record, suite.hour.Add(time.Minute)), tc.name)
+	}
+}
+
+func (suite *QualityTestSuite) TestShouldRejectJumpBeyondMaxSigma() {
+	// closes alternate by about 1%, so a 50% jump is far beyond 10 sigma
+	var history []dto.DataDto
+	for i := 0; i < 30; i++ {
+		close := 100.0
+		if i%2 == 1 {
+			close = 101
+		}
+		history = append(history, *suite.candle(suite.hour.Add(time.Duration(i-30)*time.Hour), close, close, close, close))
+	}
+	checker := quality.NewChecker(history, 10, 100)
+
+	jump := suite.candle(suite.hour, 100, 150, 100, 150)
+	assert.Contains(suite.T(), checker.Check(jump), "sigma from the previous close")
+
+	// the rejected close is not the previous close of the next candle
+	assert.Equal(suite.T(), "", checker.Check(suite.candle(suite.hour.Add(time.Hour), 101, 102, 99, 100)))
+}
+
+func (suite *QualityTestSuite) TestShouldFollowPermanentLevelShift() {
+	checker := quality.NewChecker(suite.alternatingHistory(), 10, 100)
+
+	// the market drops 30% and stays there, only the first candle of the new level is quarantined
+	assert.Contains(suite.T(), checker.Check(suite.candle(suite.hour, 100, 100, 70, 70)), "sigma from the previous close")
+	for i := 1; i < 5; i++ {
+		close := 70.0 + float64(i%2)*0.7
+		assert.Equal(suite.T(), "", checker.Check(suite.candle(suite.hour.Add(time.Duration(i)*time.Hour), close, close, close, close)), i)
+	}
+}
+
+func (suite *QualityTestSuite) TestShouldFollowQuarantinedPreviousCandle() {
+	checker := quality.NewChecker(suite.alternatingHistory(), 10, 100)
+	checker.Follow(suite.candle(suite.hour, 100, 100, 70, 70))
+
+	assert.Equal(suite.T(), "", checker.Check(suite.candle(suite.hour.Add(time.Hour), 70, 71, 70, 71)))
+}
+
+func (suite *QualityTestSuite) TestShouldSkipJumpCheckWithoutEnoughHistory() {
+	history := []dto.DataDto{*suite.candle(suite.hour.Add(-time.Hour), 100, 100, 100, 100)}
+
+	assert.Equal(suite.T(), "", quality.NewChecker(history, 10, 100).Check(suite.candle(suite.hour, 100, 150, 100, 150)))
+	assert.Equal(suite.T(), "", quality.NewChecker(nil, 0, 100).Check(suite.candle(suite.hour, 100, 150, 100, 150)))
+}
+
+// alternatingHistory returns 30 hours of closes alternating by about 1% before suite.hour
+func (suite *QualityTestSuite) alternatingHistory() []dto.DataDto {
+	var history []dto.DataDto
+	for i := 0; i < 30; i++ {
+		close := 100.0 + float64(i%2)
+		history = append(history, *suite.candle(suite.hour.Add(time.Duration(i-30)*time.Hour), close, close, close, close))
+	}
+	return history
+}
+
+func TestQualitySuite(t *testing.T) {
+	suite.Run(t, new(QualityTestSuite))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"time"
 
 	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/redis"
 	"github.com/stretchr/testify/mock"
@@ -22,3 +23,15 @@ func (m *MockRedisService) PublishEvent(ctx context.Context, eventName, source s
 func (m *MockRedisService) SubscribeToEvent(ctx context.Context, eventName string, handler func(event redis.Event)) {
 	m.Called(ctx, eventName, handler)
 }
+
+// Set is a mock implementation that does nothing
+func (m *MockRedisService) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
+	args := m.Called(ctx, key, value, expiration)
+	return args.Error(0)
+}
+
+// Get is a mock implementation that returns the configured value
+func (m *MockRedisService) Get(ctx context.Context, key string) (string, error) {
+	args := m.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResilienceTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	requests atomic.Int32
+	failures atomic.Int32 // number of requests answered with 503 before the server recovers
+	delay    time.Duration
+}
+
+func (suite *ResilienceTestSuite) SetupTest() {
+	suite.requests.Store(0)
+	suite.failures.Store(0)
+	suite.delay = 0
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if suite.requests.Add(1) <= suite.failures.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		time.Sleep(suite.delay)
+		w.Write([]byte("ok"))
+	}))
+}
+
+func (suite *ResilienceTestSuite) TearDownTest() {
+	suite.server.Close()
+	resilience.SetStateChangeListener(nil)
+}
+
+func (suite *ResilienceTestSuite) newClient(breaker *resilience.CircuitBreaker, retries int) *http.Client {
+	return &http.Client{Transport: &resilience.Transport{
+		RealTransport: http.DefaultTransport,
+		Breaker:       breaker,
+		Retries:       retries,
+		BaseDelay:     10 * time.Millisecond,
+		MaxDelay:      50 * time.Millisecond,
+		Timeout:       200 * time.Millisecond,
+	}}
+}
+
+func (suite *ResilienceTestSuite) TestShouldRetryServerErrors() {
+	suite.failures.Store(2)
+	client := suite.newClient(resilience.GetCircuitBreaker("retry-test"), 3)
+
+	resp, err := client.Get(suite.server.URL)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), int32(3), suite.requests.Load())
+}
+
+func (suite *ResilienceTestSuite) TestShouldTimeOutSlowAttempts() {
+	suite.delay = 500 * time.Millisecond
+	client := suite.newClient(resilience.GetCircuitBreaker("timeout-test"), 1)
+
+	_, err := client.Get(suite.server.URL)
+
+	assert.ErrorContains(suite.T(), err, "deadline exceeded")
+	assert.Equal(suite.T(), int32(2), suite.requests.Load())
+}
+
+func (suite *ResilienceTestSuite) TestShouldOpenAndCloseCircuitBreaker() {
+	os.Setenv("CIRCUIT_BREAKER_THRESHOLD", "2")
+	os.Setenv("CIRCUIT_BREAKER_OPEN_SECONDS", "1")
+	defer os.Unsetenv("CIRCUIT_BREAKER_THRESHOLD")
+	defer os.Unsetenv("CIRCUIT_BREAKER_OPEN_SECONDS")
+
+	var mu sync.Mutex
+	var states []resilience.State
+	resilience.SetStateChangeListener(func(name string, state resilience.State) {
+		if name == "breaker-test" {
+			mu.Lock()
+			states = append(states, state)
+			mu.Unlock()
+		}
+	})
+	suite.failures.Store(2)
+	breaker := resilience.GetCircuitBreaker("breaker-test")
+	client := suite.newClient(breaker, 5)
+
+	// two failures open the breaker, the remaining retries are short-circuited
+	_, err := client.Get(suite.server.URL)
+	assert.ErrorIs(suite.T(), err, resilience.ErrCircuitOpen)
+	assert.Equal(suite.T(), resilience.StateOpen, breaker.State())
+	assert.Equal(suite.T(), int32(2), suite.requests.Load())
+
+	// after the open timeout the trial call closes it again
+	time.Sleep(1100 * time.Millisecond)
+	resp, err := client.Get(suite.server.URL)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), resilience.StateClosed, breaker.State())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(suite.T(), []resilience.State{resilience.StateOpen, resilience.StateClosed}, states)
+}
+
+func TestResilience(t *testing.T) {
+	suite.Run(t, new(ResilienceTestSuite))
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/retention"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/archive"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/memory"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/utils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// retention runs on STORAGE_BACKEND=memory with 1h candles kept for 30 days and an archive in a temporary directory
+type RetentionTestSuite struct {
+	suite.Suite
+	archiveDir        string
+	marketDataService *marketdata.MarketDataService
+	retentionService  *retention.RetentionService
+	now               time.Time
+}
+
+func (suite *RetentionTestSuite) SetupSuite() {
+	os.Setenv("STORAGE_BACKEND", "memory")
+	os.Setenv("RETENTION_POLICIES", "1h:30d,1d:forever")
+}
+
+func (suite *RetentionTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+	os.Unsetenv("RETENTION_POLICIES")
+	os.Unsetenv("ARCHIVE_DIR")
+	memory.DB.Reset()
+}
+
+func (suite *RetentionTestSuite) SetupTest() {
+	memory.DB.Reset()
+	suite.archiveDir = suite.T().TempDir()
+	os.Setenv("ARCHIVE_DIR", suite.archiveDir)
+
+	redisMock := &MockRedisService{}
+	redisMock.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
+	suite.marketDataService = marketdata.NewMarketDataService(redisMock)
+	suite.retentionService = retention.NewRetentionService(suite.marketDataService, indicator.NewIndicatorService(redisMock))
+	suite.now = time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+func (suite *RetentionTestSuite) TestShouldParseRetentionPeriods() {
+	for value, expected := range map[string]time.Duration{"90d": 90 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "2y": 730 * 24 * time.Hour, "36h": 36 * time.Hour} {
+		retention, err := utils.ParseRetention(value)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), expected, retention, value)
+	}
+	for _, value := range []string{"", "d", "-1d", "0d", "abc"} {
+		_, err := utils.ParseRetention(value)
+		assert.Error(suite.T(), err, value)
+	}
+}
+
+func (suite *RetentionTestSuite) TestShouldRoundTripCandlesThroughParquetArchive() {
+	store := archive.NewArchive(suite.archiveDir)
+	start := time.Date(2024, 1, 31, 22, 0, 0, 0, time.UTC)
+	first := *memoryCandle(start, 100.123456789, 101, true)
+	second := *memoryCandle(start.Add(3*time.Hour), 102, 103, false)
+	first.QuoteVolume = decimal.RequireFromString("12345.000000001")
+	first.TradeCount = 42
+
+	assert.NoError(suite.T(), store.WriteCandles("btc", "1h", []dto.DataDto{first, second}))
+	assert.FileExists(suite.T(), filepath.Join(suite.archiveDir, "btc", "1h", "data_2024-01.parquet"))
+	assert.FileExists(suite.T(), filepath.Join(suite.archiveDir, "btc", "1h", "data_2024-02.parquet"))
+
+	// writing a candle again replaces the archived one
+	replaced := first
+	replaced.Close = decimal.NewFromFloat(99)
+	assert.NoError(suite.T(), store.WriteCandles("btc", "1h", []dto.DataDto{replaced}))
+
+	records, err := store.ReadCandles("btc", "1h", nil, nil, -1, false)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 2)
+	assert.True(suite.T(), records[0].Timestamp.Equal(start))
+	assertDecimalEqual(suite.T(), first.Open, records[0].Open)
+	assertDecimal(suite.T(), 99, records[0].Close)
+	assertDecimalEqual(suite.T(), first.QuoteVolume, records[0].QuoteVolume)
+	assert.Equal(suite.T(), int64(42), records[0].TradeCount)
+	assert.False(suite.T(), records[1].IsComplete)
+
+	from := start.Add(time.Hour)
+	records, err = store.ReadCandles("btc", "1h", &from, nil, -1, false)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 1)
+	assert.True(suite.T(), records[0].Timestamp.Equal(second.Timestamp))
+}
+
+func (suite *RetentionTestSuite) TestShouldStopReadingArchivedMonthsOnceLimitIsReached() {
+	store := archive.NewArchive(suite.archiveDir)
+	january := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+	assert.NoError(suite.T(), store.WriteCandles("btc", "1h", []dto.DataDto{
+		*memoryCandle(january, 100, 101, true),
+		*memoryCandle(february, 102, 103, true),
+		*memoryCandle(february.Add(time.Hour), 103, 104, true),
+	}))
+	// a month that has to be decoded fails the read
+	assert.NoError(suite.T(), os.WriteFile(filepath.Join(suite.archiveDir, "btc", "1h", "data_2024-01.parquet"), []byte("broken"), 0o644))
+
+	records, err := store.ReadCandles("btc", "1h", nil, nil, 2, true)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 2)
+	assert.True(suite.T(), records[0].Timestamp.Equal(february.Add(time.Hour)))
+	assert.True(suite.T(), records[1].Timestamp.Equal(february))
+
+	_, err = store.ReadCandles("btc", "1h", nil, nil, 3, true)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *RetentionTestSuite) TestShouldArchiveExpiredRecordsMonthByMonth() {
+	expiredStart := suite.now.Add(-100 * 24 * time.Hour)
+	suite.storeHours(expiredStart, 70*24)
+
+	archived, err := suite.marketDataService.ArchiveRecordsBefore("btc", "1h", suite.now.Add(-30*24*time.Hour))
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(70*24), archived)
+	assert.Empty(suite.T(), memory.DB.Candles("btc", "1h"))
+	files, err := filepath.Glob(filepath.Join(suite.archiveDir, "btc", "1h", "data_*.parquet"))
+	assert.NoError(suite.T(), err)
+	assert.GreaterOrEqual(suite.T(), len(files), 3)
+
+	records, err := archive.NewArchive(suite.archiveDir).ReadCandles("btc", "1h", nil, nil, -1, false)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 70*24)
+	// every hour of volume made it into a 1d group, none was lost at a month boundary
+	volume := decimal.Zero
+	for _, group := range memory.DB.Candles("btc", "1d") {
+		volume = volume.Add(group.Volume)
+	}
+	assertDecimal(suite.T(), 70*24, volume)
+}
+
+func (suite *RetentionTestSuite) TestShouldArchiveAndDeleteExpiredRecordsAndKeepDownsampledGroups() {
+	suite.storeHours(suite.now.Add(-40*24*time.Hour), 48)
+	suite.storeHours(suite.now.Add(-2*24*time.Hour), 24)
+	expiredIndicator := &dto.IndicatorDto{Timeframe: "1h", Timestamp: suite.now.Add(-40 * 24 * time.Hour), DataTimestamp: suite.now.Add(-40 * 24 * time.Hour), SMA: 100.5}
+	keptIndicator := &dto.IndicatorDto{Timeframe: "1h", Timestamp: suite.now.Add(-24 * time.Hour), DataTimestamp: suite.now.Add(-24 * time.Hour), SMA: 101.5}
+	memory.DB.UpsertIndicators("btc", "1h", []*dto.IndicatorDto{expiredIndicator, keptIndicator})
+
+	reports, err := suite.retentionService.Apply("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), reports, 1)
+	assert.Equal(suite.T(), "1h", reports[0].Timeframe)
+	assert.Equal(suite.T(), int64(48), reports[0].ArchivedRecords)
+	assert.Equal(suite.T(), int64(1), reports[0].ArchivedIndicators)
+
+	assert.Len(suite.T(), memory.DB.Candles("btc", "1h"), 24)
+	assert.Len(suite.T(), memory.DB.Indicators("btc", "1h"), 1)
+	// the expired hours live on as 4h and 1d groups
+	assert.NotEmpty(suite.T(), memory.DB.Candles("btc", "4h"))
+	assert.NotEmpty(suite.T(), memory.DB.Candles("btc", "1d"))
+
+	indicators, err := archive.NewArchive(suite.archiveDir).ReadIndicators("btc", "1h", nil, nil)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), indicators, 1)
+	assert.InDelta(suite.T(), 100.5, indicators[0].SMA, 0.0001)
+
+	// applying again finds nothing new
+	reports, err = suite.retentionService.Apply("btc")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), reports[0].ArchivedRecords)
+}
+
+func (suite *RetentionTestSuite) TestShouldReadArchivedRangesBackByRequest() {
+	expiredStart := suite.now.Add(-40 * 24 * time.Hour)
+	suite.storeHours(expiredStart, 10)
+	suite.storeHours(suite.now.Add(-2*24*time.Hour), 10)
+	_, err := suite.retentionService.Apply("btc")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), memory.DB.Candles("btc", "1h"), 10)
+
+	from := expiredStart.Add(2 * time.Hour)
+	to := expiredStart.Add(5 * time.Hour)
+	records, err := suite.marketDataService.GetRecordsByRequest(dto.OHLCRequestDto{
+		Currency: "btc", Timeframe: "1h", StartTime: &from, EndTime: &to, Limit: 100, SortField: "timestamp", SortOrder: "ASC",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 4)
+	assert.True(suite.T(), records[0].Timestamp.Equal(from))
+
+	// an unbounded request merges both and applies the limit across them
+	records, err = suite.marketDataService.GetRecordsByRequest(dto.OHLCRequestDto{
+		Currency: "btc", Timeframe: "1h", Limit: 15, SortField: "timestamp", SortOrder: "ASC",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 15)
+	assert.True(suite.T(), records[0].Timestamp.Equal(expiredStart))
+	assert.True(suite.T(), records[10].Timestamp.Equal(suite.now.Add(-2*24*time.Hour)))
+
+	// the latest records are served by storage alone
+	records, err = suite.marketDataService.GetRecordsByRequest(dto.OHLCRequestDto{
+		Currency: "btc", Timeframe: "1h", Limit: 5, SortField: "timestamp", SortOrder: "DESC",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 5)
+	assert.True(suite.T(), records[4].Timestamp.After(suite.now.Add(-3*24*time.Hour)))
+}
+
+func (suite *RetentionTestSuite) TestShouldReadArchiveOnlyBeforeTheFirstStoredRecord() {
+	expiredStart := suite.now.Add(-40 * 24 * time.Hour)
+	suite.storeHours(expiredStart, 10)
+	_, err := suite.retentionService.Apply("btc")
+	assert.NoError(suite.T(), err)
+	// a candle stored again after it was archived is served from storage, later archived months are never read
+	suite.storeHours(expiredStart.Add(5*time.Hour), 1)
+	assert.NoError(suite.T(), os.WriteFile(filepath.Join(suite.archiveDir, "btc", "1h", "data_"+suite.now.Format("2006-01")+".parquet"), []byte("broken"), 0o644))
+
+	records, err := suite.marketDataService.GetRecordsByRequest(dto.OHLCRequestDto{
+		Currency: "btc", Timeframe: "1h", Limit: 100, SortField: "timestamp", SortOrder: "ASC",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 6)
+	assert.True(suite.T(), records[0].Timestamp.Equal(expiredStart))
+	assert.True(suite.T(), records[5].Timestamp.Equal(expiredStart.Add(5*time.Hour)))
+}
+
+func (suite *RetentionTestSuite) storeHours(start time.Time, hours int) {
+	var records []*dto.DataDto
+	for i := 0; i < hours; i++ {
+		records = append(records, memoryCandle(start.Add(time.Duration(i)*time.Hour), 100+float64(i), 101+float64(i), true))
+	}
+	assert.NoError(suite.T(), suite.marketDataService.UpsertBatchData("btc", records))
+}
+
+func TestRetention(t *testing.T) {
+	suite.Run(t, new(RetentionTestSuite))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/validator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/instrument"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SymbolTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+func (suite *SymbolTestSuite) SetupTest() {
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture := "fixtures/binance/exchangeInfo.json"
+		if strings.HasSuffix(r.URL.Path, "/ticker/24hr") {
+			fixture = "fixtures/binance/tickers.json"
+		}
+		content, err := os.ReadFile(fixture)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	}))
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.URL+"/api/v3/")
+}
+
+func (suite *SymbolTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+	instrument.SetStatuses(map[string]string{})
+}
+
+func (suite *SymbolTestSuite) TestShouldFetchExchangeInfo() {
+	records, err := binance.FetchExchangeInfo()
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), records, 2)
+	assert.Equal(suite.T(), "BTCUSDT", records[0].Symbol)
+	assert.Equal(suite.T(), "BTC", records[0].BaseAsset)
+	assert.Equal(suite.T(), "USDT", records[0].QuoteAsset)
+	assert.Equal(suite.T(), "TRADING", records[0].Status)
+	assertDecimal(suite.T(), 0.01, records[0].TickSize)
+	assertDecimal(suite.T(), 0.00001, records[0].StepSize)
+	assertDecimal(suite.T(), 0.00001, records[0].MinQty)
+	assertDecimal(suite.T(), 5.0, records[0].MinNotional)
+	assert.Equal(suite.T(), "BREAK", records[1].Status)
+	assertDecimal(suite.T(), 0.0, records[1].MinNotional)
+}
+
+func (suite *SymbolTestSuite) TestShouldFetchQuoteVolumes() {
+	volumes, err := binance.FetchQuoteVolumes()
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), volumes, 2)
+	assertDecimal(suite.T(), 1424563211.55, volumes["BTCUSDT"])
+	assertDecimal(suite.T(), 410, volumes["LUNAUSDT"])
+}
+
+func (suite *SymbolTestSuite) TestShouldRejectCurrencyThatIsNotTrading() {
+	validator := validator.NewValidator()
+	assert.NoError(suite.T(), validator.ValidateTradable("btc"), "a symbol that is not synced yet is tradable")
+
+	instrument.SetStatuses(map[string]string{"btc": "BREAK"})
+	err := validator.ValidateTradable("btc")
+
+	assert.EqualError(suite.T(), err, "BTCUSDT is not trading: BREAK")
+	assert.NoError(suite.T(), validator.ValidateCurrency("btc"), "history of a halted symbol can still be queried")
+}
+
+func (suite *SymbolTestSuite) TestShouldNotGateCurrenciesOfOtherExchangesByBinanceStatus() {
+	instrument.SetStatuses(map[string]string{"btc": "BREAK"})
+	validator := validator.NewValidator()
+
+	os.Setenv("MARKET_DATA_SOURCES", "btc:kraken")
+	assert.NoError(suite.T(), validator.ValidateTradable("btc"), "btc is fetched from kraken")
+
+	os.Unsetenv("MARKET_DATA_SOURCES")
+	assert.EqualError(suite.T(), validator.ValidateTradable("btc"), "BTCUSDT is not trading: BREAK")
+}
+
+func TestSymbolSuite(t *testing.T) {
+	suite.Run(t, new(SymbolTestSuite))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/ticker"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TickerTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	query  string
+}
+
+func (suite *TickerTestSuite) SetupTest() {
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.query = r.URL.RawQuery
+		fixture, err := os.ReadFile("fixtures/binance/ticker.json")
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(fixture)
+	}))
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.URL+"/api/v3/")
+}
+
+func (suite *TickerTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+}
+
+func (suite *TickerTestSuite) TestShouldFetchTickerSnapshot() {
+	record, err := binance.FetchTickerSnapshot("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "symbol=BTCUSDT", suite.query)
+	assert.Equal(suite.T(), "BTCUSDT", record.Symbol)
+	assert.Equal(suite.T(), time.UnixMilli(1735690215123).UTC(), record.Timestamp)
+	assertDecimal(suite.T(), 93505.0, record.LastPrice)
+	assertDecimal(suite.T(), 93599.99, record.OpenPrice)
+	assertDecimal(suite.T(), 94200.0, record.HighPrice)
+	assertDecimal(suite.T(), 92800.1, record.LowPrice)
+	assertDecimal(suite.T(), -94.999998, record.PriceChange)
+	assertDecimal(suite.T(), -0.101, record.PriceChangePercent)
+	assertDecimal(suite.T(), 93512.55, record.WeightedAvgPrice)
+	assertDecimal(suite.T(), 15234.12, record.Volume)
+	assertDecimal(suite.T(), 1424563211.55, record.QuoteVolume)
+	assert.Equal(suite.T(), int64(76), record.TradeCount)
+}
+
+func (suite *TickerTestSuite) TestShouldMapTickerToHourlyCandle() {
+	record, err := binance.FetchTicker("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1h", record.Timeframe)
+	assertDecimal(suite.T(), 93505.0, record.Close)
+	assertDecimal(suite.T(), 93512.55, record.Vwap)
+	assert.Equal(suite.T(), int64(76), record.TradeCount)
+}
+
+func (suite *TickerTestSuite) TestShouldServeLatestTickerFromRedis() {
+	cached := dto.TickerDto{
+		Symbol:    "BTCUSDT",
+		Timestamp: time.Date(2025, 1, 1, 0, 10, 0, 0, time.UTC),
+		LastPrice: decimal.NewFromInt(93505),
+		Volume:    decimal.NewFromFloat(15234.12),
+	}
+	value, _ := json.Marshal(cached)
+	redisMock := new(MockRedisService)
+	redisMock.On("Get", mock.Anything, "ticker:btc").Return(string(value), nil)
+
+	record, err := ticker.NewTickerService(redisMock).GetLatest("btc")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), cached.Symbol, record.Symbol)
+	assert.Equal(suite.T(), cached.Timestamp, record.Timestamp)
+	assertDecimalEqual(suite.T(), cached.LastPrice, record.LastPrice)
+	assertDecimalEqual(suite.T(), cached.Volume, record.Volume)
+	assert.True(suite.T(), record.QuoteVolume.IsZero())
+	redisMock.AssertExpectations(suite.T())
+}
+
+func (suite *TickerTestSuite) TestShouldRejectUnknownCurrency() {
+	redisMock := new(MockRedisService)
+
+	_, err := ticker.NewTickerService(redisMock).GetLatest("unknown")
+
+	assert.Error(suite.T(), err)
+	redisMock.AssertNotCalled(suite.T(), "Get", mock.Anything, mock.Anything)
+}
+
+func TestTickerSuite(t *testing.T) {
+	suite.Run(t, new(TickerTestSuite))
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/config"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/indicator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	aggregator "github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata/aggregator"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/database"
+	"github.com/chyngyz-sydykov/marketpulse/internal/pkg/clock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TimescaleTestSuite struct {
+	suite.Suite
+}
+
+func (suite *TimescaleTestSuite) TearDownTest() {
+	os.Unsetenv("STORAGE_BACKEND")
+}
+
+func (suite *TimescaleTestSuite) TestShouldAggregateOnlyGroupedTimeframes() {
+	assert.False(suite.T(), database.IsGroupedTimeframe("1h"))
+	assert.False(suite.T(), database.IsGroupedTimeframe("1h_composite"))
+	assert.True(suite.T(), database.IsGroupedTimeframe("4h"))
+	assert.True(suite.T(), database.IsGroupedTimeframe("1d"))
+	assert.True(suite.T(), database.IsGroupedTimeframe("4h_composite"))
+}
+
+func (suite *TimescaleTestSuite) TestShouldReadOneHourCandlesFromTheHypertable() {
+	assert.Equal(suite.T(), "ts_data_btc_1h", database.TimescaleCandleSource("btc", "1h"))
+}
+
+func (suite *TimescaleTestSuite) TestShouldLabelGroupsWithTheirLastHour() {
+	source := database.TimescaleCandleSource("eth_btc", "1d")
+
+	assert.Contains(suite.T(), source, "FROM ts_data_eth_btc_1d")
+	assert.Contains(suite.T(), source, "bucket + INTERVAL '23 hours' AS timestamp")
+	assert.Contains(suite.T(), source, "last_timestamp = bucket + INTERVAL '23 hours' AS is_complete")
+}
+
+func (suite *TimescaleTestSuite) TestShouldReadCandleTablesOfTheStorageBackend() {
+	assert.Equal(suite.T(), "data_btc_1h", database.CandleTable("btc", "1h"))
+
+	os.Setenv("STORAGE_BACKEND", "timescale")
+	assert.Equal(suite.T(), "ts_data_btc_1h", database.CandleTable("btc", "1h"))
+	assert.True(suite.T(), strings.HasPrefix(database.CandleTable("btc", "4h"), "(SELECT"))
+}
+
+func TestTimescale(t *testing.T) {
+	suite.Run(t, new(TimescaleTestSuite))
+}
+
+// Test Suite Struct, the continuous aggregates need a postgres with the timescaledb extension, the suite is skipped without one
+type TimescaleAggregateTestSuite struct {
+	suite.Suite
+	marketDataService *marketdata.MarketDataService
+	aggregator        *aggregator.Aggregator
+}
+
+func (suite *TimescaleAggregateTestSuite) SetupSuite() {
+	os.Setenv("STORAGE_BACKEND", "timescale")
+	if err := database.ConnectDB(); err != nil {
+		suite.T().Skipf("postgres is not available: %v", err)
+	}
+	if _, err := database.DB.Exec(`CREATE EXTENSION IF NOT EXISTS timescaledb`); err != nil {
+		suite.T().Skipf("timescaledb is not available: %v", err)
+	}
+	suite.Require().NoError(database.EnsureInstrumentTables("btc"))
+
+	mockRedis := &MockRedisService{}
+	mockRedis.On("PublishEvent", mock.Anything, mock.Anything, "MarketPulse").Return(nil)
+
+	indicatorService := indicator.NewIndicatorService(mockRedis)
+	suite.marketDataService = marketdata.NewMarketDataService(mockRedis)
+	suite.aggregator = aggregator.NewAggregator(indicatorService)
+}
+
+func (suite *TimescaleAggregateTestSuite) TearDownSuite() {
+	os.Unsetenv("STORAGE_BACKEND")
+}
+
+// Clear the hypertable and its continuous aggregates before each test
+func (suite *TimescaleAggregateTestSuite) SetupTest() {
+	_, err := database.DB.Exec("DELETE FROM ts_data_btc_1h")
+	suite.Require().NoError(err)
+	for _, timeframe := range []string{config.FOUR_HOUR, config.ONE_DAY} {
+		suite.Require().NoError(database.RefreshTimescaleGroups("btc", timeframe, nil, nil))
+	}
+}
+
+// three complete days and six hours of the fourth one, so the last 4h and 1d groups are still forming
+func (suite *TimescaleAggregateTestSuite) TestShouldGroupLikeTheAggregator() {
+	start := clock.Now().Truncate(24 * time.Hour).Add(-4*24*time.Hour + time.Hour)
+	records := timescaleHourlyRecords(start, 3*24+6)
+	assert.NoError(suite.T(), suite.marketDataService.ImportBatchData("btc", records))
+
+	oneHourRecords := make([]dto.DataDto, len(records))
+	for i, record := range records {
+		oneHourRecords[i] = *record
+	}
+	last := records[len(records)-1].Timestamp
+
+	for _, timeframe := range []string{config.FOUR_HOUR, config.ONE_DAY} {
+		assert.NoError(suite.T(), suite.marketDataService.StoreGroupedRecordsBetween("btc", timeframe, start, last))
+
+		expected, err := suite.aggregator.GroupRecords(oneHourRecords, config.HoursByTimeframe[timeframe], timeframe)
+		assert.NoError(suite.T(), err)
+		actual, err := suite.marketDataService.GetRecordsByRequest(dto.OHLCRequestDto{
+			Currency:  "btc",
+			Timeframe: timeframe,
+			Limit:     100,
+			SortField: "timestamp",
+			SortOrder: "ASC",
+		})
+		assert.NoError(suite.T(), err)
+
+		if !assert.Len(suite.T(), actual, len(expected), timeframe) {
+			continue
+		}
+		for i, group := range expected {
+			suite.assertGroup(*group, actual[i])
+		}
+	}
+}
+
+func (suite *TimescaleAggregateTestSuite) assertGroup(expected dto.DataDto, actual dto.DataDto) {
+	assert.Truef(suite.T(), expected.Timestamp.Equal(actual.Timestamp), "expected %s group at %s, actual %s", expected.Timeframe, expected.Timestamp, actual.Timestamp)
+	assert.Equal(suite.T(), expected.Timeframe, actual.Timeframe)
+	assert.Equal(suite.T(), expected.Symbol, actual.Symbol)
+	assert.Equal(suite.T(), expected.IsComplete, actual.IsComplete, expected.Timestamp)
+	assertDecimalEqual(suite.T(), expected.Open, actual.Open)
+	assertDecimalEqual(suite.T(), expected.High, actual.High)
+	assertDecimalEqual(suite.T(), expected.Low, actual.Low)
+	assertDecimalEqual(suite.T(), expected.Close, actual.Close)
+	assertDecimalEqual(suite.T(), expected.Volume, actual.Volume)
+	assertDecimalEqual(suite.T(), expected.Trend, actual.Trend)
+	assertDecimalEqual(suite.T(), expected.QuoteVolume, actual.QuoteVolume)
+	assert.Equal(suite.T(), expected.TradeCount, actual.TradeCount)
+	assertDecimalEqual(suite.T(), expected.TakerBuyBaseVolume, actual.TakerBuyBaseVolume)
+	assertDecimalEqual(suite.T(), expected.TakerBuyQuoteVolume, actual.TakerBuyQuoteVolume)
+	assertDecimalEqual(suite.T(), expected.MedianPrice, actual.MedianPrice)
+	// postgres and decimal divide with a different precision
+	assertDecimalEqual(suite.T(), expected.Vwap.Round(8), actual.Vwap.Round(8))
+}
+
+// timescaleHourlyRecords varies every column the continuous aggregates sum or pick from the first or last hour
+func timescaleHourlyRecords(start time.Time, count int) []*dto.DataDto {
+	records := hourlyRecords(start, count)
+	for i, record := range records {
+		volume := decimal.NewFromInt(int64(1 + i%7))
+		record.Volume = volume
+		record.QuoteVolume = volume.Mul(record.Close)
+		record.TradeCount = int64(10 + i%5)
+		record.TakerBuyBaseVolume = volume.Div(decimal.NewFromInt(2))
+		record.TakerBuyQuoteVolume = record.QuoteVolume.Div(decimal.NewFromInt(2))
+		record.Vwap = record.Close
+		record.MedianPrice = record.Open.Add(record.Close).Div(decimal.NewFromInt(2))
+	}
+	return records
+}
+
+func TestTimescaleAggregate(t *testing.T) {
+	suite.Run(t, new(TimescaleAggregateTestSuite))
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/marketdata"
+	"github.com/chyngyz-sydykov/marketpulse/internal/core/trade"
+	"github.com/chyngyz-sydykov/marketpulse/internal/dto"
+	"github.com/chyngyz-sydykov/marketpulse/internal/infrastructure/binance"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// the first kline matches the fixture trades, the second hour has no trades
+var tradeKlines = `[[1735689600000,"100.00000000","102.00000000","99.00000000","101.00000000","4.50000000",1735693199999,"453.50000000",7,"2.00000000","199.00000000","0"],` +
+	`[1735693200000,"101.00000000","101.00000000","101.00000000","101.00000000","0.00000000",1735696799999,"0.00000000",0,"0.00000000","0.00000000","0"]]`
+
+type TradeCandleTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	hour   time.Time
+}
+
+func (suite *TradeCandleTestSuite) SetupTest() {
+	suite.hour = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fixture, _ := os.ReadFile("fixtures/binance/aggTrades.json")
+	var trades []dto.BinanceAggTrade
+	json.Unmarshal(fixture, &trades)
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/klines" {
+			w.Write([]byte(tradeKlines))
+			return
+		}
+		startTime, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+		endTime, _ := strconv.ParseInt(r.URL.Query().Get("endTime"), 10, 64)
+		window := []dto.BinanceAggTrade{}
+		for _, trade := range trades {
+			if trade.Time >= startTime && trade.Time <= endTime {
+				window = append(window, trade)
+			}
+		}
+		json.NewEncoder(w).Encode(window)
+	}))
+	os.Setenv("BINANCE_BASE_API_URL", suite.server.URL+"/")
+}
+
+func (suite *TradeCandleTestSuite) TearDownTest() {
+	suite.server.Close()
+	os.Unsetenv("BINANCE_BASE_API_URL")
+}
+
+func (suite *TradeCandleTestSuite) TestShouldBuildHourCandleWithVwapAndBuySellSplit() {
+	trades, err := binance.FetchAggTrades("btc", suite.hour, suite.hour.Add(time.Hour))
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), trades, 4)
+
+	candles := trade.BuildCandles("BTCUSDT", "1h", time.Hour, trades, suite.hour.Add(2*time.Hour))
+
+	assert.Len(suite.T(), candles, 1)
+	candle := candles[0]
+	assert.Equal(suite.T(), suite.hour, candle.Timestamp)
+	assertDecimal(suite.T(), 100.0, candle.Open)
+	assertDecimal(suite.T(), 102.0, candle.High)
+	assertDecimal(suite.T(), 99.0, candle.Low)
+	assertDecimal(suite.T(), 101.0, candle.Close)
+	assertDecimal(suite.T(), 4.5, candle.Volume)
+	assertDecimal(suite.T(), 453.5, candle.QuoteVolume)
+	assert.Equal(suite.T(), int64(7), candle.TradeCount)
+	assertDecimal(suite.T(), 2.0, candle.TakerBuyBaseVolume)
+	assertDecimal(suite.T(), 199.0, candle.TakerBuyQuoteVolume)
+	assert.InDelta(suite.T(), 453.5/4.5, candle.Vwap.InexactFloat64(), 1e-9)
+	assert.True(suite.T(), candle.IsComplete)
+}
+
+func (suite *TradeCandleTestSuite) TestShouldBuildMinuteCandles() {
+	trades, err := binance.FetchAggTrades("btc", suite.hour, suite.hour.Add(time.Hour))
+	assert.NoError(suite.T(), err)
+
+	// the last minute is still forming
+	candles := trade.BuildCandles("BTCUSDT", "1m", time.Minute, trades, suite.hour.Add(59*time.Minute+30*time.Second))
+
+	assert.Len(suite.T(), candles, 3)
+	assert.Equal(suite.T(), suite.hour, candles[0].Timestamp)
+	assertDecimal(suite.T(), 3.0, candles[0].Volume)
+	assertDecimal(suite.T(), 102.0, candles[0].Close)
+	assert.Equal(suite.T(), suite.hour.Add(time.Minute), candles[1].Timestamp)
+	assert.True(suite.T(), candles[1].IsComplete)
+	assert.Equal(suite.T(), suite.hour.Add(59*time.Minute), candles[2].Timestamp)
+	assert.False(suite.T(), candles[2].IsComplete)
+}
+
+func (suite *TradeCandleTestSuite) TestShouldSumVolumesWithoutFloatDrift() {
+	trades := []dto.AggTradeDto{
+		{Id: 1, Price: decimal.RequireFromString("0.1"), Quantity: decimal.RequireFromString("0.1"), TradeCount: 1, Timestamp: suite.hour},
+		{Id: 2, Price: decimal.RequireFromString("0.2"), Quantity: decimal.RequireFromString("0.2"), TradeCount: 1, Timestamp: suite.hour.Add(time.Minute)},
+	}
+
+	candles := trade.BuildCandles("BTCUSDT", "1h", time.Hour, trades, suite.hour.Add(2*time.Hour))
+
+	assert.Equal(suite.T(), "0.3", candles[0].Volume.String())
+	assert.Equal(suite.T(), "0.05", candles[0].QuoteVolume.String())
+}
+
+func (suite *TradeCandleTestSuite) TestShouldReconcileTradeCandlesWithKlines() {
+	service := trade.NewTradeService(marketdata.NewMarketDataService(&MockRedisService{}))
+
+	report, err := service.Reconcile("btc", suite.hour, suite.hour.Add(2*time.Hour))
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, report.MatchedHours)
+	assert.Equal(suite.T(), 1, report.MismatchedHours)
+	assert.Len(suite.T(), report.Rows, 2)
+	assert.Empty(suite.T(), report.Rows[0].Mismatches)
+	assert.Equal(suite.T(), []string{"missing trades"}, report.Rows[1].Mismatches)
+}
+
+func TestTradeCandle(t *testing.T) {
+	suite.Run(t, new(TradeCandleTestSuite))
+}